@@ -5,6 +5,7 @@ import (
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/bot"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/connections/db"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/connections/grpc"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/env"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
@@ -27,6 +28,12 @@ type DependencyContainer interface {
 	GetSdxTradeAPI() bot.TradeAPI
 	//GetProdTradeAPI returns production trade API instance
 	GetProdTradeAPI() bot.TradeAPI
+	//GetStrategyAPI returns strategy API instance
+	GetStrategyAPI() bot.StrategyAPI
+	//GetRiskAPI returns risk rules API instance
+	GetRiskAPI() bot.RiskAPI
+	//GetHaltAPI returns trading halt API instance
+	GetHaltAPI() bot.HaltAPI
 }
 
 var dc depContainerImpl
@@ -67,18 +74,31 @@ func initConfigurationWithLogger(sugared *zap.SugaredLogger) {
 
 	hRep := repository.NewHistoryRepository(db.GetDB())
 	actionRep := repository.NewActionRepository(db.GetDB())
+	stopRep := repository.NewStopOrderRepository(db.GetDB())
+	riskRep := repository.NewRiskRuleRepository(db.GetDB())
+	budgetRep := repository.NewBudgetRepository(db.GetDB())
 	aRep := repository.NewAlgoRepository(db.GetDB())
 	statRep := repository.NewStatRepository(db.GetDB())
 
 	statSrv := service.NewStatService(statRep, sugared)
-	aFact := strategy.NewAlgFactory(infoSdxSrv, infoProdSrv, hRep, sugared)
-	sdxTrader := trade.NewSandboxTrader(infoSdxSrv, tradeSdxSrv, actionRep, sugared)
-	prodTrader := trade.NewProdTrader(infoProdSrv, tradeProdSrv, actionRep, sugared)
+	aFact := strategy.NewAlgFactory(infoSdxSrv, infoProdSrv, hRep, aRep, sugared)
+	sdxRiskMgr := trade.NewRiskManager(entity.SandboxEnv, infoSdxSrv, riskRep, sugared)
+	prodRiskMgr := trade.NewRiskManager(entity.ProdEnv, infoProdSrv, riskRep, sugared)
+	budgetMgr := trade.NewBudgetManager(budgetRep, sugared)
+	sdxTrader := trade.NewSandboxTrader(infoSdxSrv, tradeSdxSrv, actionRep, stopRep, sdxRiskMgr, budgetMgr, sugared)
+	prodTrader := trade.NewProdTrader(infoProdSrv, tradeProdSrv, actionRep, stopRep, prodRiskMgr, budgetMgr, sugared)
 
 	historyAPI := bot.NewHistoryAPI(infoSdxSrv, hRep, aFact, aRep, sugared)
 	sdxTradeAPI := bot.NewSandboxTradeAPI(infoSdxSrv, aFact, aRep, sdxTrader, sugared)
-	prodTradeAPI := bot.NewTradeProdAPI(infoProdSrv, aFact, aRep, prodTrader, sugared)
+	halted := abool.NewBool(false)
+	prodTradeAPI := bot.NewTradeProdAPI(infoProdSrv, aFact, aRep, prodTrader, halted, sugared)
 	statAPI := bot.NewStatAPI(statSrv, sugared)
+	strategyAPI := bot.NewStrategyAPI(sugared)
+	riskAPI := bot.NewRiskAPI(riskRep, sugared)
+	haltAPI := bot.NewHaltAPI(halted, aFact, prodTradeAPI, sdxTradeAPI, prodTrader, sdxTrader, sugared)
+	//Daily loss limit of account stops all algorithms trading on it
+	sdxRiskMgr.SetHaltHandler(sdxTradeAPI.HaltAccount)
+	prodRiskMgr.SetHaltHandler(prodTradeAPI.HaltAccount)
 
 	dc = depContainerImpl{
 		infoSdxSrv:   infoSdxSrv,
@@ -89,6 +109,9 @@ func initConfigurationWithLogger(sugared *zap.SugaredLogger) {
 		hRep:         hRep,
 		aRep:         aRep,
 		actionRep:    actionRep,
+		stopRep:      stopRep,
+		riskRep:      riskRep,
+		budgetRep:    budgetRep,
 		statRep:      statRep,
 		aFact:        aFact,
 		sdxTrader:    sdxTrader,
@@ -99,6 +122,9 @@ func initConfigurationWithLogger(sugared *zap.SugaredLogger) {
 		sdxTradeAPI:  sdxTradeAPI,
 		prodTradeAPI: prodTradeAPI,
 		statAPI:      statAPI,
+		strategyAPI:  strategyAPI,
+		riskAPI:      riskAPI,
+		haltAPI:      haltAPI,
 	}
 }
 
@@ -113,6 +139,9 @@ type depContainerImpl struct {
 	hRep         repository.HistoryRepository
 	aRep         repository.AlgoRepository
 	actionRep    repository.ActionRepository
+	stopRep      repository.StopOrderRepository
+	riskRep      repository.RiskRuleRepository
+	budgetRep    repository.BudgetRepository
 	statRep      repository.StatRepository
 	aFact        strategy.AlgFactory
 	sdxTrader    trade.Trader //Sandbox trader
@@ -124,6 +153,9 @@ type depContainerImpl struct {
 	historyAPI   bot.HistoryAPI
 	sdxTradeAPI  bot.TradeAPI
 	prodTradeAPI bot.TradeAPI
+	strategyAPI  bot.StrategyAPI
+	riskAPI      bot.RiskAPI
+	haltAPI      bot.HaltAPI
 }
 
 func (dc *depContainerImpl) GetLogger() *zap.SugaredLogger {
@@ -146,6 +178,18 @@ func (dc *depContainerImpl) GetProdTradeAPI() bot.TradeAPI {
 	return dc.prodTradeAPI
 }
 
+func (dc *depContainerImpl) GetStrategyAPI() bot.StrategyAPI {
+	return dc.strategyAPI
+}
+
+func (dc *depContainerImpl) GetRiskAPI() bot.RiskAPI {
+	return dc.riskAPI
+}
+
+func (dc *depContainerImpl) GetHaltAPI() bot.HaltAPI {
+	return dc.haltAPI
+}
+
 func Init() {
 	if isInitialized.SetToIf(false, true) {
 		//If data not initialized
@@ -180,6 +224,14 @@ func StartBgTasks() {
 	dc.logger.Info("Starting background tasks...")
 	dc.sdxTrader.Go(dc.ctx)  //Starting sandbox trader
 	dc.prodTrader.Go(dc.ctx) //Starting prod trader
+
+	dc.logger.Info("Restoring active algorithms...")
+	if err := dc.sdxTradeAPI.RestoreAlgorithms(dc.ctx); err != nil {
+		dc.logger.Error("Error while restoring sandbox algorithms: ", err)
+	}
+	if err := dc.prodTradeAPI.RestoreAlgorithms(dc.ctx); err != nil {
+		dc.logger.Error("Error while restoring prod algorithms: ", err)
+	}
 }
 
 func PostProcess() {
@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade"
+	"github.com/tevino/abool/v2"
+	"go.uber.org/zap"
+)
+
+type HaltAPI interface {
+	//Halt stops all prod and sandbox algorithms, cancels their posted orders and optionally closes their positions.
+	//New prod algorithms are refused until Resume
+	Halt(req *dto.HaltRequest) (*dto.HaltResponse, error)
+	//Resume allows to start new prod algorithms again, halted algorithms are not restarted
+	Resume() (*dto.HaltResponse, error)
+}
+
+type DefaultHaltAPI struct {
+	halted       *abool.AtomicBool
+	algFactory   strategy.AlgFactory
+	prodTradeAPI TradeAPI
+	sdxTradeAPI  TradeAPI
+	prodTrader   trade.Trader
+	sdxTrader    trade.Trader
+	logger       *zap.SugaredLogger
+}
+
+func NewHaltAPI(halted *abool.AtomicBool, algFactory strategy.AlgFactory, prodTradeAPI TradeAPI, sdxTradeAPI TradeAPI,
+	prodTrader trade.Trader, sdxTrader trade.Trader, logger *zap.SugaredLogger) HaltAPI {
+	return &DefaultHaltAPI{halted: halted, algFactory: algFactory, prodTradeAPI: prodTradeAPI, sdxTradeAPI: sdxTradeAPI,
+		prodTrader: prodTrader, sdxTrader: sdxTrader, logger: logger}
+}
+
+func (h *DefaultHaltAPI) Halt(req *dto.HaltRequest) (*dto.HaltResponse, error) {
+	h.logger.Warnf("Halting trading, close positions: %t", req.ClosePositions)
+	//Flag is set at first to refuse new algorithms while the running ones are stopped
+	h.halted.Set()
+	prodAlgs, err := h.algFactory.GetProdAlgs()
+	if err != nil {
+		return nil, err
+	}
+	sdxAlgs, err := h.algFactory.GetSdbxAlgs()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.HaltAlgorithmResponse, 0, len(prodAlgs)+len(sdxAlgs))
+	for _, alg := range prodAlgs {
+		res = append(res, h.haltAlgorithm(alg, entity.ProdEnv, h.prodTradeAPI, h.prodTrader, req.ClosePositions))
+	}
+	for _, alg := range sdxAlgs {
+		res = append(res, h.haltAlgorithm(alg, entity.SandboxEnv, h.sdxTradeAPI, h.sdxTrader, req.ClosePositions))
+	}
+	h.logger.Warnf("Trading halted, %d algorithms stopped", len(res))
+	return &dto.HaltResponse{IsHalted: true, Info: "Trading halted", Algorithms: res}, nil
+}
+
+//haltAlgorithm stops algorithm at first to prevent new orders, then cancels posted orders and closes positions if requested
+func (h *DefaultHaltAPI) haltAlgorithm(alg stmodel.Algorithm, env entity.Environment, api TradeAPI, trader trade.Trader,
+	closePositions bool) *dto.HaltAlgorithmResponse {
+	algDm := alg.GetAlgorithm()
+	res := &dto.HaltAlgorithmResponse{AlgorithmID: algDm.ID, Env: string(env), AccountId: algDm.AccountId,
+		CanceledOrders: make([]string, 0), ClosedPositions: make([]*dto.ClosingResponse, 0), Errors: make([]string, 0)}
+	stopResp, err := api.StopAlgorithm(&dto.StopAlgorithmRequest{AlgorithmId: algDm.ID})
+	if err != nil {
+		h.logger.Errorf("Error while stopping algorithm %d: %s", algDm.ID, err)
+		res.Errors = append(res.Errors, "stop algorithm: "+err.Error())
+	} else {
+		res.IsStopped = stopResp.IsStopped
+	}
+	canceled, err := trader.CancelOrders(algDm.ID)
+	res.CanceledOrders = append(res.CanceledOrders, canceled...)
+	if err != nil {
+		res.Errors = append(res.Errors, "cancel orders: "+err.Error())
+	}
+	if !closePositions {
+		return res
+	}
+	actions, err := trader.ClosePositions(algDm.ID, algDm.AccountId)
+	for _, action := range actions {
+		direction := "buy"
+		if action.Direction == entity.Sell {
+			direction = "sell"
+		}
+		res.ClosedPositions = append(res.ClosedPositions, &dto.ClosingResponse{Figi: action.InstrFigi, Direction: direction,
+			LotAmount: action.LotAmount, Status: string(action.Status), Info: action.Info})
+	}
+	if err != nil {
+		res.Errors = append(res.Errors, "close positions: "+err.Error())
+	}
+	return res
+}
+
+func (h *DefaultHaltAPI) Resume() (*dto.HaltResponse, error) {
+	h.halted.UnSet()
+	h.logger.Warn("Trading resumed")
+	return &dto.HaltResponse{IsHalted: false, Info: "Trading resumed, halted algorithms must be started again",
+		Algorithms: make([]*dto.HaltAlgorithmResponse, 0)}, nil
+}
@@ -8,11 +8,15 @@ import (
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/search"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/sizing"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/fill"
 	"go.uber.org/zap"
 	"log"
+	"sort"
 	"sync"
 	"time"
 )
@@ -25,6 +29,10 @@ type HistoryAPI interface {
 	AnalyzeAlgo(req *dto.CreateAlgorithmRequest, ctx context.Context) (*dto.HistStatResponse, error)
 	//AnalyzeAlgoInRange Analyze algorithm with parameter variation
 	AnalyzeAlgoInRange(req *dto.CreateAlgorithmRequest, ctx context.Context) (*dto.HistStatInRangeResponse, error)
+	//AnalyzeAlgoInRangeStream Analyze algorithm with parameter variation passing result of each parameter set to progress as it finishes
+	AnalyzeAlgoInRangeStream(req *dto.CreateAlgorithmRequest, progress func(*dto.RangeProgress), ctx context.Context) (*dto.HistStatInRangeResponse, error)
+	//AnalyzeWalkForward optimizes parameters on rolling in-sample windows and validates them on following out-of-sample windows
+	AnalyzeWalkForward(req *dto.WalkForwardRequest, ctx context.Context) (*dto.WalkForwardResponse, error)
 }
 
 type DefaultHistoryAPI struct {
@@ -59,7 +67,7 @@ func (h *DefaultHistoryAPI) AnalyzeAlgo(req *dto.CreateAlgorithmRequest, ctx con
 	if err != nil {
 		return nil, err
 	}
-	res, err := h.performAnalysis(req, shares, alg, ctx)
+	res, err := h.performAnalysis(req, shares, alg, h.histRep, false, ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -67,26 +75,41 @@ func (h *DefaultHistoryAPI) AnalyzeAlgo(req *dto.CreateAlgorithmRequest, ctx con
 	return res, nil
 }
 
+//performAnalysis simulates algorithm on history of repository, full equity is kept in report when it is required to merge reports
 func (h *DefaultHistoryAPI) performAnalysis(req *dto.CreateAlgorithmRequest, shares *dtotapi.SharesResponse,
-	alg stmodel.Algorithm, ctx context.Context) (*dto.HistStatResponse, error) {
+	alg stmodel.Algorithm, hRep repository.HistoryRepository, fullEquity bool, ctx context.Context) (*dto.HistStatResponse, error) {
 	sub, err := alg.Subscribe()
 	if err != nil {
 		return nil, err
 	}
+	if sub.Sizing, err = sizing.FromParams(alg.GetParam(), len(alg.GetAlgorithm().Figis)); err != nil {
+		return nil, err
+	}
+	sub.Limits = alg.GetAlgorithm().MoneyLimits
 	figiSet := make(map[string]bool)
 	for _, figi := range req.Figis {
 		figiSet[figi] = true
 	}
 	lots := make(map[string]int64)
 	figiCurrency := make(map[string]string)
+	shortEnabled := make(map[string]bool)
 	for _, instr := range shares.Instruments {
 		if !figiSet[instr.Figi] {
 			continue
 		}
 		lots[instr.Figi] = instr.Lot
 		figiCurrency[instr.Figi] = instr.Currency
+		shortEnabled[instr.Figi] = instr.ShortEnabledFlag
+	}
+	fillModel, err := fill.FromParams(alg.GetParam())
+	if err != nil {
+		return nil, err
+	}
+	trDr := trade.NewMockTrader(hRep, lots, figiCurrency, shortEnabled, h.logger)
+	trDr.SetFillModel(fillModel)
+	if fullEquity {
+		trDr.KeepFullEquity()
 	}
-	trDr := trade.NewMockTrader(h.histRep, lots, figiCurrency, h.logger)
 	if err = trDr.AddSubscription(sub); err != nil {
 		return nil, err
 	}
@@ -100,47 +123,148 @@ func (h *DefaultHistoryAPI) performAnalysis(req *dto.CreateAlgorithmRequest, sha
 }
 
 func (h *DefaultHistoryAPI) AnalyzeAlgoInRange(req *dto.CreateAlgorithmRequest, ctx context.Context) (*dto.HistStatInRangeResponse, error) {
+	return h.AnalyzeAlgoInRangeStream(req, nil, ctx)
+}
+
+func (h *DefaultHistoryAPI) AnalyzeAlgoInRangeStream(req *dto.CreateAlgorithmRequest, progress func(*dto.RangeProgress),
+	ctx context.Context) (*dto.HistStatInRangeResponse, error) {
 	h.logger.Info("Analyze algorithm in range from request: ", req)
-	algDm := entity.AlgorithmFromDto(req)
-	algRange, err := h.aFact.NewRange(algDm)
+	objective, err := newRangeObjective(req)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := search.FromDto(req.Search)
 	if err != nil {
 		return nil, err
 	}
+	algDm := entity.AlgorithmFromDto(req)
 	shares, err := h.infoSrv.GetAllShares(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	resCh := h.rangeAnalyzeBg(algRange, req, shares, ctx)
-	analyzeRes := make([]*dto.HistStatIdDto, 0, len(algRange))
-
-	for algRes := range resCh {
-		h.logger.Debug("Result received: ", algRes)
-		analyzeRes = append(analyzeRes, algRes)
+	analyzeRes, trials, err := h.searchRange(algDm, req, conf, objective, shares, h.histRep, progress, ctx)
+	if err != nil {
+		return nil, err
 	}
-
 	if len(analyzeRes) == 0 {
 		return nil, nil
 	}
-	var top = analyzeRes[0]
+	res := rankResults(analyzeRes, objective, req)
+	res.Mode = string(conf.Mode)
+	res.Trials = trials
+	h.logger.Info("Analyze algorithm in range completed; Result: ", res)
+	return res, nil
+}
+
+//searchRange analyzes parameter sets proposed by searcher on history of repository. Returns results of analysis
+//on the whole history and number of all analyzed parameter sets. Optional progress receives each result as it finishes
+func (h *DefaultHistoryAPI) searchRange(algDm *entity.Algorithm, req *dto.CreateAlgorithmRequest, conf search.Conf,
+	objective *rangeObjective, shares *dtotapi.SharesResponse, hRep repository.HistoryRepository,
+	progress func(*dto.RangeProgress), ctx context.Context) ([]*dto.HistStatIdDto, int, error) {
+	searcher, err := h.aFact.NewSearch(algDm, conf)
+	if err != nil {
+		return nil, 0, err
+	}
+	analyzeRes := make([]*dto.HistStatIdDto, 0)
+	var id uint
+	trials := 0
+	for batch := searcher.Next(); batch != nil && ctx.Err() == nil; batch = searcher.Next() {
+		batchRep, err := h.fidelityRep(hRep, req.Figis, batch.Fidelity)
+		if err != nil {
+			return nil, trials, err
+		}
+		algRange := make([]stmodel.Algorithm, 0, len(batch.Candidates))
+		for _, params := range batch.Candidates {
+			currAlg := algDm.CopyNoParam()
+			currAlg.ID = id
+			id++
+			for key, value := range params {
+				currAlg.Params = append(currAlg.Params, &entity.Param{Key: key, Value: value})
+			}
+			alg, err := h.aFact.NewHistOn(currAlg, batchRep)
+			if err != nil {
+				h.logger.Warnf("Parameter set %v skipped: %s", params, err)
+				continue
+			}
+			algRange = append(algRange, alg)
+		}
+		results := make([]*search.Result, 0, len(algRange))
+		for algRes := range h.rangeAnalyzeBg(algRange, req, shares, batchRep, conf.Concurrency, ctx) {
+			h.logger.Debug("Result received: ", algRes)
+			trials++
+			row := objective.gridRow(algRes)
+			results = append(results, &search.Result{Params: algRes.Param, Score: row.Score, Valid: row.Valid})
+			if batch.Fidelity >= 1 {
+				analyzeRes = append(analyzeRes, algRes)
+			}
+			if progress != nil {
+				progress(&dto.RangeProgress{Trial: trials, Stage: batch.Stage, Fidelity: batch.Fidelity, Row: row})
+			}
+		}
+		searcher.Report(results)
+	}
+	return analyzeRes, trials, nil
+}
+
+//fidelityRep returns repository with beginning part of history, repository itself is returned for the whole history
+func (h *DefaultHistoryAPI) fidelityRep(hRep repository.HistoryRepository, figis []string, fidelity float64) (repository.HistoryRepository, error) {
+	if fidelity >= 1 {
+		return hRep, nil
+	}
+	hist, err := hRep.FindAllByFigis(figis)
+	if err != nil {
+		return nil, err
+	}
+	if len(hist) == 0 {
+		return hRep, nil
+	}
+	start := hist[0].Time
+	end := start.Add(time.Duration(float64(hist[len(hist)-1].Time.Sub(start)) * fidelity))
+	return repository.NewWindowHistoryRepository(hRep, start, end), nil
+}
+
+//rankResults orders results satisfying objective constraints by objective score and returns top N of them.
+//All results are returned as a grid when grid format requested
+func rankResults(analyzeRes []*dto.HistStatIdDto, objective *rangeObjective, req *dto.CreateAlgorithmRequest) *dto.HistStatInRangeResponse {
+	//Results are received in order of completion - restore order of parameter sets
+	sort.SliceStable(analyzeRes, func(i, j int) bool {
+		return analyzeRes[i].Id < analyzeRes[j].Id
+	})
+	grid := make([]*dto.GridRow, 0, len(analyzeRes))
+	ranked := make([]*dto.RangeResult, 0, len(analyzeRes))
 	for _, stat := range analyzeRes {
-		if top.HistStat.CurBalance["rub"].LessThan(stat.HistStat.CurBalance["rub"]) {
-			top = stat
+		row := objective.gridRow(stat)
+		grid = append(grid, row)
+		if row.Valid {
+			ranked = append(ranked, &dto.RangeResult{Params: stat.Param, Score: row.Score, Stat: stat.HistStat})
 		}
 	}
-	res := &dto.HistStatInRangeResponse{
-		BestRes: top.HistStat,
-		Params:  top.Param,
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score.GreaterThan(ranked[j].Score)
+	})
+	topN := req.TopN
+	if topN < 1 {
+		topN = 1
 	}
-	h.logger.Info("Analyze algorithm in range completed; Result: ", res)
-	return res, nil
+	if topN > len(ranked) {
+		topN = len(ranked)
+	}
+	res := &dto.HistStatInRangeResponse{Top: ranked[:topN], Objective: objective.kind, Currency: objective.currency}
+	if len(ranked) > 0 {
+		res.BestRes = ranked[0].Stat
+		res.Params = ranked[0].Params
+	}
+	if req.GridFormat != "" {
+		res.Grid = grid
+	}
+	return res
 }
 
 func (h *DefaultHistoryAPI) rangeAnalyzeBg(algRange []stmodel.Algorithm, req *dto.CreateAlgorithmRequest,
-	shares *dtotapi.SharesResponse, ctx context.Context) chan *dto.HistStatIdDto {
+	shares *dtotapi.SharesResponse, hRep repository.HistoryRepository, concurrency int, ctx context.Context) chan *dto.HistStatIdDto {
 	var wg sync.WaitGroup
 	resCh := make(chan *dto.HistStatIdDto)
-	concurrency := 18
 	semaphore := make(chan bool, concurrency)
 	//Performs background algorithm processing
 	go func() {
@@ -157,7 +281,7 @@ func (h *DefaultHistoryAPI) rangeAnalyzeBg(algRange []stmodel.Algorithm, req *dt
 						<-semaphore
 						wg.Done()
 					}()
-					histResult, err := h.performAnalysis(req, shares, alg, ctx)
+					histResult, err := h.performAnalysis(req, shares, alg, hRep, false, ctx)
 					if err != nil {
 						h.logger.Error("Error while performing algorithm analysis: ", err)
 						return
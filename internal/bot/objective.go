@@ -0,0 +1,98 @@
+package bot
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+)
+
+//Objectives to rank results of range analysis
+const (
+	BalanceObjective        string = "balance"         //Final balance
+	SharpeObjective         string = "sharpe"          //Annualized Sharpe ratio
+	ProfitDrawdownObjective string = "profit_drawdown" //Profit divided by max drawdown, drawdown lower than one currency unit is counted as one
+)
+
+//Grid formats of range analysis results
+const (
+	GridJson string = "json"
+	GridCsv  string = "csv"
+)
+
+//rangeObjective scores results of range analysis in single currency
+type rangeObjective struct {
+	kind      string
+	currency  string
+	minTrades int
+}
+
+//newRangeObjective creates objective from request, by default results are ranked by final balance in currency of the first limit
+func newRangeObjective(req *dto.CreateAlgorithmRequest) (*rangeObjective, error) {
+	obj := rangeObjective{kind: BalanceObjective, currency: "rub"}
+	if len(req.Limits) > 0 {
+		obj.currency = req.Limits[0].Currency
+	}
+	if req.Objective != nil {
+		if req.Objective.Type != "" {
+			obj.kind = req.Objective.Type
+		}
+		if req.Objective.Currency != "" {
+			obj.currency = req.Objective.Currency
+		}
+		obj.minTrades = req.Objective.MinTrades
+	}
+	switch obj.kind {
+	case BalanceObjective, SharpeObjective, ProfitDrawdownObjective:
+	default:
+		return nil, errors.NewValidationErr(fmt.Sprintf("Objective must be one of %s, %s, %s; got '%s'",
+			BalanceObjective, SharpeObjective, ProfitDrawdownObjective, obj.kind))
+	}
+	if req.GridFormat != "" && req.GridFormat != GridJson && req.GridFormat != GridCsv {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Grid format must be %s or %s; got '%s'", GridJson, GridCsv, req.GridFormat))
+	}
+	return &obj, nil
+}
+
+//score returns value of objective for result and false if result doesn't satisfy objective constraints
+func (o *rangeObjective) score(stat *dto.HistStatResponse) (decimal.Decimal, bool) {
+	metrics := o.metrics(stat)
+	valid := metrics.TradeNum >= o.minTrades
+	switch o.kind {
+	case SharpeObjective:
+		return metrics.Sharpe, valid
+	case ProfitDrawdownObjective:
+		drawdown := metrics.MaxDrawdown
+		if drawdown.LessThan(decimal.NewFromInt(1)) {
+			drawdown = decimal.NewFromInt(1)
+		}
+		return metrics.Profit.Div(drawdown).Round(4), valid
+	default:
+		return stat.CurBalance[o.currency], valid
+	}
+}
+
+//metrics returns report metrics in objective currency, empty metrics when result has no report
+func (o *rangeObjective) metrics(stat *dto.HistStatResponse) *dto.ReportMetrics {
+	if stat.Report != nil {
+		if metrics, ok := stat.Report.Metrics[o.currency]; ok {
+			return metrics
+		}
+	}
+	return &dto.ReportMetrics{}
+}
+
+//gridRow creates short result of analysis with score of objective
+func (o *rangeObjective) gridRow(res *dto.HistStatIdDto) *dto.GridRow {
+	score, valid := o.score(res.HistStat)
+	metrics := o.metrics(res.HistStat)
+	return &dto.GridRow{
+		Params:         res.Param,
+		Score:          score,
+		Valid:          valid,
+		Balance:        res.HistStat.CurBalance[o.currency],
+		TradeNum:       metrics.TradeNum,
+		Sharpe:         metrics.Sharpe,
+		MaxDrawdownPct: metrics.MaxDrawdownPct,
+	}
+}
@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func rangeStat(id uint, balance int64, drawdown int64, trades int) *dto.HistStatIdDto {
+	return &dto.HistStatIdDto{
+		Id:    id,
+		Param: map[string]string{"short_dur": "10"},
+		HistStat: &dto.HistStatResponse{
+			CurBalance: map[string]decimal.Decimal{"usd": decimal.NewFromInt(balance)},
+			Report: &dto.BacktestReport{Metrics: map[string]*dto.ReportMetrics{"usd": {
+				Profit: decimal.NewFromInt(balance), MaxDrawdown: decimal.NewFromInt(drawdown), TradeNum: trades,
+			}}},
+		},
+	}
+}
+
+func Test_rankResults_by_objective(t *testing.T) {
+	results := []*dto.HistStatIdDto{rangeStat(2, 30, 30, 5), rangeStat(0, 50, 100, 5), rangeStat(1, 20, 5, 1)}
+	req := &dto.CreateAlgorithmRequest{Limits: []dto.MoneyValue{{Currency: "usd"}}, TopN: 2, GridFormat: GridJson}
+
+	//Default objective - balance in currency of the first limit
+	objective, err := newRangeObjective(req)
+	assert.Nil(t, err)
+	res := rankResults(results, objective, req)
+	assert.Equal(t, "usd", res.Currency)
+	assert.Equal(t, 2, len(res.Top))
+	assert.True(t, res.BestRes.CurBalance["usd"].Equal(decimal.NewFromInt(50)))
+	assert.Equal(t, 3, len(res.Grid))
+	assert.True(t, res.Grid[0].Balance.Equal(decimal.NewFromInt(50)), "grid is ordered by parameter sets")
+
+	//Profit to drawdown with min trades excludes the best ratio made by single trade
+	req.Objective = &dto.RangeObjective{Type: ProfitDrawdownObjective, MinTrades: 2}
+	objective, err = newRangeObjective(req)
+	assert.Nil(t, err)
+	res = rankResults(results, objective, req)
+	assert.True(t, res.Top[0].Score.Equal(decimal.NewFromInt(1)))
+	assert.True(t, res.Top[1].Score.Equal(decimal.NewFromFloat(0.5)))
+	assert.False(t, res.Grid[1].Valid)
+
+	req.Objective = &dto.RangeObjective{Type: "unknown"}
+	_, err = newRangeObjective(req)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"strings"
+)
+
+type RiskAPI interface {
+	//GetRules returns risk rules of the account
+	GetRules(req *dto.RiskRulesRequest) (*dto.RiskRulesResponse, error)
+	//SaveRule creates account risk rule or replaces value of existing one with the same type and currency
+	SaveRule(req *dto.RiskRuleRequest) (*dto.RiskRuleResponse, error)
+	//DeleteRule removes risk rule by id
+	DeleteRule(req *dto.DeleteRiskRuleRequest) error
+}
+
+type DefaultRiskAPI struct {
+	ruleRep repository.RiskRuleRepository
+	logger  *zap.SugaredLogger
+}
+
+func NewRiskAPI(ruleRep repository.RiskRuleRepository, logger *zap.SugaredLogger) RiskAPI {
+	return &DefaultRiskAPI{ruleRep: ruleRep, logger: logger}
+}
+
+func (ra *DefaultRiskAPI) GetRules(req *dto.RiskRulesRequest) (*dto.RiskRulesResponse, error) {
+	if req.AccountId == "" {
+		return nil, errors.NewValidationErr("accountId is required")
+	}
+	rules, err := ra.ruleRep.FindByAccount(req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.RiskRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		res = append(res, rule.ToDto())
+	}
+	return &dto.RiskRulesResponse{Rules: res}, nil
+}
+
+func (ra *DefaultRiskAPI) SaveRule(req *dto.RiskRuleRequest) (*dto.RiskRuleResponse, error) {
+	rule := entity.RiskRuleFromDto(req)
+	rule.Currency = strings.ToLower(rule.Currency)
+	if err := validateRiskRule(rule); err != nil {
+		return nil, err
+	}
+	if err := ra.ruleRep.Save(rule); err != nil {
+		return nil, err
+	}
+	ra.logger.Infof("Risk rule saved: %+v", rule)
+	return rule.ToDto(), nil
+}
+
+func (ra *DefaultRiskAPI) DeleteRule(req *dto.DeleteRiskRuleRequest) error {
+	if req.RuleId == 0 {
+		return errors.NewValidationErr("ruleId is required")
+	}
+	return ra.ruleRep.Delete(req.RuleId)
+}
+
+func validateRiskRule(rule *entity.RiskRule) error {
+	if rule.AccountID == "" {
+		return errors.NewValidationErr("accountId is required")
+	}
+	switch rule.Env {
+	case "":
+		rule.Env = entity.ProdEnv
+	case entity.ProdEnv, entity.SandboxEnv:
+	default:
+		return errors.NewValidationErr("unknown environment " + string(rule.Env))
+	}
+	if !rule.Value.IsPositive() {
+		return errors.NewValidationErr("rule value must be positive")
+	}
+	switch rule.Type {
+	case entity.MaxExposure, entity.MaxDailyLoss:
+		if rule.Currency == "" {
+			return errors.NewValidationErr("currency is required for rule " + string(rule.Type))
+		}
+	case entity.MaxFigiShare:
+		if rule.Value.GreaterThan(decimal.NewFromInt(100)) {
+			return errors.NewValidationErr("share of instrument must be in percents from 0 to 100")
+		}
+		rule.Currency = ""
+	case entity.MaxOrdersPerMin:
+		if !rule.Value.Equal(rule.Value.Floor()) {
+			return errors.NewValidationErr("number of orders per minute must be integer")
+		}
+		rule.Currency = ""
+	default:
+		return errors.NewValidationErr("unknown rule type " + string(rule.Type))
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy"
+	"go.uber.org/zap"
+)
+
+type StrategyAPI interface {
+	//GetStrategies returns registered strategies with accepted parameters
+	GetStrategies() (*dto.StrategiesResponse, error)
+}
+
+type DefaultStrategyAPI struct {
+	logger *zap.SugaredLogger
+}
+
+func NewStrategyAPI(logger *zap.SugaredLogger) StrategyAPI {
+	return &DefaultStrategyAPI{logger: logger}
+}
+
+func (s *DefaultStrategyAPI) GetStrategies() (*dto.StrategiesResponse, error) {
+	entries := strategy.GetStrategies()
+	res := make([]*dto.StrategyResponse, 0, len(entries))
+	for _, entry := range entries {
+		desc := entry.Value
+		params := make([]*dto.ParamSpecResponse, 0, len(desc.Params))
+		for i := range desc.Params {
+			params = append(params, desc.Params[i].ToDto())
+		}
+		res = append(res, &dto.StrategyResponse{
+			Name:           entry.Key,
+			Description:    desc.Description,
+			RangeSupported: desc.NewSplitter != nil,
+			Params:         params,
+		})
+	}
+	return &dto.StrategiesResponse{Strategies: res}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/sizing"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade"
 	"go.uber.org/zap"
@@ -21,6 +22,10 @@ type TradeAPI interface {
 	GetActiveAlgorithms() (*dto.AlgorithmsResponse, error)
 	//StopAlgorithm stops algorithm with requested id
 	StopAlgorithm(req *dto.StopAlgorithmRequest) (*dto.StopAlgorithmResponse, error)
+	//RestoreAlgorithms restarts algorithms which were active before application stop
+	RestoreAlgorithms(ctx context.Context) error
+	//HaltAccount stops all algorithms trading on the account, called by risk manager when daily loss limit reached
+	HaltAccount(accountId string, reason string)
 }
 
 type BaseTradeAPI struct {
@@ -46,7 +51,34 @@ func (ta *BaseTradeAPI) StopAlgorithm(req *dto.StopAlgorithmRequest) (*dto.StopA
 	return &dto.StopAlgorithmResponse{IsStopped: true, Info: "Stopped successfully"}, nil
 }
 
-func (ta *BaseTradeAPI) tradeInternal(req *dto.CreateAlgorithmRequest,
+//haltInternal stops active algorithms of the environment trading on the account
+func (ta *BaseTradeAPI) haltInternal(accountId string, reason string, algs []stmodel.Algorithm) {
+	ta.logger.Warnf("Halting algorithms of account %s: %s", accountId, reason)
+	for _, alg := range algs {
+		algDm := alg.GetAlgorithm()
+		if algDm.AccountId != accountId {
+			continue
+		}
+		if _, err := ta.StopAlgorithm(&dto.StopAlgorithmRequest{AlgorithmId: algDm.ID}); err != nil {
+			ta.logger.Errorf("Error while halting algorithm %d: %s", algDm.ID, err)
+			continue
+		}
+		ta.logger.Infof("Algorithm %d halted", algDm.ID)
+	}
+}
+
+//toDtoWithBudgets converts algorithm to response with money of algorithm tracked by trader
+func (ta *BaseTradeAPI) toDtoWithBudgets(algDm *entity.Algorithm) *dto.AlgorithmResponse {
+	res := algDm.ToDto()
+	budgets := ta.trader.GetBudgets(algDm.ID)
+	res.Budgets = make([]*dto.BudgetResponse, 0, len(budgets))
+	for _, budget := range budgets {
+		res.Budgets = append(res.Budgets, budget.ToDto())
+	}
+	return res
+}
+
+func (ta *BaseTradeAPI) tradeInternal(req *dto.CreateAlgorithmRequest, env entity.Environment,
 	factoryF func(request *entity.Algorithm) (stmodel.Algorithm, error), ctx context.Context) (*dto.TradeStartResponse, error) {
 	ta.logger.Info("Requested new algorithm ", req)
 	//Check is enough rights to account at first
@@ -67,23 +99,61 @@ func (ta *BaseTradeAPI) tradeInternal(req *dto.CreateAlgorithmRequest,
 
 	//Create and start algorithm
 	algDm := entity.AlgorithmFromDto(req)
+	algDm.Env = env
+	if err := strategy.Validate(algDm); err != nil {
+		return nil, err
+	}
 	if err := ta.algRep.Save(algDm); err != nil {
 		return nil, err
 	}
+	if err := ta.startAlgorithm(algDm, factoryF, ctx); err != nil {
+		return nil, err
+	}
+	return &dto.TradeStartResponse{Info: "Successfully started", AlgorithmID: algDm.ID}, nil
+}
+
+//restoreInternal loads active algorithms of the environment from db and starts them again.
+//Posted orders of algorithms are passed to trader to continue monitoring
+func (ta *BaseTradeAPI) restoreInternal(env entity.Environment,
+	factoryF func(request *entity.Algorithm) (stmodel.Algorithm, error), ctx context.Context) error {
+	algos, err := ta.algRep.FindActiveByEnv(env)
+	if err != nil {
+		return err
+	}
+	ta.logger.Infof("Restoring %d active algorithms in %s environment", len(algos), env)
+	for _, algDm := range algos {
+		if err := ta.startAlgorithm(algDm, factoryF, ctx); err != nil {
+			ta.logger.Errorf("Error while restoring algorithm %d, skipping: %s", algDm.ID, err)
+			continue
+		}
+		ta.logger.Infof("Algorithm %d restored with %d posted orders", algDm.ID, len(algDm.Actions))
+	}
+	return nil
+}
+
+//startAlgorithm creates algorithm, subscribes it to trader and starts it in background
+func (ta *BaseTradeAPI) startAlgorithm(algDm *entity.Algorithm,
+	factoryF func(request *entity.Algorithm) (stmodel.Algorithm, error), ctx context.Context) error {
 	alg, err := factoryF(algDm)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	sub, err := alg.Subscribe()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if sub.Sizing, err = sizing.FromParams(alg.GetParam(), len(algDm.Figis)); err != nil {
+		return err
 	}
+	sub.Limits = algDm.MoneyLimits
 	if err = ta.trader.AddSubscription(sub); err != nil {
-		return nil, err
+		return err
 	}
+	ta.trader.RestoreOrders(algDm.Actions)
+	ta.trader.RestoreStopOrders(algDm.StopOrders)
 	if err = alg.Go(ctx); err != nil {
 		ta.logger.Error("Error while starting algorithm, check routine leaking")
-		return nil, err
+		return err
 	}
-	return &dto.TradeStartResponse{Info: "Successfully started", AlgorithmID: algDm.ID}, nil
+	return nil
 }
@@ -3,16 +3,20 @@ package bot
 import (
 	"context"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade"
+	"github.com/tevino/abool/v2"
 	"go.uber.org/zap"
 )
 
 type TradeProdAPI struct {
 	*BaseTradeAPI
 	algFactory strategy.AlgFactory
+	halted     *abool.AtomicBool //Set by trading halt - new algorithms are refused
 	logger     *zap.SugaredLogger
 }
 
@@ -24,17 +28,33 @@ func (t *TradeProdAPI) GetActiveAlgorithms() (*dto.AlgorithmsResponse, error) {
 	}
 	res := make([]*dto.AlgorithmResponse, 0, len(algs))
 	for _, alg := range algs {
-		res = append(res, alg.GetAlgorithm().ToDto())
+		res = append(res, t.toDtoWithBudgets(alg.GetAlgorithm()))
 	}
 	return &dto.AlgorithmsResponse{Algorithms: res}, nil
 }
 
 func (t *TradeProdAPI) Trade(req *dto.CreateAlgorithmRequest, ctx context.Context) (*dto.TradeStartResponse, error) {
-	return t.tradeInternal(req, t.algFactory.NewProd, ctx)
+	if t.halted.IsSet() {
+		return nil, errors.NewValidationErr("Trading halted, resume it to start new algorithms")
+	}
+	return t.tradeInternal(req, entity.ProdEnv, t.algFactory.NewProd, ctx)
+}
+
+func (t *TradeProdAPI) HaltAccount(accountId string, reason string) {
+	algs, err := t.algFactory.GetProdAlgs()
+	if err != nil {
+		t.logger.Error("Error retrieving prod algorithms: ", err)
+		return
+	}
+	t.haltInternal(accountId, reason, algs)
+}
+
+func (t *TradeProdAPI) RestoreAlgorithms(ctx context.Context) error {
+	return t.restoreInternal(entity.ProdEnv, t.algFactory.NewProd, ctx)
 }
 
 func NewTradeProdAPI(infoSrv service.InfoSrv, algFactory strategy.AlgFactory, algRep repository.AlgoRepository,
-	trader trade.Trader, logger *zap.SugaredLogger) TradeAPI {
+	trader trade.Trader, halted *abool.AtomicBool, logger *zap.SugaredLogger) TradeAPI {
 	baseAPI := BaseTradeAPI{algFactory: algFactory, logger: logger, trader: trader, infoSrv: infoSrv, algRep: algRep}
-	return &TradeProdAPI{&baseAPI, algFactory, logger}
+	return &TradeProdAPI{&baseAPI, algFactory, halted, logger}
 }
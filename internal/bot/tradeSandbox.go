@@ -3,6 +3,7 @@ package bot
 import (
 	"context"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy"
@@ -24,13 +25,26 @@ func (t *TradeSandboxAPI) GetActiveAlgorithms() (*dto.AlgorithmsResponse, error)
 	}
 	res := make([]*dto.AlgorithmResponse, 0, len(algs))
 	for _, alg := range algs {
-		res = append(res, alg.GetAlgorithm().ToDto())
+		res = append(res, t.toDtoWithBudgets(alg.GetAlgorithm()))
 	}
 	return &dto.AlgorithmsResponse{Algorithms: res}, nil
 }
 
 func (t TradeSandboxAPI) Trade(req *dto.CreateAlgorithmRequest, ctx context.Context) (*dto.TradeStartResponse, error) {
-	return t.tradeInternal(req, t.algFactory.NewSandbox, ctx)
+	return t.tradeInternal(req, entity.SandboxEnv, t.algFactory.NewSandbox, ctx)
+}
+
+func (t *TradeSandboxAPI) HaltAccount(accountId string, reason string) {
+	algs, err := t.algFactory.GetSdbxAlgs()
+	if err != nil {
+		t.logger.Error("Error retrieving sandbox algorithms: ", err)
+		return
+	}
+	t.haltInternal(accountId, reason, algs)
+}
+
+func (t *TradeSandboxAPI) RestoreAlgorithms(ctx context.Context) error {
+	return t.restoreInternal(entity.SandboxEnv, t.algFactory.NewSandbox, ctx)
 }
 
 func NewSandboxTradeAPI(infoSrv service.InfoSrv, algFactory strategy.AlgFactory, algRep repository.AlgoRepository,
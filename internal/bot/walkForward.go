@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"context"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/search"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade"
+	"github.com/shopspring/decimal"
+	"math"
+	"sort"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+func (h *DefaultHistoryAPI) AnalyzeWalkForward(req *dto.WalkForwardRequest, ctx context.Context) (*dto.WalkForwardResponse, error) {
+	h.logger.Info("Walk-forward analysis request: ", req)
+	if req.InSampleDays < 1 || req.OutSampleDays < 1 {
+		return nil, errors.NewValidationErr("In-sample and out-of-sample windows must be at least one day")
+	}
+	step := req.StepDays
+	if step < 1 {
+		step = req.OutSampleDays
+	}
+	//Overlapping out-of-sample windows would count the same days in merged result several times
+	if step < req.OutSampleDays {
+		return nil, errors.NewValidationErr("Step of windows must not be less than out-of-sample window")
+	}
+	objective, err := newRangeObjective(&req.CreateAlgorithmRequest)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := search.FromDto(req.Search)
+	if err != nil {
+		return nil, err
+	}
+	hist, err := h.histRep.FindAllByFigis(req.Figis)
+	if err != nil {
+		return nil, err
+	}
+	if len(hist) == 0 {
+		return nil, errors.NewValidationErr("No history loaded for requested figis")
+	}
+	shares, err := h.infoSrv.GetAllShares(ctx)
+	if err != nil {
+		return nil, err
+	}
+	algDm := entity.AlgorithmFromDto(&req.CreateAlgorithmRequest)
+	res := &dto.WalkForwardResponse{Folds: make([]*dto.WalkForwardFold, 0), Objective: objective.kind, Currency: objective.currency}
+	end := hist[len(hist)-1].Time
+	//Fold is performed while there is history after in-sample window
+	for isStart := hist[0].Time; isStart.Add(time.Duration(req.InSampleDays) * day).Before(end); isStart = isStart.Add(time.Duration(step) * day) {
+		fold := &dto.WalkForwardFold{
+			InSampleStart:  isStart,
+			InSampleEnd:    isStart.Add(time.Duration(req.InSampleDays) * day),
+			OutSampleStart: isStart.Add(time.Duration(req.InSampleDays) * day),
+			OutSampleEnd:   isStart.Add(time.Duration(req.InSampleDays+req.OutSampleDays) * day),
+		}
+		if err = h.analyzeFold(fold, algDm, req, conf, objective, shares, ctx); err != nil {
+			return nil, err
+		}
+		h.logger.Infof("Walk-forward fold completed: %+v", fold)
+		res.Folds = append(res.Folds, fold)
+	}
+	if len(res.Folds) == 0 {
+		return nil, errors.NewValidationErr("Loaded history is shorter than in-sample window")
+	}
+	res.OutOfSample = mergeOutOfSample(res.Folds)
+	res.Stability = paramStability(res.Folds, req.Params)
+	h.logger.Info("Walk-forward analysis completed; Out-of-sample result: ", res.OutOfSample)
+	return res, nil
+}
+
+//analyzeFold chooses the best parameters on in-sample window and analyzes algorithm with them on out-of-sample window
+func (h *DefaultHistoryAPI) analyzeFold(fold *dto.WalkForwardFold, algDm *entity.Algorithm, req *dto.WalkForwardRequest,
+	conf search.Conf, objective *rangeObjective, shares *dtotapi.SharesResponse, ctx context.Context) error {
+	isRep := repository.NewWindowHistoryRepository(h.histRep, fold.InSampleStart, fold.InSampleEnd)
+	analyzeRes, _, err := h.searchRange(algDm, &req.CreateAlgorithmRequest, conf, objective, shares, isRep, nil, ctx)
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	ranked := rankResults(analyzeRes, objective, &dto.CreateAlgorithmRequest{TopN: 1})
+	if len(ranked.Top) == 0 {
+		h.logger.Infof("No in-sample result satisfies objective in fold starting %s", fold.InSampleStart)
+		return nil
+	}
+	fold.Params = ranked.Params
+	fold.InSampleScore = ranked.Top[0].Score
+
+	oosAlgDm := algDm.CopyNoParam()
+	for key, value := range ranked.Params {
+		oosAlgDm.Params = append(oosAlgDm.Params, &entity.Param{Key: key, Value: value})
+	}
+	oosRep := repository.NewWindowHistoryRepository(h.histRep, fold.OutSampleStart, fold.OutSampleEnd)
+	alg, err := h.aFact.NewHistOn(oosAlgDm, oosRep)
+	if err != nil {
+		return err
+	}
+	if fold.OutSample, err = h.performAnalysis(&req.CreateAlgorithmRequest, shares, alg, oosRep, true, ctx); err != nil {
+		return err
+	}
+	fold.OutSampleScore, _ = objective.score(fold.OutSample)
+	return nil
+}
+
+//mergeOutOfSample stitches out-of-sample results of folds into single result
+func mergeOutOfSample(folds []*dto.WalkForwardFold) *dto.HistStatResponse {
+	res := &dto.HistStatResponse{CurBalance: make(map[string]decimal.Decimal)}
+	reports := make([]*dto.BacktestReport, 0, len(folds))
+	for _, fold := range folds {
+		if fold.OutSample == nil {
+			continue
+		}
+		res.BuyOpNum += fold.OutSample.BuyOpNum
+		res.SellOpNum += fold.OutSample.SellOpNum
+		res.ExpiredOpNum += fold.OutSample.ExpiredOpNum
+		for cur, val := range fold.OutSample.CurBalance {
+			res.CurBalance[cur] = res.CurBalance[cur].Add(val)
+		}
+		if fold.OutSample.Report != nil {
+			reports = append(reports, fold.OutSample.Report)
+		}
+	}
+	res.Report = trade.MergeReports(reports)
+	return res
+}
+
+//paramStability describes chosen values of parameters varied by request,
+//parameter is treated as varied when chosen value differs from requested one
+func paramStability(folds []*dto.WalkForwardFold, reqParams map[string]string) []*dto.ParamStability {
+	names := make([]string, 0)
+	for name, reqVal := range reqParams {
+		for _, fold := range folds {
+			if val, ok := fold.Params[name]; ok && val != reqVal {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	res := make([]*dto.ParamStability, 0, len(names))
+	for _, name := range names {
+		stab := dto.ParamStability{Name: name, Values: make([]string, 0, len(folds))}
+		distinct := make(map[string]bool)
+		numbers := make([]float64, 0, len(folds))
+		numeric := true
+		for _, fold := range folds {
+			val, ok := fold.Params[name]
+			if !ok {
+				continue
+			}
+			if len(stab.Values) > 0 && stab.Values[len(stab.Values)-1] != val {
+				stab.Changes++
+			}
+			stab.Values = append(stab.Values, val)
+			distinct[val] = true
+			num, err := decimal.NewFromString(val)
+			if err != nil {
+				numeric = false
+				continue
+			}
+			numbers = append(numbers, num.InexactFloat64())
+		}
+		stab.Distinct = len(distinct)
+		if numeric && len(numbers) > 0 {
+			mean, stdDev := meanStdDev(numbers)
+			stab.Mean = &mean
+			stab.StdDev = &stdDev
+		}
+		res = append(res, &stab)
+	}
+	return res
+}
+
+func meanStdDev(values []float64) (decimal.Decimal, decimal.Decimal) {
+	var sum float64
+	for _, val := range values {
+		sum += val
+	}
+	mean := sum / float64(len(values))
+	var sqSum float64
+	for _, val := range values {
+		sqSum += (val - mean) * (val - mean)
+	}
+	stdDev := math.Sqrt(sqSum / float64(len(values)))
+	return decimal.NewFromFloat(mean).Round(4), decimal.NewFromFloat(stdDev).Round(4)
+}
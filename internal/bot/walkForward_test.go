@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"context"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func Test_mergeOutOfSample_and_paramStability(t *testing.T) {
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	capital := map[string]decimal.Decimal{"usd": decimal.NewFromInt(1000)}
+	oosStat := func(from int, profit int64, buys uint) *dto.HistStatResponse {
+		begin, to := start.Add(time.Duration(from)*day), start.Add(time.Duration(from+1)*day)
+		return &dto.HistStatResponse{
+			BuyOpNum:   buys,
+			CurBalance: map[string]decimal.Decimal{"usd": decimal.NewFromInt(profit)},
+			Report: &dto.BacktestReport{
+				Start: begin, End: to, Capital: capital,
+				Equity: []*dto.EquityPoint{
+					{Time: begin, Value: map[string]decimal.Decimal{"usd": decimal.NewFromInt(1000)}},
+					{Time: to, Value: map[string]decimal.Decimal{"usd": decimal.NewFromInt(1000 + profit)}},
+				},
+				Metrics: map[string]*dto.ReportMetrics{"usd": {Profit: decimal.NewFromInt(profit)}},
+			},
+		}
+	}
+	folds := []*dto.WalkForwardFold{
+		{Params: map[string]string{"short_dur": "10", "long_dur": "100"}, OutSample: oosStat(0, 50, 1)},
+		{Params: map[string]string{"short_dur": "20", "long_dur": "100"}, OutSample: oosStat(1, -30, 2)},
+		{},
+	}
+
+	res := mergeOutOfSample(folds)
+	assert.Equal(t, uint(3), res.BuyOpNum)
+	assert.True(t, res.CurBalance["usd"].Equal(decimal.NewFromInt(20)))
+	assert.Equal(t, 4, len(res.Report.Equity))
+	assert.True(t, res.Report.Equity[3].Value["usd"].Equal(decimal.NewFromInt(1020)), "equity is shifted by previous profit")
+	assert.True(t, res.Report.Metrics["usd"].Profit.Equal(decimal.NewFromInt(20)))
+	assert.True(t, res.Report.Metrics["usd"].MaxDrawdown.Equal(decimal.NewFromInt(30)))
+
+	stab := paramStability(folds, map[string]string{"short_dur": "10:20:10", "long_dur": "100"})
+	assert.Equal(t, 1, len(stab), "only varied parameters are described")
+	assert.Equal(t, "short_dur", stab[0].Name)
+	assert.Equal(t, []string{"10", "20"}, stab[0].Values)
+	assert.Equal(t, 2, stab[0].Distinct)
+	assert.Equal(t, 1, stab[0].Changes)
+	assert.True(t, stab[0].Mean.Equal(decimal.NewFromInt(15)))
+	assert.True(t, stab[0].StdDev.Equal(decimal.NewFromInt(5)))
+}
+
+func TestAnalyzeWalkForward_overlappingWindows(t *testing.T) {
+	api := &DefaultHistoryAPI{logger: zap.NewNop().Sugar()}
+	req := &dto.WalkForwardRequest{InSampleDays: 20, OutSampleDays: 5, StepDays: 3}
+	_, err := api.AnalyzeWalkForward(req, context.Background())
+	assert.IsType(t, errors.ValidationErr{}, err, "Out-of-sample windows must not overlap")
+}
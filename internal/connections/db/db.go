@@ -33,6 +33,9 @@ func migrate() error {
 		&entity.History{},
 		&entity.Algorithm{},
 		&entity.Action{},
+		&entity.StopOrder{},
+		&entity.RiskRule{},
+		&entity.AlgoBudget{},
 		&entity.Param{},
 		&entity.CtxParam{},
 		&entity.MoneyLimit{},
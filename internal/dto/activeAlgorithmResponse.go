@@ -18,6 +18,7 @@ type AlgorithmResponse struct {
 	Params      map[string]string `json:"params"`
 	IsActive    bool              `json:"isActive"`
 	InstrAvail  *InstrumentsInfo  `json:"instrAvail"` //Information about available instruments for algorithm
+	Budgets     []*BudgetResponse `json:"budgets"`    //Money of algorithm tracked by trader across orders
 	CreatedAt   time.Time         `json:"createdAt"`
 	UpdatedAt   time.Time         `json:"updatedAt"`
 }
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//BacktestReport is a detailed result of algorithm simulation on history
+type BacktestReport struct {
+	Trades     []*TradeReport             `json:"trades"`     //Round trip trades, position not closed by the end of history is valued by the last price
+	Equity     []*EquityPoint             `json:"equity"`     //Equity curve sampled over history period
+	Metrics    map[string]*ReportMetrics  `json:"metrics"`    //Performance metrics by currency
+	Benchmarks []*BenchmarkReport         `json:"benchmarks"` //Buy-and-hold result of each figi
+	Start      time.Time                  `json:"start"`      //Start of history period
+	End        time.Time                  `json:"end"`        //End of history period
+	Capital    map[string]decimal.Decimal `json:"capital"`    //Initial capital by currency - algorithm limits
+	FullEquity []*EquityPoint             `json:"-"`          //Equity by every history record, kept only to merge reports
+}
+
+//TradeReport describes position opened and closed by algorithm
+type TradeReport struct {
+	Figi       string          `json:"figi"`
+	Currency   string          `json:"currency"`
+	Short      bool            `json:"short"`
+	Lots       int64           `json:"lots"`
+	EntryTime  time.Time       `json:"entryTime"`
+	EntryPrice decimal.Decimal `json:"entryPrice"`
+	ExitTime   time.Time       `json:"exitTime"`
+	ExitPrice  decimal.Decimal `json:"exitPrice"`
+	Profit     decimal.Decimal `json:"profit"` //Profit with commissions
+	Open       bool            `json:"open"`   //Position is not closed by the end of history
+}
+
+//EquityPoint is a value of capital and positions by currency at the time
+type EquityPoint struct {
+	Time  time.Time                  `json:"time"`
+	Value map[string]decimal.Decimal `json:"value"`
+}
+
+//ReportMetrics contains performance metrics of algorithm in single currency
+type ReportMetrics struct {
+	Profit         decimal.Decimal `json:"profit"`
+	Return         decimal.Decimal `json:"return"`         //Profit in percents of initial capital
+	MaxDrawdown    decimal.Decimal `json:"maxDrawdown"`    //The largest fall of equity from its peak
+	MaxDrawdownPct decimal.Decimal `json:"maxDrawdownPct"` //The largest fall of equity from its peak in percents of the peak
+	Sharpe         decimal.Decimal `json:"sharpe"`         //Annualized Sharpe ratio of daily returns
+	Sortino        decimal.Decimal `json:"sortino"`        //Annualized Sortino ratio of daily returns
+	TradeNum       int             `json:"tradeNum"`       //Number of closed trades
+	WinRate        decimal.Decimal `json:"winRate"`        //Percent of profitable closed trades
+	ProfitFactor   decimal.Decimal `json:"profitFactor"`   //Gross profit divided by gross loss, zero when there are no losses
+	AvgHolding     string          `json:"avgHolding"`     //Average holding time of closed trades
+	ExposurePct    decimal.Decimal `json:"exposurePct"`    //Percent of history period with open positions
+}
+
+//BenchmarkReport describes result of buying figi by the whole capital at the start and holding it to the end of history
+type BenchmarkReport struct {
+	Figi       string          `json:"figi"`
+	Currency   string          `json:"currency"`
+	StartPrice decimal.Decimal `json:"startPrice"`
+	EndPrice   decimal.Decimal `json:"endPrice"`
+	Return     decimal.Decimal `json:"return"` //Price change in percents
+	Profit     decimal.Decimal `json:"profit"` //Profit of lots bought by capital of the currency
+	Excess     decimal.Decimal `json:"excess"` //Algorithm profit minus buy-and-hold profit
+}
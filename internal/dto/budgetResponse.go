@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+//BudgetResponse represents algorithm money tracked by trader in single currency
+type BudgetResponse struct {
+	Currency  string          `json:"currency"`
+	Limit     decimal.Decimal `json:"limit"`     //Configured money limit
+	Cash      decimal.Decimal `json:"cash"`      //Limit minus spent by buys plus received by sells
+	Reserved  decimal.Decimal `json:"reserved"`  //Reserved by posted buy orders
+	Available decimal.Decimal `json:"available"` //Available for new buy orders
+}
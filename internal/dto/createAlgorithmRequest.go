@@ -6,12 +6,16 @@ import (
 
 //CreateAlgorithmRequest request to create new trade algorithm or history algorithm config
 type CreateAlgorithmRequest struct {
-	AccountId string            `json:"accountId"`
-	Figis     []string          `json:"figis"`
-	Strategy  string            `json:"strategy"`
-	Limits    []MoneyValue      `json:"limits"` //Algorithm limits on using money
-	Params    map[string]string `json:"params"`
-	InstrInit *InstrumentsInfo  `json:"instrInit"` //Optional - to specify initial instrument available
+	AccountId  string            `json:"accountId"`
+	Figis      []string          `json:"figis"`
+	Strategy   string            `json:"strategy"`
+	Limits     []MoneyValue      `json:"limits"` //Algorithm limits on using money
+	Params     map[string]string `json:"params"`
+	InstrInit  *InstrumentsInfo  `json:"instrInit"`  //Optional - to specify initial instrument available
+	TopN       int               `json:"topN"`       //Optional - number of best parameter sets returned by range analysis, 1 by default
+	Objective  *RangeObjective   `json:"objective"`  //Optional - ranking of range analysis results, final balance by default
+	GridFormat string            `json:"gridFormat"` //Optional - format of all range analysis results: json or csv, not returned by default
+	Search     *SearchConf       `json:"search"`     //Optional - search of parameter sets by range analysis, full grid by default
 }
 
 type MoneyValue struct {
@@ -0,0 +1,15 @@
+package dtotapi
+
+import "github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+
+type CancelStopOrderRequest struct {
+	AccountId   string
+	StopOrderId string
+}
+
+func (cor *CancelStopOrderRequest) ToTinApi() *investapi.CancelStopOrderRequest {
+	return &investapi.CancelStopOrderRequest{
+		AccountId:   cor.AccountId,
+		StopOrderId: cor.StopOrderId,
+	}
+}
@@ -0,0 +1,16 @@
+package dtotapi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"time"
+)
+
+type CancelStopOrderResponse struct {
+	Time time.Time
+}
+
+func CancelStopOrderResponseToDto(resp *investapi.CancelStopOrderResponse) *CancelStopOrderResponse {
+	return &CancelStopOrderResponse{
+		Time: resp.Time.AsTime(),
+	}
+}
@@ -0,0 +1,11 @@
+package dtotapi
+
+import "github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+
+type MarginAttributesRequest struct {
+	AccountId string
+}
+
+func (req *MarginAttributesRequest) ToTinApi() *investapi.GetMarginAttributesRequest {
+	return &investapi.GetMarginAttributesRequest{AccountId: req.AccountId}
+}
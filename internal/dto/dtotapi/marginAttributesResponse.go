@@ -0,0 +1,36 @@
+package dtotapi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/convert"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"github.com/shopspring/decimal"
+)
+
+type MarginAttributesResponse struct {
+	LiquidPortfolio       *MoneyValue     //Liquid portfolio value
+	StartingMargin        *MoneyValue     //Starting margin - collateral required to make new deals
+	MinimalMargin         *MoneyValue     //Minimal margin - collateral required to keep opened positions
+	FundsSufficiencyLevel decimal.Decimal //Ratio of liquid portfolio to starting margin
+	AmountOfMissingFunds  *MoneyValue     //Difference between starting margin and liquid portfolio
+}
+
+//GetFreeMargin returns liquid portfolio value not used as collateral of opened positions
+func (mar *MarginAttributesResponse) GetFreeMargin() decimal.Decimal {
+	if mar.LiquidPortfolio == nil {
+		return decimal.Zero
+	}
+	if mar.StartingMargin == nil {
+		return mar.LiquidPortfolio.Value
+	}
+	return mar.LiquidPortfolio.Value.Sub(mar.StartingMargin.Value)
+}
+
+func MarginAttributesResponseToDto(resp *investapi.GetMarginAttributesResponse) *MarginAttributesResponse {
+	return &MarginAttributesResponse{
+		LiquidPortfolio:       MoneyValueToDto(resp.LiquidPortfolio),
+		StartingMargin:        MoneyValueToDto(resp.StartingMargin),
+		MinimalMargin:         MoneyValueToDto(resp.MinimalMargin),
+		FundsSufficiencyLevel: convert.QuotationToDec(resp.FundsSufficiencyLevel),
+		AmountOfMissingFunds:  MoneyValueToDto(resp.AmountOfMissingFunds),
+	}
+}
@@ -0,0 +1,25 @@
+package dtotapi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+//OperationsRequest is a request of executed operations of account by instrument for the period
+type OperationsRequest struct {
+	AccountId string
+	Figi      string
+	From      time.Time
+	To        time.Time
+}
+
+func (or *OperationsRequest) ToTinApi() *investapi.OperationsRequest {
+	return &investapi.OperationsRequest{
+		AccountId: or.AccountId,
+		Figi:      or.Figi,
+		From:      timestamppb.New(or.From),
+		To:        timestamppb.New(or.To),
+		State:     investapi.OperationState_OPERATION_STATE_EXECUTED,
+	}
+}
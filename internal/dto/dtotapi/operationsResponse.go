@@ -0,0 +1,51 @@
+package dtotapi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"time"
+)
+
+type OperationType int
+
+const (
+	OperationTypeUnspecified OperationType = 0
+	OperationTypeBuy         OperationType = 15
+	OperationTypeSell        OperationType = 22
+)
+
+//OperationsResponse contains operations of account
+type OperationsResponse struct {
+	Operations []*Operation
+}
+
+type Operation struct {
+	Id           string
+	Figi         string
+	Type         OperationType //Operation type, only buy and sell types are used by bot
+	Payment      *MoneyValue   //Money amount of operation
+	Price        *MoneyValue   //Price of single instrument
+	Quantity     int64         //Amount of instruments
+	QuantityRest int64         //Not executed amount of instruments
+	Date         time.Time     //Operation time
+}
+
+func operationToDto(op *investapi.Operation) *Operation {
+	return &Operation{
+		Id:           op.Id,
+		Figi:         op.Figi,
+		Type:         OperationType(op.OperationType),
+		Payment:      MoneyValueToDto(op.Payment),
+		Price:        MoneyValueToDto(op.Price),
+		Quantity:     op.Quantity,
+		QuantityRest: op.QuantityRest,
+		Date:         op.Date.AsTime(),
+	}
+}
+
+func OperationsResponseToDto(resp *investapi.OperationsResponse) *OperationsResponse {
+	operations := make([]*Operation, 0, len(resp.Operations))
+	for _, op := range resp.Operations {
+		operations = append(operations, operationToDto(op))
+	}
+	return &OperationsResponse{Operations: operations}
+}
@@ -0,0 +1,42 @@
+package dtotapi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/convert"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"github.com/shopspring/decimal"
+)
+
+type StopOrderType int
+
+const (
+	StopOrderTypeUnspecified StopOrderType = iota
+	StopOrderTypeTakeProfit
+	StopOrderTypeStopLoss
+	StopOrderTypeStopLimit
+)
+
+//PostStopOrderRequest is a request of good till cancel stop order executed by market price when stop price is reached
+type PostStopOrderRequest struct {
+	Figi          string
+	PosNum        int64
+	StopPrice     decimal.Decimal
+	Direction     OrderDirection
+	AccountId     string
+	StopOrderType StopOrderType
+}
+
+func (pr *PostStopOrderRequest) ToTinApi() *investapi.PostStopOrderRequest {
+	if pr == nil {
+		return nil
+	}
+	return &investapi.PostStopOrderRequest{
+		Figi:           pr.Figi,
+		Quantity:       pr.PosNum,
+		Price:          convert.DecToQuotation(pr.StopPrice),
+		StopPrice:      convert.DecToQuotation(pr.StopPrice),
+		Direction:      investapi.StopOrderDirection(pr.Direction),
+		AccountId:      pr.AccountId,
+		ExpirationType: investapi.StopOrderExpirationType_STOP_ORDER_EXPIRATION_TYPE_GOOD_TILL_CANCEL,
+		StopOrderType:  investapi.StopOrderType(pr.StopOrderType),
+	}
+}
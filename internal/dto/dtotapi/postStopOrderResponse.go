@@ -0,0 +1,13 @@
+package dtotapi
+
+import "github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+
+type PostStopOrderResponse struct {
+	StopOrderId string
+}
+
+func PostStopOrderResponseToDto(resp *investapi.PostStopOrderResponse) *PostStopOrderResponse {
+	return &PostStopOrderResponse{
+		StopOrderId: resp.StopOrderId,
+	}
+}
@@ -0,0 +1,39 @@
+package dtotapi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+type StopOrderStatus int
+
+const (
+	StopOrderStatusUnspecified StopOrderStatus = iota
+	StopOrderStatusAll
+	StopOrderStatusActive
+	StopOrderStatusExecuted
+	StopOrderStatusCanceled
+	StopOrderStatusExpired
+)
+
+//StopOrdersRequest requests stop orders of account, active ones if status is not specified.
+//Period is applied only if both bounds are set
+type StopOrdersRequest struct {
+	AccountId string
+	Status    StopOrderStatus
+	From      time.Time
+	To        time.Time
+}
+
+func (sr *StopOrdersRequest) ToTinApi() *investapi.GetStopOrdersRequest {
+	req := &investapi.GetStopOrdersRequest{
+		AccountId: sr.AccountId,
+		Status:    investapi.StopOrderStatusOption(sr.Status),
+	}
+	if !sr.From.IsZero() && !sr.To.IsZero() {
+		req.From = timestamppb.New(sr.From)
+		req.To = timestamppb.New(sr.To)
+	}
+	return req
+}
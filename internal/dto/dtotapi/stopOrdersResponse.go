@@ -0,0 +1,53 @@
+package dtotapi
+
+import "github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+
+//StopOrdersResponse contains stop orders of account
+type StopOrdersResponse struct {
+	StopOrders []*StopOrder
+}
+
+type StopOrder struct {
+	StopOrderId     string
+	Figi            string
+	LotsRequested   int64
+	Direction       OrderDirection
+	OrderType       StopOrderType
+	Currency        string
+	StopPrice       *MoneyValue //Activation price of single instrument
+	Status          StopOrderStatus
+	ExchangeOrderId string //Id of exchange order stop order was converted to on execution
+}
+
+//Contains checks is stop order with requested id present in response
+func (sor *StopOrdersResponse) Contains(stopOrderId string) bool {
+	return sor.Find(stopOrderId) != nil
+}
+
+//Find returns stop order with requested id or nil if it is absent in response
+func (sor *StopOrdersResponse) Find(stopOrderId string) *StopOrder {
+	for _, order := range sor.StopOrders {
+		if order.StopOrderId == stopOrderId {
+			return order
+		}
+	}
+	return nil
+}
+
+func StopOrdersResponseToDto(resp *investapi.GetStopOrdersResponse) *StopOrdersResponse {
+	orders := make([]*StopOrder, 0, len(resp.StopOrders))
+	for _, order := range resp.StopOrders {
+		orders = append(orders, &StopOrder{
+			StopOrderId:     order.StopOrderId,
+			Figi:            order.Figi,
+			LotsRequested:   order.LotsRequested,
+			Direction:       OrderDirection(order.Direction),
+			OrderType:       StopOrderType(order.OrderType),
+			Currency:        order.Currency,
+			StopPrice:       MoneyValueToDto(order.StopPrice),
+			Status:          StopOrderStatus(order.Status),
+			ExchangeOrderId: order.ExchangeOrderId,
+		})
+	}
+	return &StopOrdersResponse{StopOrders: orders}
+}
@@ -0,0 +1,21 @@
+package dtotapi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+type TradingSchedulesRequest struct {
+	Exchange string //Exchange name, schedules of all exchanges are returned when empty
+	From     time.Time
+	To       time.Time
+}
+
+func (req *TradingSchedulesRequest) ToTinApi() *investapi.TradingSchedulesRequest {
+	return &investapi.TradingSchedulesRequest{
+		Exchange: req.Exchange,
+		From:     timestamppb.New(req.From),
+		To:       timestamppb.New(req.To),
+	}
+}
@@ -0,0 +1,62 @@
+package dtotapi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+type TradingSchedulesResponse struct {
+	Exchanges []*TradingSchedule
+}
+
+type TradingSchedule struct {
+	Exchange string
+	Days     []*TradingDay
+}
+
+//TradingDay contains session bounds of exchange day, zero time when session is absent
+type TradingDay struct {
+	Date             time.Time
+	IsTradingDay     bool
+	StartTime        time.Time //Start of main session
+	EndTime          time.Time //End of main session
+	EveningStartTime time.Time
+	EveningEndTime   time.Time
+}
+
+//GetSchedule returns schedule of exchange or nil if it's absent
+func (resp *TradingSchedulesResponse) GetSchedule(exchange string) *TradingSchedule {
+	for _, sched := range resp.Exchanges {
+		if sched.Exchange == exchange {
+			return sched
+		}
+	}
+	return nil
+}
+
+func TradingSchedulesResponseToDto(resp *investapi.TradingSchedulesResponse) *TradingSchedulesResponse {
+	exchanges := make([]*TradingSchedule, 0, len(resp.Exchanges))
+	for _, sched := range resp.Exchanges {
+		days := make([]*TradingDay, 0, len(sched.Days))
+		for _, day := range sched.Days {
+			days = append(days, &TradingDay{
+				Date:             timeOrZero(day.Date),
+				IsTradingDay:     day.IsTradingDay,
+				StartTime:        timeOrZero(day.StartTime),
+				EndTime:          timeOrZero(day.EndTime),
+				EveningStartTime: timeOrZero(day.EveningStartTime),
+				EveningEndTime:   timeOrZero(day.EveningEndTime),
+			})
+		}
+		exchanges = append(exchanges, &TradingSchedule{Exchange: sched.Exchange, Days: days})
+	}
+	return &TradingSchedulesResponse{Exchanges: exchanges}
+}
+
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,6 @@
+package dto
+
+//HaltRequest request to halt trading of all algorithms
+type HaltRequest struct {
+	ClosePositions bool `json:"closePositions"` //If true - positions opened by algorithms are closed with market orders
+}
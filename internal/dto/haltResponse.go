@@ -0,0 +1,27 @@
+package dto
+
+type HaltResponse struct {
+	IsHalted   bool                     `json:"isHalted"`
+	Info       string                   `json:"info"`
+	Algorithms []*HaltAlgorithmResponse `json:"algorithms"`
+}
+
+//HaltAlgorithmResponse describes what was done to halt single algorithm
+type HaltAlgorithmResponse struct {
+	AlgorithmID     uint               `json:"algorithmId"`
+	Env             string             `json:"env"`
+	AccountId       string             `json:"accountId"`
+	IsStopped       bool               `json:"isStopped"`
+	CanceledOrders  []string           `json:"canceledOrders"`  //Ids of canceled orders
+	ClosedPositions []*ClosingResponse `json:"closedPositions"` //Orders posted to close positions
+	Errors          []string           `json:"errors"`
+}
+
+//ClosingResponse describes order posted to close position
+type ClosingResponse struct {
+	Figi      string `json:"figi"`
+	Direction string `json:"direction"`
+	LotAmount int64  `json:"lotAmount"`
+	Status    string `json:"status"`
+	Info      string `json:"info"`
+}
@@ -1,7 +1,33 @@
 package dto
 
+import "github.com/shopspring/decimal"
+
 //HistStatInRangeResponse history range analysis result
 type HistStatInRangeResponse struct {
-	BestRes *HistStatResponse `json:"bestRes"` //best stat result
-	Params  map[string]string `json:"params"`  //best stat result parameters
+	BestRes   *HistStatResponse `json:"bestRes"`   //best stat result
+	Params    map[string]string `json:"params"`    //best stat result parameters
+	Top       []*RangeResult    `json:"top"`       //best results with reports ordered from the best one
+	Objective string            `json:"objective"` //ranking objective
+	Currency  string            `json:"currency"`  //currency of ranked values
+	Grid      []*GridRow        `json:"grid"`      //all results, returned when grid format requested
+	Mode      string            `json:"mode"`      //search mode of parameter sets
+	Trials    int               `json:"trials"`    //number of analyzed parameter sets, analysis on part of history included
+}
+
+//RangeResult is a result of algorithm analysis with parameters
+type RangeResult struct {
+	Params map[string]string `json:"params"`
+	Score  decimal.Decimal   `json:"score"` //value of ranking objective
+	Stat   *HistStatResponse `json:"stat"`
+}
+
+//GridRow is a short result of algorithm analysis with parameters, used to plot results of all parameter sets
+type GridRow struct {
+	Params         map[string]string `json:"params"`
+	Score          decimal.Decimal   `json:"score"`
+	Valid          bool              `json:"valid"` //Result satisfies objective constraints and is ranked
+	Balance        decimal.Decimal   `json:"balance"`
+	TradeNum       int               `json:"tradeNum"`
+	Sharpe         decimal.Decimal   `json:"sharpe"`
+	MaxDrawdownPct decimal.Decimal   `json:"maxDrawdownPct"`
 }
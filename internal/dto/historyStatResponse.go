@@ -9,9 +9,11 @@ import (
 //CurBalance is result balance by the end algorithm simulation.
 //If there are not sold instruments, price is taken from the last data and converted to currency
 type HistStatResponse struct {
-	BuyOpNum   uint                       `json:"buyOpNum"`   //Number of buy operations
-	SellOpNum  uint                       `json:"sellOpNum"`  //Number of sell operations
-	CurBalance map[string]decimal.Decimal `json:"curBalance"` //Result profit
+	BuyOpNum     uint                       `json:"buyOpNum"`     //Number of buy operations
+	SellOpNum    uint                       `json:"sellOpNum"`    //Number of sell operations
+	ExpiredOpNum uint                       `json:"expiredOpNum"` //Number of orders expired unfilled
+	CurBalance   map[string]decimal.Decimal `json:"curBalance"`   //Result profit
+	Report       *BacktestReport            `json:"report"`       //Detailed simulation report
 }
 
 //HistStatIdDto represents auxiliary dto used by range analysis
@@ -12,6 +12,6 @@ type InstrumentsInfo struct {
 
 type InstrumentInfo struct {
 	Figi        string          `json:"figi"`     //Instrument figi
-	Amount      int64           `json:"amount"`   //Amount of instrument available
-	BuyPosPrice decimal.Decimal `json:"buyPrice"` //To specify instrument bought price if it is
+	Amount      int64           `json:"amount"`   //Amount of instrument available, negative for short position
+	BuyPosPrice decimal.Decimal `json:"buyPrice"` //To specify instrument bought price if it is (sell price for short position)
 }
@@ -0,0 +1,8 @@
+package dto
+
+//RangeObjective describes how results of range analysis are ranked
+type RangeObjective struct {
+	Type      string `json:"type"`      //balance (default), sharpe or profit_drawdown
+	Currency  string `json:"currency"`  //Currency of ranked values, currency of the first limit by default
+	MinTrades int    `json:"minTrades"` //Results with fewer closed trades are not ranked
+}
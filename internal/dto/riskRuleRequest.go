@@ -0,0 +1,22 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+//RiskRuleRequest request to create or replace account risk rule
+type RiskRuleRequest struct {
+	AccountId string          `json:"accountId"`
+	Env       string          `json:"env"` //Environment of account: PROD (default) or SANDBOX
+	Type      string          `json:"type"`
+	Currency  string          `json:"currency"` //Required for money rules: MAX_EXPOSURE, MAX_DAILY_LOSS
+	Value     decimal.Decimal `json:"value"`
+}
+
+//RiskRulesRequest request to list risk rules of account
+type RiskRulesRequest struct {
+	AccountId string `form:"accountId"`
+}
+
+//DeleteRiskRuleRequest request to remove risk rule
+type DeleteRiskRuleRequest struct {
+	RuleId uint `form:"ruleId"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+type RiskRuleResponse struct {
+	ID        uint            `json:"id"`
+	AccountId string          `json:"accountId"`
+	Env       string          `json:"env"`
+	Type      string          `json:"type"`
+	Currency  string          `json:"currency"`
+	Value     decimal.Decimal `json:"value"`
+}
+
+type RiskRulesResponse struct {
+	Rules []*RiskRuleResponse `json:"rules"`
+}
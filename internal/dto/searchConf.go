@@ -0,0 +1,21 @@
+package dto
+
+//SearchConf describes how parameter sets are chosen from ranges of varied parameters in range analysis
+type SearchConf struct {
+	Mode        string `json:"mode"`        //grid (default), random, halving or genetic
+	Trials      int    `json:"trials"`      //Budget of analyzed parameter sets, 100 by default; grid is limited only when set
+	Seed        int64  `json:"seed"`        //Optional - seed of random choice to repeat search
+	Eta         int    `json:"eta"`         //Halving - only 1/eta of the best parameter sets proceed to the next rung, 3 by default
+	Population  int    `json:"population"`  //Genetic - number of parameter sets in generation, 10 by default
+	Concurrency int    `json:"concurrency"` //Number of parameter sets analyzed in parallel, 18 by default
+}
+
+//RangeProgress is a line of streamed range analysis: result of analyzed parameter set, final result or error
+type RangeProgress struct {
+	Trial    int                      `json:"trial,omitempty"`    //Sequence number of analyzed parameter set
+	Stage    int                      `json:"stage"`              //Halving rung or genetic generation
+	Fidelity float64                  `json:"fidelity,omitempty"` //Part of history used for analysis, 1 is whole history
+	Row      *GridRow                 `json:"row,omitempty"`      //Result of analyzed parameter set
+	Result   *HistStatInRangeResponse `json:"result,omitempty"`   //Final result, the last line of stream
+	Error    string                   `json:"error,omitempty"`
+}
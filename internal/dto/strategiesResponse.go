@@ -0,0 +1,23 @@
+package dto
+
+//StrategiesResponse represents list of registered strategies
+type StrategiesResponse struct {
+	Strategies []*StrategyResponse `json:"strategies"`
+}
+
+type StrategyResponse struct {
+	Name           string               `json:"name"`           //Strategy name to use in algorithm requests
+	Description    string               `json:"description"`    //Human-readable strategy description
+	RangeSupported bool                 `json:"rangeSupported"` //Is strategy supports /history/analyze/range requests
+	Params         []*ParamSpecResponse `json:"params"`         //Parameters accepted by strategy
+}
+
+type ParamSpecResponse struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required"`
+	Min         string `json:"min,omitempty"`
+	Max         string `json:"max,omitempty"`
+}
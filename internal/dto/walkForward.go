@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//WalkForwardRequest is a range analysis request performed on rolling windows of loaded history.
+//Parameters are chosen on in-sample window by objective and validated on the following out-of-sample window
+type WalkForwardRequest struct {
+	CreateAlgorithmRequest
+	InSampleDays  int `json:"inSampleDays"`  //Length of optimization window
+	OutSampleDays int `json:"outSampleDays"` //Length of validation window
+	StepDays      int `json:"stepDays"`      //Optional - shift of windows between folds, equals to out-of-sample length by default
+}
+
+//WalkForwardResponse walk-forward analysis result
+type WalkForwardResponse struct {
+	Folds       []*WalkForwardFold `json:"folds"`
+	OutOfSample *HistStatResponse  `json:"outOfSample"` //Stitched result of all out-of-sample windows
+	Stability   []*ParamStability  `json:"stability"`   //Stability of chosen varied parameters between folds
+	Objective   string             `json:"objective"`
+	Currency    string             `json:"currency"`
+}
+
+//WalkForwardFold is a result of single optimization and validation step
+type WalkForwardFold struct {
+	InSampleStart  time.Time         `json:"inSampleStart"`
+	InSampleEnd    time.Time         `json:"inSampleEnd"`
+	OutSampleStart time.Time         `json:"outSampleStart"`
+	OutSampleEnd   time.Time         `json:"outSampleEnd"`
+	Params         map[string]string `json:"params"` //Parameters chosen on in-sample window, empty if no result satisfies objective
+	InSampleScore  decimal.Decimal   `json:"inSampleScore"`
+	OutSampleScore decimal.Decimal   `json:"outSampleScore"`
+	OutSample      *HistStatResponse `json:"outSample"`
+}
+
+//ParamStability describes how chosen parameter value changes between folds
+type ParamStability struct {
+	Name     string           `json:"name"`
+	Values   []string         `json:"values"`   //Chosen values by folds
+	Distinct int              `json:"distinct"` //Number of distinct chosen values
+	Changes  int              `json:"changes"`  //Number of value changes between consecutive folds
+	Mean     *decimal.Decimal `json:"mean"`     //Mean of chosen values, only for numeric parameters
+	StdDev   *decimal.Decimal `json:"stdDev"`   //Standard deviation of chosen values, only for numeric parameters
+}
@@ -36,18 +36,35 @@ type Action struct {
 	AccountID      string          //Filled on init;
 	Direction      ActionDirection //Filled by algorithm on request; Direction of operation buy/sell
 	InstrFigi      string          //Filled by algorithm (required); instrument figi to buy/sell
-	LotAmount      int64           //Filled by algorithm (optional); amount of instrument to sell in case of sell
+	LotAmount      int64           //Filled by algorithm (optional); amount of instrument to sell in case of sell or to buy to cover short position
+	Short          bool            `gorm:"default:false"` //Filled by algorithm (optional); sell opens short position - trader checks margin and sizes it by limit if LotAmount not set
 	OrderType      OrderType       //Filled by algorithm (required); type of order - is it limited request or market
 	Status         ActionStatus    //Filled by algorithm as Created, then trader update it status; Represents current order trade status
 	Info           string          //Filled by trader; failed details etc.
 	Currency       string          //Filled by algorithm (required); real currency used for buy/sell
 	TotalPrice     decimal.Decimal `gorm:"type:numeric"` //Filled by trader; real full amount with taxes
 	ReqPrice       decimal.Decimal `gorm:"type:numeric"` //Filled by algorithm (optionally); Price of position for limited order
+	TriggerPrice   decimal.Decimal `gorm:"type:numeric"` //Filled by algorithm (optionally); Estimated execution price of market order triggered by stop - history trader fills order by it
 	PositionPrice  decimal.Decimal `gorm:"type:numeric"` //Filled by trader; Average position price returned from Tinkoff API - may be used by algorithm
 	LotsExecuted   int64           `gorm:"default:0"`    //Filled by trader; Number of lots executed
+	StopLoss       decimal.Decimal `gorm:"type:numeric"` //Filled by algorithm (optional); Price drop in percents from buy fill price (growth from short sell fill price) to place broker stop loss order
+	TakeProfit     decimal.Decimal `gorm:"type:numeric"` //Filled by algorithm (optional); Price growth in percents from buy fill price (drop from short sell fill price) to place broker take profit order
 	ExpirationTime time.Time       //Filled by algorithm (optional); Expiration time of order - if order is Partially filled and expired - cancel will be sent
 	OrderId        string          //Filled by trader; Order id returned by Tinkoff API
 	RetrievedAt    time.Time       //Filled by data processor (if any); Time when data was retrieved from API
 	CreatedAt      time.Time       //Filled by gorm on insert
 	UpdatedAt      time.Time       //Filled by gorm on update
 }
+
+//ExecutedAmount returns signed amount of executed lots: positive for buy and negative for sell.
+//Canceled or failed order may be partially executed; successful action without LotsExecuted is treated as fully executed
+func (a *Action) ExecutedAmount() int64 {
+	lots := a.LotsExecuted
+	if a.Status == Success && lots == 0 {
+		lots = a.LotAmount
+	}
+	if a.Direction == Sell {
+		return -lots
+	}
+	return lots
+}
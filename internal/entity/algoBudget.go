@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//AlgoBudget is money of algorithm in single currency tracked by trader across orders.
+//Budget starts from configured money limit, buys spend it and sells return money to it
+type AlgoBudget struct {
+	ID          uint            //Filled on save
+	AlgorithmID uint            `gorm:"uniqueIndex:idx_algo_budget"`
+	Currency    string          `gorm:"uniqueIndex:idx_algo_budget"`
+	Limit       decimal.Decimal `gorm:"type:numeric"` //Configured money limit - initial cash of algorithm
+	Cash        decimal.Decimal `gorm:"type:numeric"` //Limit minus money spent by buys plus money received by sells
+	Reserved    decimal.Decimal `gorm:"type:numeric"` //Money reserved by posted and not completed buy orders
+	UpdatedAt   time.Time       //Filled by gorm on update
+}
+
+//Available returns money available for new buy orders
+func (ab *AlgoBudget) Available() decimal.Decimal {
+	return ab.Cash.Sub(ab.Reserved)
+}
+
+func (ab *AlgoBudget) ToDto() *dto.BudgetResponse {
+	return &dto.BudgetResponse{
+		Currency:  ab.Currency,
+		Limit:     ab.Limit,
+		Cash:      ab.Cash,
+		Reserved:  ab.Reserved,
+		Available: ab.Available(),
+	}
+}
@@ -9,16 +9,26 @@ import (
 	"gorm.io/gorm"
 )
 
+//Environment represents trading environment which algorithm runs in
+type Environment string
+
+const (
+	ProdEnv    Environment = "PROD"
+	SandboxEnv Environment = "SANDBOX"
+)
+
 //Algorithm represents full algorithm configuration
 type Algorithm struct {
 	gorm.Model
 	Strategy    string         //Name of strategy
 	AccountId   string         //Account identity
-	Figis       pq.StringArray `gorm:"type:text[]"` //List of figis to operate
+	Env         Environment    `gorm:"default:PROD"` //Environment of running algorithm - used to restore algorithm after restart
+	Figis       pq.StringArray `gorm:"type:text[]"`  //List of figis to operate
 	MoneyLimits []*MoneyLimit  //OneToMany List of money limits to operate
 	Params      []*Param       //OneToMany List of algorithm parameters
-	CtxParams   []*CtxParam    //OneToMany Context algorithm parameters required to save/restore state TODO not implemeted!
+	CtxParams   []*CtxParam    //OneToMany Context algorithm parameters required to save/restore state
 	Actions     []*Action      //OneToMany List of actions made by algorithm
+	StopOrders  []*StopOrder   //OneToMany List of broker stop orders protecting algorithm positions
 	IsActive    bool           //Algorithm activity state, if running - true, else - false
 }
 
@@ -107,11 +117,22 @@ func (alg *Algorithm) CopyNoParam() *Algorithm {
 		Figis:       alg.Figis,
 		MoneyLimits: alg.MoneyLimits, //Copy is ok - not modifiable
 		AccountId:   alg.AccountId,
+		Env:         alg.Env,
 		CtxParams:   alg.CtxParams,
 		IsActive:    alg.IsActive,
 	}
 }
 
+//GetParam returns Param by param name and flag is requested parameter exits
+func (alg *Algorithm) GetParam(paramName string) (*Param, bool) {
+	for _, param := range alg.Params {
+		if param.Key == paramName {
+			return param, true
+		}
+	}
+	return nil, false
+}
+
 //GetCtxParam returns CtxParam by param name and flag is requested parameter exits
 func (alg *Algorithm) GetCtxParam(paramName string) (*CtxParam, bool) {
 	for _, param := range alg.CtxParams {
@@ -122,6 +143,15 @@ func (alg *Algorithm) GetCtxParam(paramName string) (*CtxParam, bool) {
 	return nil, false
 }
 
+//SetCtxParam sets value of CtxParam by param name, adds new CtxParam if it not exists
+func (alg *Algorithm) SetCtxParam(paramName string, value string) {
+	if param, ok := alg.GetCtxParam(paramName); ok {
+		param.Value = value
+		return
+	}
+	alg.CtxParams = append(alg.CtxParams, &CtxParam{AlgorithmID: alg.ID, Key: paramName, Value: value})
+}
+
 func AlgorithmFromDto(req *dto.CreateAlgorithmRequest) *Algorithm {
 	params := make([]*Param, 0, len(req.Params))
 	for key, val := range req.Params {
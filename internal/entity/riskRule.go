@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type RiskRuleType string
+
+const (
+	MaxExposure     RiskRuleType = "MAX_EXPOSURE"       //Max gross money amount of instrument positions in currency, including posted order
+	MaxFigiShare    RiskRuleType = "MAX_FIGI_SHARE"     //Max share in percents of single instrument in portfolio value in its currency
+	MaxOrdersPerMin RiskRuleType = "MAX_ORDERS_PER_MIN" //Max number of orders posted by all algorithms of account in a minute
+	MaxDailyLoss    RiskRuleType = "MAX_DAILY_LOSS"     //Max drop of portfolio value in currency since start of the day - halts all algorithms of account
+)
+
+//RiskRule represents account-wide risk rule checked by trader before posting order of any algorithm on the account.
+//Single rule of each type may be set for account (and currency for money rules).
+//Daily loss rule keeps its day state, so it survives application restart
+type RiskRule struct {
+	ID            uint            //Filled on save
+	AccountID     string          `gorm:"uniqueIndex:idx_risk_rule"` //Account rule applied to
+	Env           Environment     `gorm:"default:PROD"`              //Environment of account - rules are processed by risk manager of its environment
+	Type          RiskRuleType    `gorm:"uniqueIndex:idx_risk_rule"` //Type of rule
+	Currency      string          `gorm:"uniqueIndex:idx_risk_rule"` //Currency of money rules (exposure, daily loss); empty for others
+	Value         decimal.Decimal `gorm:"type:numeric"`              //Limit value: money amount, percents or number of orders depending on type
+	DayStart      string          //Day of DayStartValue in format 2006-01-02, daily loss rule only
+	DayStartValue decimal.Decimal `gorm:"type:numeric"` //Portfolio value in currency at start of the day, daily loss rule only
+	HaltedDay     string          //Day when account trading halted by daily loss rule in format 2006-01-02
+	CreatedAt     time.Time       //Filled by gorm on insert
+	UpdatedAt     time.Time       //Filled by gorm on update
+}
+
+func (rr *RiskRule) ToDto() *dto.RiskRuleResponse {
+	return &dto.RiskRuleResponse{
+		ID:        rr.ID,
+		AccountId: rr.AccountID,
+		Env:       string(rr.Env),
+		Type:      string(rr.Type),
+		Currency:  rr.Currency,
+		Value:     rr.Value,
+	}
+}
+
+func RiskRuleFromDto(req *dto.RiskRuleRequest) *RiskRule {
+	return &RiskRule{
+		AccountID: req.AccountId,
+		Env:       Environment(req.Env),
+		Type:      RiskRuleType(req.Type),
+		Currency:  req.Currency,
+		Value:     req.Value,
+	}
+}
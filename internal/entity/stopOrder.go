@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type StopOrderStatus string
+type StopOrderType string
+
+const (
+	StopActive   StopOrderStatus = "ACTIVE"
+	StopCanceled StopOrderStatus = "CANCELED"
+	StopExecuted StopOrderStatus = "EXECUTED"
+)
+
+const (
+	StopLoss   StopOrderType = "STOP_LOSS"
+	TakeProfit StopOrderType = "TAKE_PROFIT"
+)
+
+//StopOrder represents broker side stop order placed by trader after buy fill to protect bought position
+//or after short sell fill to protect short position. Stop order is canceled when algorithm closes position
+type StopOrder struct {
+	ID          uint            //Filled on save
+	AlgorithmID uint            //Algorithm which position is protected
+	ActionID    uint            //Buy action which position is protected
+	AccountID   string          //Account of position
+	InstrFigi   string          //Instrument figi to sell
+	Type        StopOrderType   //Stop loss or take profit
+	StopPrice   decimal.Decimal `gorm:"type:numeric"` //Activation price of single instrument
+	LotAmount   int64           //Amount of lots to sell (to buy for short position)
+	Short       bool            `gorm:"default:false"` //Stop order protects short position - buy order is made on activation
+	Currency    string          //Currency of instrument
+	StopOrderId string          //Stop order id returned by Tinkoff API
+	Status      StopOrderStatus //Current stop order status
+	CreatedAt   time.Time       //Filled by gorm on insert
+	UpdatedAt   time.Time       //Filled by gorm on update
+}
@@ -0,0 +1,14 @@
+package errors
+
+//ValidationErr happens when request parameters have wrong format or values
+type ValidationErr struct {
+	msg string
+}
+
+func (err ValidationErr) Error() string {
+	return err.msg
+}
+
+func NewValidationErr(msg string) ValidationErr {
+	return ValidationErr{msg: msg}
+}
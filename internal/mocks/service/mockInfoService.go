@@ -128,6 +128,36 @@ func (mr *MockInfoSrvMockRecorder) GetLastPrices(figis, ctx interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastPrices", reflect.TypeOf((*MockInfoSrv)(nil).GetLastPrices), figis, ctx)
 }
 
+// GetMarginAttributes mocks base method.
+func (m *MockInfoSrv) GetMarginAttributes(req *dtotapi.MarginAttributesRequest, ctx context.Context) (*dtotapi.MarginAttributesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMarginAttributes", req, ctx)
+	ret0, _ := ret[0].(*dtotapi.MarginAttributesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMarginAttributes indicates an expected call of GetMarginAttributes.
+func (mr *MockInfoSrvMockRecorder) GetMarginAttributes(req, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarginAttributes", reflect.TypeOf((*MockInfoSrv)(nil).GetMarginAttributes), req, ctx)
+}
+
+// GetOperations mocks base method.
+func (m *MockInfoSrv) GetOperations(req *dtotapi.OperationsRequest, ctx context.Context) (*dtotapi.OperationsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperations", req, ctx)
+	ret0, _ := ret[0].(*dtotapi.OperationsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperations indicates an expected call of GetOperations.
+func (mr *MockInfoSrvMockRecorder) GetOperations(req, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperations", reflect.TypeOf((*MockInfoSrv)(nil).GetOperations), req, ctx)
+}
+
 // GetOrderState mocks base method.
 func (m *MockInfoSrv) GetOrderState(req *dtotapi.OrderStateRequest, ctx context.Context) (*dtotapi.OrderStateResponse, error) {
 	m.ctrl.T.Helper()
@@ -157,3 +187,18 @@ func (mr *MockInfoSrvMockRecorder) GetPositions(req, ctx interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPositions", reflect.TypeOf((*MockInfoSrv)(nil).GetPositions), req, ctx)
 }
+
+// GetTradingSchedules mocks base method.
+func (m *MockInfoSrv) GetTradingSchedules(req *dtotapi.TradingSchedulesRequest, ctx context.Context) (*dtotapi.TradingSchedulesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTradingSchedules", req, ctx)
+	ret0, _ := ret[0].(*dtotapi.TradingSchedulesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTradingSchedules indicates an expected call of GetTradingSchedules.
+func (mr *MockInfoSrvMockRecorder) GetTradingSchedules(req, ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTradingSchedules", reflect.TypeOf((*MockInfoSrv)(nil).GetTradingSchedules), req, ctx)
+}
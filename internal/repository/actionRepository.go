@@ -11,6 +11,8 @@ import (
 type ActionRepository interface {
 	Save(action *entity.Action) error
 	UpdateStatusWithMsg(id uint, status entity.ActionStatus, msg string) error
+	//FindExecutedLots returns net amount of lots executed by algorithm orders by figi: bought minus sold
+	FindExecutedLots(algoId uint) (map[string]int64, error)
 }
 
 type PgActionRepository struct {
@@ -37,6 +39,23 @@ func (rep *PgActionRepository) UpdateStatusWithMsg(id uint, status entity.Action
 	return rep.db.Model(&entity.Action{}).Where("id = ?", id).Updates(entity.Action{Status: status, Info: msg}).Error
 }
 
+func (rep *PgActionRepository) FindExecutedLots(algoId uint) (map[string]int64, error) {
+	var rows []struct {
+		InstrFigi string
+		Amount    int64
+	}
+	sql := "select instr_figi, sum(case when direction = ? then lots_executed else -lots_executed end) as amount " +
+		"from actions where algorithm_id = ? and lots_executed > 0 group by instr_figi"
+	if err := rep.db.Raw(sql, entity.Buy, algoId).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	res := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		res[row.InstrFigi] = row.Amount
+	}
+	return res, nil
+}
+
 func NewActionRepository(db *gorm.DB) ActionRepository {
 	return &PgActionRepository{db: db}
 }
@@ -10,6 +10,11 @@ import (
 type AlgoRepository interface {
 	Save(algo *entity.Algorithm) error
 	SetActiveStatus(id uint, isActive bool) error
+	//SaveState persists algorithm context parameters together with the action changed the state in one transaction
+	SaveState(action *entity.Action, ctxParams []*entity.CtxParam) error
+	//FindActiveByEnv returns active algorithms of the environment with populated limits, params, context, posted actions
+	//and active stop orders
+	FindActiveByEnv(env entity.Environment) ([]*entity.Algorithm, error)
 }
 
 type PgAlgoRepository struct {
@@ -31,6 +36,48 @@ func (ar *PgAlgoRepository) Save(algo *entity.Algorithm) (err error) {
 	return ar.db.Save(algo).Error
 }
 
+func (ar *PgAlgoRepository) SaveState(action *entity.Action, ctxParams []*entity.CtxParam) (err error) {
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			err = errors.ConvertToError(rec)
+		}
+	}()
+	return ar.db.Transaction(func(tx *gorm.DB) error {
+		if action != nil {
+			if err := tx.Save(action).Error; err != nil {
+				return err
+			}
+		}
+		for _, param := range ctxParams {
+			if err := tx.Save(param).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (ar *PgAlgoRepository) FindActiveByEnv(env entity.Environment) ([]*entity.Algorithm, error) {
+	var algos []*entity.Algorithm
+	query := ar.db.Where("is_active = ? and env = ?", true, env)
+	if env == entity.ProdEnv {
+		//Algorithms saved before environment was stored have empty one - they are considered prod
+		query = ar.db.Where("is_active = ? and (env = ? or env = '' or env is null)", true, env)
+	}
+	err := query.
+		Preload("MoneyLimits").
+		Preload("Params").
+		Preload("CtxParams").
+		Preload("Actions", "status = ?", entity.Posted).
+		Preload("StopOrders", "status = ?", entity.StopActive).
+		Find(&algos).Error
+	if err != nil {
+		return nil, err
+	}
+	return algos, nil
+}
+
 func NewAlgoRepository(db *gorm.DB) AlgoRepository {
 	return &PgAlgoRepository{db: db}
 }
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"gorm.io/gorm"
+	"log"
+)
+
+//BudgetRepository provides methods to operate algorithm budgets database data
+type BudgetRepository interface {
+	//Save creates budget or updates existing one
+	Save(budget *entity.AlgoBudget) error
+	FindByAlgorithm(algoId uint) ([]*entity.AlgoBudget, error)
+}
+
+type PgBudgetRepository struct {
+	db *gorm.DB
+}
+
+func (rep *PgBudgetRepository) Save(budget *entity.AlgoBudget) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Save method failed and recovered, info: %s", r)
+			err = errors.ConvertToError(r)
+		}
+	}()
+	return rep.db.Save(budget).Error
+}
+
+func (rep *PgBudgetRepository) FindByAlgorithm(algoId uint) ([]*entity.AlgoBudget, error) {
+	var budgets []*entity.AlgoBudget
+	err := rep.db.Where("algorithm_id = ?", algoId).Order("currency").Find(&budgets).Error
+	return budgets, err
+}
+
+func NewBudgetRepository(db *gorm.DB) BudgetRepository {
+	return &PgBudgetRepository{db: db}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"time"
+)
+
+//WindowHistoryRepository provides read only access to history records of underlying repository in time window [from, to)
+type WindowHistoryRepository struct {
+	rep                 HistoryRepository
+	from                time.Time
+	to                  time.Time
+	findAllByFigisCache collections.SyncMap[string, []entity.History]
+}
+
+func (h *WindowHistoryRepository) ClearAndSaveAll(history []entity.History) error {
+	return errors.NewUnexpectedError("History window is read only")
+}
+
+func (h *WindowHistoryRepository) FindAll() ([]entity.History, error) {
+	hist, err := h.rep.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return h.filter(hist), nil
+}
+
+func (h *WindowHistoryRepository) FindAllByFigis(figis []string) ([]entity.History, error) {
+	strKey := fmt.Sprint(figis)
+	if hist, ok := h.findAllByFigisCache.Get(strKey); ok {
+		return hist, nil
+	}
+	hist, err := h.rep.FindAllByFigis(figis)
+	if err != nil {
+		return nil, err
+	}
+	res := h.filter(hist)
+	h.findAllByFigisCache.Put(strKey, res)
+	return res, nil
+}
+
+func (h *WindowHistoryRepository) filter(hist []entity.History) []entity.History {
+	res := make([]entity.History, 0)
+	for _, rec := range hist {
+		if !rec.Time.Before(h.from) && rec.Time.Before(h.to) {
+			res = append(res, rec)
+		}
+	}
+	return res
+}
+
+//NewWindowHistoryRepository creates repository returning history of underlying repository from the start time inclusive
+//to the end time exclusive
+func NewWindowHistoryRepository(rep HistoryRepository, from time.Time, to time.Time) HistoryRepository {
+	return &WindowHistoryRepository{rep: rep, from: from, to: to,
+		findAllByFigisCache: collections.NewSyncMap[string, []entity.History]()}
+}
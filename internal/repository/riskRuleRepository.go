@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"gorm.io/gorm"
+	"log"
+)
+
+//RiskRuleRepository provides methods to operate account risk rules database data
+type RiskRuleRepository interface {
+	//Save creates rule or replaces value of existing rule with the same account, type and currency
+	Save(rule *entity.RiskRule) error
+	FindByAccount(accountId string) ([]*entity.RiskRule, error)
+	//FindByType returns rules of the type of all accounts of the environment
+	FindByType(env entity.Environment, ruleType entity.RiskRuleType) ([]*entity.RiskRule, error)
+	//UpdateDayState persists day start value and halt day of daily loss rule
+	UpdateDayState(rule *entity.RiskRule) error
+	Delete(id uint) error
+}
+
+type PgRiskRuleRepository struct {
+	db *gorm.DB
+}
+
+func (rep *PgRiskRuleRepository) Save(rule *entity.RiskRule) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Save method failed and recovered, info: %s", r)
+			err = errors.ConvertToError(r)
+		}
+	}()
+	return rep.db.Transaction(func(tx *gorm.DB) error {
+		var existing entity.RiskRule
+		res := tx.Where("account_id = ? and type = ? and currency = ?", rule.AccountID, rule.Type, rule.Currency).
+			Limit(1).Find(&existing)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			//Day state of replaced rule is kept, so changed limit doesn't reset day start value and halt
+			rule.ID = existing.ID
+			rule.CreatedAt = existing.CreatedAt
+			rule.DayStart = existing.DayStart
+			rule.DayStartValue = existing.DayStartValue
+			rule.HaltedDay = existing.HaltedDay
+		}
+		return tx.Save(rule).Error
+	})
+}
+
+func (rep *PgRiskRuleRepository) FindByAccount(accountId string) ([]*entity.RiskRule, error) {
+	var rules []*entity.RiskRule
+	err := rep.db.Where("account_id = ?", accountId).Order("id").Find(&rules).Error
+	return rules, err
+}
+
+func (rep *PgRiskRuleRepository) FindByType(env entity.Environment, ruleType entity.RiskRuleType) ([]*entity.RiskRule, error) {
+	var rules []*entity.RiskRule
+	err := rep.db.Where("env = ? and type = ?", env, ruleType).Order("id").Find(&rules).Error
+	return rules, err
+}
+
+func (rep *PgRiskRuleRepository) UpdateDayState(rule *entity.RiskRule) error {
+	return rep.db.Model(&entity.RiskRule{}).Where("id = ?", rule.ID).Select("day_start", "day_start_value", "halted_day").
+		Updates(entity.RiskRule{DayStart: rule.DayStart, DayStartValue: rule.DayStartValue, HaltedDay: rule.HaltedDay}).Error
+}
+
+func (rep *PgRiskRuleRepository) Delete(id uint) error {
+	res := rep.db.Delete(&entity.RiskRule{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.NewNotFound("Risk rule not found")
+	}
+	return nil
+}
+
+func NewRiskRuleRepository(db *gorm.DB) RiskRuleRepository {
+	return &PgRiskRuleRepository{db: db}
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"gorm.io/gorm"
+	"log"
+)
+
+//StopOrderRepository provides methods to operate broker stop orders database data
+type StopOrderRepository interface {
+	Save(stopOrder *entity.StopOrder) error
+}
+
+type PgStopOrderRepository struct {
+	db *gorm.DB
+}
+
+func (rep *PgStopOrderRepository) Save(stopOrder *entity.StopOrder) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Save method failed and recovered, info: %s", r)
+			err = errors.ConvertToError(r)
+		}
+	}()
+	return rep.db.Save(stopOrder).Error
+}
+
+func NewStopOrderRepository(db *gorm.DB) StopOrderRepository {
+	return &PgStopOrderRepository{db: db}
+}
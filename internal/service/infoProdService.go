@@ -20,6 +20,10 @@ func (is *InfoProdService) GetPositions(req *dtotapi.PositionsRequest, ctx conte
 	return is.tapi.GetProdPositions(req, ctx)
 }
 
+func (is *InfoProdService) GetOperations(req *dtotapi.OperationsRequest, ctx context.Context) (*dtotapi.OperationsResponse, error) {
+	return is.tapi.GetProdOperations(req, ctx)
+}
+
 func (is *InfoProdService) GetOrderState(req *dtotapi.OrderStateRequest, ctx context.Context) (*dtotapi.OrderStateResponse, error) {
 	return is.tapi.GetProdOrderState(req, ctx)
 }
@@ -27,3 +31,7 @@ func (is *InfoProdService) GetOrderState(req *dtotapi.OrderStateRequest, ctx con
 func (is *InfoProdService) GetAccounts(ctx context.Context) (*dtotapi.AccountsResponse, error) {
 	return is.tapi.GetProdAccounts(ctx)
 }
+
+func (is *InfoProdService) GetMarginAttributes(req *dtotapi.MarginAttributesRequest, ctx context.Context) (*dtotapi.MarginAttributesResponse, error) {
+	return is.tapi.GetProdMarginAttributes(req, ctx)
+}
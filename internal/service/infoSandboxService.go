@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/tinapi"
 	"go.uber.org/zap"
 )
@@ -20,6 +21,10 @@ func (is *InfoSandboxService) GetPositions(req *dtotapi.PositionsRequest, ctx co
 	return is.tapi.GetSandboxPositions(req, ctx)
 }
 
+func (is *InfoSandboxService) GetOperations(req *dtotapi.OperationsRequest, ctx context.Context) (*dtotapi.OperationsResponse, error) {
+	return is.tapi.GetSandboxOperations(req, ctx)
+}
+
 func (is *InfoSandboxService) GetOrderState(req *dtotapi.OrderStateRequest, ctx context.Context) (*dtotapi.OrderStateResponse, error) {
 	return is.tapi.GetSandboxOrderState(req, ctx)
 }
@@ -27,3 +32,8 @@ func (is *InfoSandboxService) GetOrderState(req *dtotapi.OrderStateRequest, ctx
 func (is *InfoSandboxService) GetAccounts(ctx context.Context) (*dtotapi.AccountsResponse, error) {
 	return is.tapi.GetSandboxAccounts(ctx)
 }
+
+//GetMarginAttributes not supported by sandbox API - margin trading is not available in sandbox
+func (is *InfoSandboxService) GetMarginAttributes(req *dtotapi.MarginAttributesRequest, ctx context.Context) (*dtotapi.MarginAttributesResponse, error) {
+	return nil, errors.NewNotImplemented()
+}
@@ -32,8 +32,17 @@ type InfoSrv interface {
 	//GetLastPrices returns last price for the instrument
 	GetLastPrices(figis []string, ctx context.Context) (*dtotapi.LastPricesResponse, error)
 
+	//GetTradingSchedules returns exchange trading schedules in time interval
+	GetTradingSchedules(req *dtotapi.TradingSchedulesRequest, ctx context.Context) (*dtotapi.TradingSchedulesResponse, error)
+
 	//GetPositions returns current amount of money and instrument from the requested account
 	GetPositions(req *dtotapi.PositionsRequest, ctx context.Context) (*dtotapi.PositionsResponse, error)
+
+	//GetOperations returns executed operations of the account by instrument for the period
+	GetOperations(req *dtotapi.OperationsRequest, ctx context.Context) (*dtotapi.OperationsResponse, error)
+
+	//GetMarginAttributes returns margin attributes of the account - required to open short positions
+	GetMarginAttributes(req *dtotapi.MarginAttributesRequest, ctx context.Context) (*dtotapi.MarginAttributesResponse, error)
 }
 
 type BaseInfoSrv struct {
@@ -117,6 +126,10 @@ func (i *BaseInfoSrv) GetInstrumentInfoByFigi(figi string, ctx context.Context)
 	return i.tapi.GetInstrumentInfo(&req, ctx)
 }
 
+func (i *BaseInfoSrv) GetTradingSchedules(req *dtotapi.TradingSchedulesRequest, ctx context.Context) (*dtotapi.TradingSchedulesResponse, error) {
+	return i.tapi.GetTradingSchedules(req, ctx)
+}
+
 func (i *BaseInfoSrv) GetLastPrices(figis []string, ctx context.Context) (*dtotapi.LastPricesResponse, error) {
 	req := dtotapi.LastPricesRequest{Figis: figis}
 	return i.tapi.GetLastPrices(&req, ctx)
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/tinapi"
 	"go.uber.org/zap"
 )
@@ -24,6 +25,22 @@ func (ts *TradeProdService) GetOrderStatus(req *dtotapi.OrderStateRequest, ctx c
 	return ts.TinApi.GetProdOrderState(req, ctx)
 }
 
+func (ts *TradeProdService) PostStopOrder(req *dtotapi.PostStopOrderRequest, ctx context.Context) (*dtotapi.PostStopOrderResponse, error) {
+	return ts.TinApi.PostProdStopOrder(req, ctx)
+}
+
+func (ts *TradeProdService) GetStopOrders(req *dtotapi.StopOrdersRequest, ctx context.Context) (*dtotapi.StopOrdersResponse, error) {
+	return ts.TinApi.GetProdStopOrders(req, ctx)
+}
+
+func (ts *TradeProdService) CancelStopOrder(req *dtotapi.CancelStopOrderRequest, ctx context.Context) (*dtotapi.CancelStopOrderResponse, error) {
+	return ts.TinApi.CancelProdStopOrder(req, ctx)
+}
+
+func (ts *TradeProdService) GetTradesStream(accounts []string, ctx context.Context) (investapi.OrdersStreamService_TradesStreamClient, error) {
+	return ts.TinApi.GetOrderStream(accounts, ctx)
+}
+
 func NewTradeProdService(tapi tinapi.Api, logger *zap.SugaredLogger) TradeService {
 	return &TradeProdService{TinApi: tapi, logger: logger}
 }
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/tinapi"
 	"go.uber.org/zap"
 )
@@ -24,6 +26,24 @@ func (ts *TradeSandboxService) GetOrderStatus(req *dtotapi.OrderStateRequest, ct
 	return ts.TinApi.GetSandboxOrderState(req, ctx)
 }
 
+//PostStopOrder not supported by sandbox API - stop orders are available only in prod
+func (ts *TradeSandboxService) PostStopOrder(req *dtotapi.PostStopOrderRequest, ctx context.Context) (*dtotapi.PostStopOrderResponse, error) {
+	return nil, errors.NewNotImplemented()
+}
+
+func (ts *TradeSandboxService) GetStopOrders(req *dtotapi.StopOrdersRequest, ctx context.Context) (*dtotapi.StopOrdersResponse, error) {
+	return nil, errors.NewNotImplemented()
+}
+
+func (ts *TradeSandboxService) CancelStopOrder(req *dtotapi.CancelStopOrderRequest, ctx context.Context) (*dtotapi.CancelStopOrderResponse, error) {
+	return nil, errors.NewNotImplemented()
+}
+
+//GetTradesStream not supported by sandbox API - sandbox orders are checked by polling
+func (ts *TradeSandboxService) GetTradesStream(accounts []string, ctx context.Context) (investapi.OrdersStreamService_TradesStreamClient, error) {
+	return nil, errors.NewNotImplemented()
+}
+
 func NewTradeSandboxSrv(tapi tinapi.Api, logger *zap.SugaredLogger) TradeService {
 	return &TradeSandboxService{TinApi: tapi, logger: logger}
 }
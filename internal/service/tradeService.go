@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
 )
 
 //TradeService provides methods for order management
@@ -15,4 +16,16 @@ type TradeService interface {
 
 	//CancelOrder cancels order and returns cancel result
 	CancelOrder(req *dtotapi.CancelOrderRequest, ctx context.Context) (*dtotapi.CancelOrderResponse, error)
+
+	//PostStopOrder posts broker side stop order executed by market price when stop price is reached
+	PostStopOrder(req *dtotapi.PostStopOrderRequest, ctx context.Context) (*dtotapi.PostStopOrderResponse, error)
+
+	//GetStopOrders returns active stop orders of account
+	GetStopOrders(req *dtotapi.StopOrdersRequest, ctx context.Context) (*dtotapi.StopOrdersResponse, error)
+
+	//CancelStopOrder cancels active stop order
+	CancelStopOrder(req *dtotapi.CancelStopOrderRequest, ctx context.Context) (*dtotapi.CancelStopOrderResponse, error)
+
+	//GetTradesStream returns stream of order trades (executions) of requested accounts
+	GetTradesStream(accounts []string, ctx context.Context) (investapi.OrdersStreamService_TradesStreamClient, error)
 }
@@ -2,52 +2,55 @@ package avr
 
 import (
 	"context"
-	"encoding/json"
-	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/risk"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
 	"github.com/shopspring/decimal"
 	"github.com/tevino/abool/v2"
 	"go.uber.org/zap"
-	"strconv"
 	"time"
 )
 
-//AlgorithmImpl is base implementation of average trading algorithm.
-//Data processor is aggregation part of algorithm and controlled by the algorithm.
-//This implementation supports one subscription and communication with one trader
-//Algorithm based on 2 moving average windows - short and long.
-//Average window values calculated and provided by DataProc
-//Average algorithm just check the difference between windows and compare sign with previous step
-//if difference changes from negative to positive - then there is trend for rising and buy condition is met
-//if difference changes from positive to negative - then there is trend to falling and sell condition is met
+// AlgorithmImpl is base implementation of average trading algorithm.
+// Data processor is aggregation part of algorithm and controlled by the algorithm.
+// This implementation supports one subscription and communication with one trader
+// Algorithm based on 2 moving average windows - short and long.
+// Average window values calculated and provided by DataProc
+// Average algorithm just check the difference between windows and compare sign with previous step
+// if difference changes from negative to positive - then there is trend for rising and buy condition is met
+// if difference changes from positive to negative - then there is trend to falling and sell condition is met
 //
-//Important note! Currently, at start algorithm assumes that there is no available instruments to sell (it may be added in future as parameter or domain.Algorithm)
-//So at start algorithm search for buy conditions and only after that it cat make sell operations
+// Important note! Currently, at start algorithm assumes that there is no available instruments to sell (it may be added in future as parameter or domain.Algorithm)
+// So at start algorithm search for buy conditions and only after that it cat make sell operations.
+// If short selling enabled by parameter - sell conditions without holdings open short position, which is covered by buy conditions
 type AlgorithmImpl struct {
-	id              uint                       //Algorithm id extracted for more convenience
-	isActive        *abool.AtomicBool          //Atomic bool indicating is algorithm active
-	dataProc        DataProc                   //Data processor - provides data as the channel for algorithm
-	accountId       string                     //Account id extracted for more convenience
-	figis           []string                   //List of figis to monitor and use in algorithm
-	limits          []*entity.MoneyLimit       //Limits of money available for algorithm
-	algorithm       *entity.Algorithm          //Link to original object which algorithm based
-	param           map[string]string          //Map of different algorithm configuration parameters (order expiration time etc)
-	aChan           chan *stmodel.ActionReq    //Channel to send order requests to trader
-	arChan          chan *stmodel.ActionResp   //Channel to receive responses from trader about action result
-	stopCh          chan bool                  //Channel to stop algorithm when required
-	buyPrice        map[string]decimal.Decimal //Cache of buy prices made previously (when sell goes after buy - it clears record) - to prevent selling cheaper than previous buy
-	ordExp          time.Duration              //Expiration duration of posted orders - when expiration time passed and order not finished then it will be canceled
-	commission      decimal.Decimal            //Commission on deals to take into account
-	relDerivative   decimal.Decimal
-	stopLossEnabled bool
-	stopLossRel     decimal.Decimal //Relative price limit, when crossed - process market sell
-	ctx             context.Context
-	cancelF         context.CancelFunc
-	instrAmount     map[string]int64 //Initial amount of instruments available
+	id            uint                       //Algorithm id extracted for more convenience
+	isActive      *abool.AtomicBool          //Atomic bool indicating is algorithm active
+	dataProc      DataProc                   //Data processor - provides data as the channel for algorithm
+	accountId     string                     //Account id extracted for more convenience
+	figis         []string                   //List of figis to monitor and use in algorithm
+	limits        []*entity.MoneyLimit       //Limits of money available for algorithm
+	algorithm     *entity.Algorithm          //Link to original object which algorithm based
+	param         map[string]string          //Map of different algorithm configuration parameters (order expiration time etc)
+	aChan         chan *stmodel.ActionReq    //Channel to send order requests to trader
+	arChan        chan *stmodel.ActionResp   //Channel to receive responses from trader about action result
+	stopCh        chan bool                  //Channel to stop algorithm when required
+	buyPrice      map[string]decimal.Decimal //Cache of buy prices made previously (when sell goes after buy - it clears record) - to prevent selling cheaper than previous buy; sell price for short position
+	conf          stbase.Config              //Order parameters common with signal algorithms - expiration, commission, stop loss, short selling
+	relDerivative decimal.Decimal
+	trailing      *risk.TrailingStop //Trailing stop of long positions, its state is persisted with algorithm context
+	gate          *session.Gate      //Trading session gate - suppresses orders outside allowed sessions
+	ctx           context.Context
+	cancelF       context.CancelFunc
+	instrAmount   map[string]int64          //Initial amount of instruments available
+	algRep        repository.AlgoRepository //Repository to persist algorithm state, nil when state must not be saved (history)
+	clock         *stbase.SimClock          //Simulation clock of history trader, disabled in production
 
 	logger *zap.SugaredLogger
 }
@@ -59,11 +62,12 @@ const (
 	waitRes
 )
 
+// Order parameters are common with signal algorithms
 const (
-	OrderExpiration string = "order_expiration"
-	Commission      string = "order_commission"
+	OrderExpiration        = stbase.OrderExpiration
+	Commission             = stbase.Commission
 	RelDerivative   string = "relative_derivative"
-	StopLoss        string = "stop_loss"
+	StopLoss               = stbase.StopLoss
 )
 
 type AlgoData struct {
@@ -81,7 +85,9 @@ func (a *AlgorithmImpl) Subscribe() (*stmodel.Subscription, error) {
 
 	arCh := make(chan *stmodel.ActionResp, 1) //must not block trader, so size = 1
 	a.arChan = arCh
-	return &stmodel.Subscription{AlgoID: a.id, AChan: a.aChan, RChan: a.arChan}, nil
+	sub := &stmodel.Subscription{AlgoID: a.id, AChan: a.aChan, RChan: a.arChan}
+	a.clock.Subscribe(sub)
+	return sub, nil
 }
 
 func (a AlgorithmImpl) IsActive() bool {
@@ -109,12 +115,25 @@ func (a *AlgorithmImpl) procBg(datCh <-chan procData) {
 	defer func() {
 		a.isActive.UnSet()
 		close(a.aChan)
+		a.clock.Close(a.arChan)
 		a.logger.Infof("Stopping algorithm background; ID: %d", a.id)
 	}()
 	statusMap := make(map[string]algoStatus)
 	for _, figi := range a.figis {
 		statusMap[figi] = process
 	}
+	//Restored algorithm may have posted orders - wait for their results before new requests
+	for _, action := range a.algorithm.Actions {
+		if action.Status == entity.Posted {
+			statusMap[action.InstrFigi] = waitRes
+		}
+	}
+	//Positions held before trailing stop was enabled are tracked from the buy price
+	for figi, amount := range a.instrAmount {
+		if amount > 0 {
+			a.trailing.Open(figi, a.buyPrice[figi], time.Time{})
+		}
+	}
 	aDat := AlgoData{ //Algorithm data storing as single thread state
 		statusMap:   statusMap,
 		prev:        make(map[string]decimal.Decimal),
@@ -122,16 +141,19 @@ func (a *AlgorithmImpl) procBg(datCh <-chan procData) {
 	}
 	a.logger.Infof("Starting background algorithm processing; id: %d , strategy: avr , limits: %+v",
 		a.id, a.limits)
+	stopped := false //Set when trader response processing failed
+	processResp := func(resp *stmodel.ActionResp) {
+		if err := a.processTraderResp(&aDat, resp); err != nil {
+			a.logger.Errorf("Error while trader response processing:\n%s", err)
+			stopped = true
+		}
+	}
 	for {
 		select {
 		case resp, ok := <-a.arChan:
 			a.logger.Debugf("Receiving response, channel state: %t , response: %+v", ok, *resp.Action)
 			if ok {
-				err := a.processTraderResp(&aDat, resp)
-				if err != nil {
-					a.logger.Errorf("Error while trader response processing:\n%s", err)
-					return
-				}
+				processResp(resp)
 			} else {
 				a.logger.Warn("Trader closed response channel, stopping algorithm...")
 				return
@@ -139,6 +161,9 @@ func (a *AlgorithmImpl) procBg(datCh <-chan procData) {
 		case pDat, ok := <-datCh:
 			if ok {
 				a.processData(&aDat, &pDat)
+				if !a.clock.Send(a.ctx, pDat.Time, a.arChan, processResp) {
+					return
+				}
 			} else {
 				a.logger.Infof("Closed data processor stream, stopping algorithm...")
 				return
@@ -147,37 +172,38 @@ func (a *AlgorithmImpl) procBg(datCh <-chan procData) {
 			a.logger.Info("Context canceled, stopping...")
 			return
 		}
+		if stopped {
+			return
+		}
 	}
 }
 
-//process response from trade.Trader after requested passed trading stages
+// process response from trade.Trader after requested passed trading stages
 func (a *AlgorithmImpl) processTraderResp(aDat *AlgoData, resp *stmodel.ActionResp) error {
 	action := resp.Action
 	a.logger.Debug("Processing trader response: ", *resp.Action)
-	if resp.Action.Status == entity.Success {
-		iAmount := action.LotAmount
-		if action.Direction == entity.Sell {
-			iAmount = -iAmount
-			//Drops buy price - because the deal has already been completed
-			delete(a.buyPrice, action.InstrFigi)
-		} else {
-			//Checks and update previous buy limit to wait for next sell price no lower than buy price
-			price, ok := a.buyPrice[action.InstrFigi]
-			if ok {
-				//If it's consecutive buy then select maximum price
-				a.buyPrice[action.InstrFigi] = decimal.Max(price, action.PositionPrice)
-			} else {
-				a.buyPrice[action.InstrFigi] = action.PositionPrice
-			}
+	//Holdings are updated by really executed lots - canceled order may be partially filled
+	if iAmount := action.ExecutedAmount(); iAmount != 0 {
+		if action.Status != entity.Success {
+			a.logger.Infof("Order partially executed: %s", action.Info)
 		}
+		//Drops price when position closed, else keeps the highest buy price (the lowest short sell price) to wait for profitable close
+		before := aDat.instrAmount[action.InstrFigi]
 		a.logger.Infof("Incrementing instrument: %s with amount %d", action.InstrFigi, iAmount)
-		aDat.instrAmount[action.InstrFigi] = aDat.instrAmount[action.InstrFigi] + iAmount
+		aDat.instrAmount[action.InstrFigi] = before + iAmount
+		stbase.UpdatePositionPrice(a.buyPrice, before, aDat.instrAmount[action.InstrFigi], action)
+		if aDat.instrAmount[action.InstrFigi] > 0 {
+			a.trailing.Open(action.InstrFigi, action.PositionPrice, action.RetrievedAt)
+		} else {
+			a.trailing.Close(action.InstrFigi)
+		}
 	} else {
 		a.logger.Infof("Operation failed %+v", resp)
 	}
 	a.logger.Infof("Trader response processed, algo data: %+v", aDat)
 	aDat.statusMap[action.InstrFigi] = process
 	a.updateState()
+	stbase.PersistState(a.algRep, a.algorithm, action, a.logger)
 	return nil
 }
 
@@ -188,6 +214,13 @@ func (a *AlgorithmImpl) processData(aDat *AlgoData, pDat *procData) {
 	a.logger.Debugf("Difference, current: %s, prev: %s, price: %s, derivative: %s, rel derivative: %s",
 		currDiff, prevDiff, pDat.Price, pDat.DER, relDer)
 	aDat.prev[pDat.Figi] = currDiff
+	//Trailing stop must see every price to keep the highest one actual
+	_, stopped := a.trailing.Check(&candle.Candle{Figi: pDat.Figi, Time: pDat.Time, Open: pDat.Price, High: pDat.Price,
+		Low: pDat.Price, Close: pDat.Price})
+	if a.trailing.IsChanged() && a.algRep != nil {
+		a.updateState()
+		stbase.PersistState(a.algRep, a.algorithm, nil, a.logger)
+	}
 	status, ok := aDat.statusMap[pDat.Figi]
 	if !ok {
 		aDat.statusMap[pDat.Figi] = process
@@ -195,28 +228,46 @@ func (a *AlgorithmImpl) processData(aDat *AlgoData, pDat *procData) {
 		a.logger.Debugf("Waiting in status: %d", status)
 		return
 	}
+	if !a.gate.CanTrade(pDat.Figi, pDat.Time) {
+		return
+	}
+	if a.gate.MustClose(pDat.Figi, pDat.Time) {
+		a.closeBySession(aDat, pDat)
+		return
+	}
+	if stopped && aDat.instrAmount[pDat.Figi] > 0 {
+		a.logger.Infof("Trailing stop reached; Current price: %s", pDat.Price)
+		a.doSell(aDat, pDat, entity.Market)
+		return
+	}
 	buyPrice, ok := a.buyPrice[pDat.Figi]
 
 	//If previous difference value not exists finish method
 	if !prevExists {
 		return
 	}
+	//Buy if exists previous difference by figi, short window derivative is positive AND
+	//Go from negative to positive difference (short window crossing long) OR price growing now fast enough (by rel derivative setting)
+	buyCond := pDat.DER.IsPositive() && ((prevDiff.IsNegative() && currDiff.IsPositive()) ||
+		(prevDiff.IsPositive() && currDiff.IsPositive() && relDer.GreaterThan(a.relDerivative)))
+	//Sell if exists previous difference by figi, short window derivative is negative AND
+	//Go from positive to negative difference (short window crossing long) OR price dropping
+	sellCond := pDat.DER.IsNegative() && ((prevDiff.IsPositive() && currDiff.IsNegative()) || (currDiff.IsNegative() && prevDiff.IsNegative()))
+	if aDat.instrAmount[pDat.Figi] < 0 {
+		a.processShort(aDat, pDat, buyCond)
+		return
+	}
 	switch true {
-	case pDat.DER.IsPositive() && ((prevDiff.IsNegative() && currDiff.IsPositive()) ||
-		(prevDiff.IsPositive() && currDiff.IsPositive() && relDer.GreaterThan(a.relDerivative))):
-		//Buy if exists previous difference by figi, short window derivative is positive AND
-		//Go from negative to positive difference (short window crossing long) OR price growing now fast enough (by rel derivative setting)
+	case buyCond:
 		if ok {
 			a.logger.Info("Previous buy operation not finished with price: ", buyPrice, "; waiting for sell operation...")
-		} else {
+		} else if a.gate.CanOpen(pDat.Figi, pDat.Time) {
 			a.doBuy(aDat, pDat)
 		}
-	case pDat.DER.IsNegative() && (!ok || buyPrice.LessThan(pDat.Price)) &&
-		((prevDiff.IsPositive() && currDiff.IsNegative()) || (currDiff.IsNegative() && prevDiff.IsNegative())):
-		//Sell if exists previous difference by figi, short window derivative is negative, buy price not found or lower than current AND
-		//Go from positive to negative difference (short window crossing long) OR price dropping
+	case sellCond && (!ok || buyPrice.LessThan(pDat.Price)):
+		//Sell if buy price not found or lower than current
 		if ok {
-			buyPriceComm := buyPrice.Mul(decimal.NewFromInt(1).Add(a.commission.Mul(decimal.NewFromInt(2))))
+			buyPriceComm := buyPrice.Mul(decimal.NewFromInt(1).Add(a.conf.Commission.Mul(decimal.NewFromInt(2))))
 			a.logger.Infof("Buy price found. Current price: %s, buy price: %s, buy with percents: %s", pDat.Price, buyPrice, buyPriceComm)
 			if buyPriceComm.GreaterThanOrEqual(pDat.Price) {
 				a.logger.Infof("Buy price %s is greater than current %s plus 2x commissions - not good enough, waiting better...",
@@ -225,24 +276,59 @@ func (a *AlgorithmImpl) processData(aDat *AlgoData, pDat *procData) {
 			}
 		}
 		a.doSell(aDat, pDat, entity.Limited)
-	case ok && pDat.DER.IsNegative() && a.stopLossEnabled && pDat.SAV.LessThanOrEqual(buyPrice.Mul(a.stopLossRel)):
+	case ok && pDat.DER.IsNegative() && a.conf.StopLossEnabled && pDat.SAV.LessThanOrEqual(buyPrice.Mul(a.conf.StopLossRel)):
 		//If current price lower than stop loss - sell using market order
-		a.logger.Infof("Stop loss reached; Current price: %s, stop loss price: %s", pDat.Price, buyPrice.Mul(a.stopLossRel))
+		a.logger.Infof("Stop loss reached; Current price: %s, stop loss price: %s", pDat.Price, buyPrice.Mul(a.conf.StopLossRel))
 		a.doSell(aDat, pDat, entity.Market)
 	}
 }
 
+// processShort covers short position by buy conditions when price is lower than sell price minus 2x commissions
+// or by market when stop loss is enabled and price rises above it
+func (a *AlgorithmImpl) processShort(aDat *AlgoData, pDat *procData, buyCond bool) {
+	sellPrice, ok := a.buyPrice[pDat.Figi]
+	switch true {
+	case ok && a.conf.StopLossEnabled && pDat.SAV.GreaterThanOrEqual(sellPrice.Mul(a.conf.StopLossUpRel)):
+		a.logger.Infof("Short stop loss reached; Current price: %s, stop loss price: %s", pDat.Price, sellPrice.Mul(a.conf.StopLossUpRel))
+		a.doCover(aDat, pDat, entity.Market)
+	case buyCond:
+		if ok {
+			sellPriceComm := sellPrice.Mul(decimal.NewFromInt(1).Sub(a.conf.Commission.Mul(decimal.NewFromInt(2))))
+			if sellPriceComm.LessThanOrEqual(pDat.Price) {
+				a.logger.Infof("Sell price %s minus 2x commissions is lower than current %s - not good enough, waiting better...",
+					sellPriceComm, pDat.Price)
+				return
+			}
+		}
+		a.doCover(aDat, pDat, entity.Limited)
+	}
+}
+
+// closeBySession closes long or short position by market as allowed trading session is ending
+func (a *AlgorithmImpl) closeBySession(aDat *AlgoData, pDat *procData) {
+	switch amount := aDat.instrAmount[pDat.Figi]; {
+	case amount > 0:
+		a.logger.Infof("Session is ending, closing position of %s by market", pDat.Figi)
+		a.doSell(aDat, pDat, entity.Market)
+	case amount < 0:
+		a.logger.Infof("Session is ending, covering short position of %s by market", pDat.Figi)
+		a.doCover(aDat, pDat, entity.Market)
+	}
+}
+
 func (a *AlgorithmImpl) doBuy(aDat *AlgoData, pDat *procData) {
 	action := entity.Action{
 		AlgorithmID:    a.id,
 		Direction:      entity.Buy,
 		InstrFigi:      pDat.Figi,
 		ReqPrice:       pDat.Price,
-		ExpirationTime: time.Now().Add(a.ordExp),
+		ExpirationTime: time.Now().Add(a.conf.OrderExp),
 		Status:         entity.Created,
 		OrderType:      entity.Limited,
 		RetrievedAt:    pDat.Time,
 		AccountID:      a.accountId,
+		StopLoss:       a.conf.BrokerStopLoss,
+		TakeProfit:     a.conf.TakeProfit,
 	}
 	a.logger.Infof("Conditions for Buy, requesting action: %+v", action)
 	a.aChan <- a.makeReq(&action)
@@ -258,7 +344,7 @@ func (a *AlgorithmImpl) doSell(aDat *AlgoData, pDat *procData, orderType entity.
 			InstrFigi:      pDat.Figi,
 			LotAmount:      amount,
 			ReqPrice:       pDat.Price,
-			ExpirationTime: time.Now().Add(a.ordExp),
+			ExpirationTime: time.Now().Add(a.conf.OrderExp),
 			Status:         entity.Created,
 			OrderType:      orderType,
 			RetrievedAt:    pDat.Time,
@@ -267,36 +353,54 @@ func (a *AlgorithmImpl) doSell(aDat *AlgoData, pDat *procData, orderType entity.
 		a.logger.Infof("Conditions for Sell, requesting action: %+v", action)
 		a.aChan <- a.makeReq(&action)
 		aDat.statusMap[pDat.Figi] = waitRes
+	} else if a.conf.ShortEnabled && a.gate.CanOpen(pDat.Figi, pDat.Time) {
+		//No holdings - open short position, trader defines amount by money limit
+		action := entity.Action{
+			AlgorithmID:    a.id,
+			Direction:      entity.Sell,
+			InstrFigi:      pDat.Figi,
+			Short:          true,
+			ReqPrice:       pDat.Price,
+			ExpirationTime: time.Now().Add(a.conf.OrderExp),
+			Status:         entity.Created,
+			OrderType:      orderType,
+			RetrievedAt:    pDat.Time,
+			AccountID:      a.accountId,
+			StopLoss:       a.conf.BrokerStopLoss,
+			TakeProfit:     a.conf.TakeProfit,
+		}
+		a.logger.Infof("Conditions for short Sell, requesting action: %+v", action)
+		a.aChan <- a.makeReq(&action)
+		aDat.statusMap[pDat.Figi] = waitRes
 	}
 }
 
-//updateState updates algorithm context parameters from current state
+// doCover requests buy of whole short position amount
+func (a *AlgorithmImpl) doCover(aDat *AlgoData, pDat *procData, orderType entity.OrderType) {
+	action := entity.Action{
+		AlgorithmID:    a.id,
+		Direction:      entity.Buy,
+		InstrFigi:      pDat.Figi,
+		LotAmount:      -aDat.instrAmount[pDat.Figi],
+		ReqPrice:       pDat.Price,
+		ExpirationTime: time.Now().Add(a.conf.OrderExp),
+		Status:         entity.Created,
+		OrderType:      orderType,
+		RetrievedAt:    pDat.Time,
+		AccountID:      a.accountId,
+	}
+	a.logger.Infof("Conditions for cover Buy, requesting action: %+v", action)
+	a.aChan <- a.makeReq(&action)
+	aDat.statusMap[pDat.Figi] = waitRes
+}
+
+// updateState updates algorithm context parameters from current state
 func (a *AlgorithmImpl) updateState() {
-	instruments := make([]*dto.InstrumentInfo, 0)
-	for figi, amount := range a.instrAmount {
-		info := dto.InstrumentInfo{
-			Figi:   figi,
-			Amount: amount,
-		}
-		if price, ok := a.buyPrice[figi]; ok {
-			info.BuyPosPrice = price
-		}
-		instruments = append(instruments, &info)
-	}
-	res, err := json.Marshal(instruments)
-	if err == nil {
-		param, ok := a.algorithm.GetCtxParam(dto.InstrAmountField)
-		if !ok {
-			param = &entity.CtxParam{
-				ID:          0,
-				AlgorithmID: a.id,
-				Key:         dto.InstrAmountField,
-				Value:       string(res),
-			}
-			a.algorithm.CtxParams = append(a.algorithm.CtxParams, param)
-		} else {
-			param.Value = string(res)
-		}
+	if err := stbase.SetInstrumentsState(a.algorithm, a.instrAmount, a.buyPrice); err != nil {
+		a.logger.Errorf("Error while marshalling state of algorithm %d: %s", a.id, err)
+	}
+	if err := a.trailing.SetState(a.algorithm); err != nil {
+		a.logger.Errorf("Error while marshalling trailing stop of algorithm %d: %s", a.id, err)
 	}
 }
 
@@ -328,10 +432,13 @@ func (a *AlgorithmImpl) Configure(ctx []*entity.CtxParam) error {
 	}
 	ctxParam := entity.ContextToMap(ctx)
 
-	return configure(ctxParam, &algoState{
+	if err := configure(ctxParam, &algoState{
 		InitAmount: a.instrAmount,
 		BuyPrice:   a.buyPrice,
-	}, a.logger)
+	}, a.logger); err != nil {
+		return err
+	}
+	return a.trailing.Restore(ctxParam)
 }
 
 func (a *AlgorithmImpl) GetParam() map[string]string {
@@ -342,25 +449,27 @@ func (a *AlgorithmImpl) GetAlgorithm() *entity.Algorithm {
 	return a.algorithm
 }
 
-//NewProd constructs new algorithm using production data processor
-func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+// NewProd constructs new algorithm using production data processor
+func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
 	proc, err := newDataProc(algo, infoSrv, logger)
 	if err != nil {
 		return nil, err
 	}
-	return newAvr(algo, logger, proc)
+	return newAvr(algo, algRep, logger, proc, session.NewApiSchedule(infoSrv, logger), false)
 }
 
-//NewSandbox constructs new algorithm using production data processor cause it the same for such algorithm
-func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+// NewSandbox constructs new algorithm using production data processor cause it the same for such algorithm
+func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
 	proc, err := newDataProc(algo, infoSrv, logger)
 	if err != nil {
 		return nil, err
 	}
-	return newAvr(algo, logger, proc)
+	return newAvr(algo, algRep, logger, proc, session.NewApiSchedule(infoSrv, logger), false)
 }
 
-//NewHist constructs new algorithm using history data processor
+// NewHist constructs new algorithm using history data processor and providing simulation clock to trader
 func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogger *zap.SugaredLogger) (stmodel.Algorithm, error) {
 	//New logger with Increased level to suppress history analysis not necessary logging
 	logger := zap.New(rootLogger.Desugar().Core(), zap.IncreaseLevel(zap.WarnLevel)).Sugar()
@@ -368,36 +477,38 @@ func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogg
 	if err != nil {
 		return nil, err
 	}
-	return newAvr(algo, logger, proc)
+	return newAvr(algo, nil, logger, proc, session.StaticSchedule{}, true)
 }
 
-//Main average algorithm constructor
-func newAvr(algo *entity.Algorithm, logger *zap.SugaredLogger, proc DataProc) (stmodel.Algorithm, error) {
+// Main average algorithm constructor
+func newAvr(algo *entity.Algorithm, algRep repository.AlgoRepository, logger *zap.SugaredLogger, proc DataProc,
+	schedule session.Schedule, simClock bool) (stmodel.Algorithm, error) {
 	//Turn params to map for convenience
 	paramMap := entity.ParamsToMap(algo.Params)
-	//Set order expiration time in seconds (when using limited requests), default 5 min
-	ordExpInt := getOrDefaultInt(paramMap, OrderExpiration, 300)
-	//Get stop loss parameter
-	stopLossPercent, stopLossEnabled := getDecimal(paramMap, StopLoss)
-	stopLossC := decimal.NewFromInt(1).Sub(stopLossPercent.Div(decimal.NewFromInt(100)))
+	gate, err := session.NewGate(paramMap, schedule)
+	if err != nil {
+		return nil, err
+	}
+	conf := stbase.ConfigFromParams(paramMap)
 	algorthm := &AlgorithmImpl{
-		id:              algo.ID,
-		isActive:        abool.NewBool(true),
-		accountId:       algo.AccountId,
-		dataProc:        proc,
-		figis:           algo.Figis,
-		limits:          algo.MoneyLimits,
-		param:           paramMap,
-		algorithm:       algo,
-		buyPrice:        make(map[string]decimal.Decimal),
-		stopCh:          make(chan bool),
-		logger:          logger,
-		relDerivative:   getOrDefaultDecimal(paramMap, RelDerivative, decimal.NewFromFloat(0.01)),
-		ordExp:          time.Duration(ordExpInt) * time.Second,
-		commission:      getOrDefaultDecimal(paramMap, Commission, decimal.NewFromFloat(0.04)).Div(decimal.NewFromInt(100)),
-		stopLossRel:     stopLossC,
-		stopLossEnabled: stopLossEnabled,
-		instrAmount:     make(map[string]int64),
+		id:            algo.ID,
+		isActive:      abool.NewBool(true),
+		accountId:     algo.AccountId,
+		dataProc:      proc,
+		figis:         algo.Figis,
+		limits:        algo.MoneyLimits,
+		param:         paramMap,
+		algorithm:     algo,
+		buyPrice:      make(map[string]decimal.Decimal),
+		stopCh:        make(chan bool),
+		logger:        logger,
+		relDerivative: stbase.GetOrDefaultDecimal(paramMap, RelDerivative, decimal.NewFromFloat(0.01)),
+		conf:          conf,
+		trailing:      risk.NewTrailingStop(conf.TrailingStop),
+		gate:          gate,
+		instrAmount:   make(map[string]int64),
+		algRep:        algRep,
+		clock:         stbase.NewSimClock(simClock, logger),
 	}
 	if err := algorthm.Configure(algo.CtxParams); err != nil {
 		logger.Errorf("Failed configure algorithm %d with configuration %+v", algo.ID, algo.CtxParams)
@@ -406,41 +517,3 @@ func newAvr(algo *entity.Algorithm, logger *zap.SugaredLogger, proc DataProc) (s
 
 	return algorthm, nil
 }
-
-func getOrDefaultDecimal(paramMap map[string]string, param string, def decimal.Decimal) decimal.Decimal {
-	res, ok := paramMap[param]
-	if !ok {
-		return def
-	}
-	resDec, err := decimal.NewFromString(res)
-	if err != nil {
-		return def
-	} else {
-		return resDec
-	}
-}
-
-func getOrDefaultInt(paramMap map[string]string, param string, def int) int {
-	res, ok := paramMap[param]
-	if !ok {
-		return def
-	}
-	resDec, err := strconv.Atoi(res)
-	if err != nil {
-		return def
-	} else {
-		return resDec
-	}
-}
-
-func getDecimal(paramMap map[string]string, param string) (decimal.Decimal, bool) {
-	res, ok := paramMap[param]
-	if !ok {
-		return decimal.Zero, false
-	}
-	resDec, err := decimal.NewFromString(res)
-	if err != nil {
-		return decimal.Zero, false
-	}
-	return resDec, true
-}
@@ -0,0 +1,60 @@
+package avr
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestProcessTraderResp_partialFill(t *testing.T) {
+	alg, err := newAvr(&entity.Algorithm{Figis: []string{"figi"}}, nil, zap.NewNop().Sugar(), nil, session.StaticSchedule{}, false)
+	assert.Nil(t, err)
+	a := alg.(*AlgorithmImpl)
+	aDat := &AlgoData{statusMap: make(map[string]algoStatus), instrAmount: map[string]int64{"figi": 0}}
+
+	//Expired buy order canceled after 3 of 10 lots executed
+	buy := &entity.Action{InstrFigi: "figi", Direction: entity.Buy, Status: entity.Canceled, LotAmount: 10,
+		LotsExecuted: 3, PositionPrice: decimal.NewFromInt(100)}
+	assert.Nil(t, a.processTraderResp(aDat, &stmodel.ActionResp{Action: buy}))
+	assert.Equal(t, int64(3), aDat.instrAmount["figi"])
+	assert.True(t, a.buyPrice["figi"].Equal(decimal.NewFromInt(100)))
+
+	//Canceled sell without executed lots keeps holdings
+	sell := &entity.Action{InstrFigi: "figi", Direction: entity.Sell, Status: entity.Canceled, LotAmount: 3}
+	assert.Nil(t, a.processTraderResp(aDat, &stmodel.ActionResp{Action: sell}))
+	assert.Equal(t, int64(3), aDat.instrAmount["figi"])
+
+	//Successful sell closes position
+	sell = &entity.Action{InstrFigi: "figi", Direction: entity.Sell, Status: entity.Success, LotAmount: 3, LotsExecuted: 3}
+	assert.Nil(t, a.processTraderResp(aDat, &stmodel.ActionResp{Action: sell}))
+	assert.Equal(t, int64(0), aDat.instrAmount["figi"])
+}
+
+func TestProcessTraderResp_partialSell(t *testing.T) {
+	alg, err := newAvr(&entity.Algorithm{Figis: []string{"figi"}}, nil, zap.NewNop().Sugar(), nil, session.StaticSchedule{}, false)
+	assert.Nil(t, err)
+	a := alg.(*AlgorithmImpl)
+	aDat := &AlgoData{statusMap: make(map[string]algoStatus), instrAmount: map[string]int64{"figi": 0}}
+
+	buy := &entity.Action{InstrFigi: "figi", Direction: entity.Buy, Status: entity.Success, LotAmount: 10,
+		LotsExecuted: 10, PositionPrice: decimal.NewFromInt(100)}
+	assert.Nil(t, a.processTraderResp(aDat, &stmodel.ActionResp{Action: buy}))
+
+	//Sell canceled after 4 of 10 lots executed keeps price of remaining lots
+	sell := &entity.Action{InstrFigi: "figi", Direction: entity.Sell, Status: entity.Canceled, LotAmount: 10,
+		LotsExecuted: 4, PositionPrice: decimal.NewFromInt(90)}
+	assert.Nil(t, a.processTraderResp(aDat, &stmodel.ActionResp{Action: sell}))
+	assert.Equal(t, int64(6), aDat.instrAmount["figi"])
+	assert.True(t, a.buyPrice["figi"].Equal(decimal.NewFromInt(100)))
+
+	//Selling the rest closes position
+	sell = &entity.Action{InstrFigi: "figi", Direction: entity.Sell, Status: entity.Success, LotAmount: 6,
+		LotsExecuted: 6, PositionPrice: decimal.NewFromInt(110)}
+	assert.Nil(t, a.processTraderResp(aDat, &stmodel.ActionResp{Action: sell}))
+	_, ok := a.buyPrice["figi"]
+	assert.False(t, ok)
+}
@@ -1,8 +1,8 @@
 package avr
 
 import (
-	"encoding/json"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
@@ -19,21 +19,16 @@ func configure(confCtx map[string]string, state *algoState, logger *zap.SugaredL
 	if state.BuyPrice == nil {
 		state.BuyPrice = make(map[string]decimal.Decimal)
 	}
-	instrData, ok := confCtx[dto.InstrAmountField]
-	if ok {
-		var instrAmount dto.InstrumentsInfo
-		err := json.Unmarshal([]byte(instrData), &instrAmount)
-		if err != nil {
-			logger.Warnf("Unable unmarshal '%s' to Instruments", instrData)
-			return err
-		} else {
-			for _, instr := range instrAmount.Instruments {
-				state.InitAmount[instr.Figi] = instr.Amount
-				if !instr.BuyPosPrice.IsZero() {
-					state.BuyPrice[instr.Figi] = instr.BuyPosPrice
-				}
-			}
-		}
+	instrAmount, buyPrice, err := stbase.GetInstrumentsState(confCtx)
+	if err != nil {
+		logger.Warnf("Unable unmarshal '%s' to Instruments", confCtx[dto.InstrAmountField])
+		return err
+	}
+	for figi, amount := range instrAmount {
+		state.InitAmount[figi] = amount
+	}
+	for figi, price := range buyPrice {
+		state.BuyPrice[figi] = price
 	}
 	return nil
 }
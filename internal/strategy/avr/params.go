@@ -0,0 +1,20 @@
+package avr
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+)
+
+//Description is a human-readable description of the strategy
+const Description = "Moving average crossover: buys when short window average crosses long one upwards " +
+	"and sells when it crosses downwards not cheaper than buy price plus commissions"
+
+//ParamSpecs describes parameters accepted by the average strategy, common order and trading session parameters included
+var ParamSpecs = stbase.WithParamSpecs(
+	stmodel.ParamSpec{Name: ShortDur, Type: stmodel.IntParam, Description: "Short average window length in seconds",
+		Required: true, Min: stmodel.DecLimit(1)},
+	stmodel.ParamSpec{Name: LongDur, Type: stmodel.IntParam, Description: "Long average window length in seconds",
+		Required: true, Min: stmodel.DecLimit(1)},
+	stmodel.ParamSpec{Name: RelDerivative, Type: stmodel.DecimalParam, Description: "Relative short average derivative to buy without crossing",
+		Default: "0.01"},
+)
@@ -0,0 +1,89 @@
+package avr
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"time"
+)
+
+//figiAvr keeps average windows and values calculated on previous candle of single instrument
+type figiAvr struct {
+	sav        *collections.TList[decimal.Decimal] //Short window
+	lav        *collections.TList[decimal.Decimal] //Long window
+	prevSav    decimal.Decimal
+	prevTime   time.Time
+	prevDiff   decimal.Decimal
+	prevExists bool
+}
+
+//signalGen calculates windows averages by candles and signals by the same conditions as AlgorithmImpl.
+//Unlike AlgorithmImpl it doesn't check buy price - it's a responsibility of algorithm emitting orders
+type signalGen struct {
+	shortDur      time.Duration
+	longDur       time.Duration
+	relDerivative decimal.Decimal
+	data          map[string]*figiAvr
+	logger        *zap.SugaredLogger
+}
+
+func (s *signalGen) Process(c *candle.Candle) stbase.Signal {
+	dat, ok := s.data[c.Figi]
+	if !ok {
+		sav := collections.NewTList[decimal.Decimal](s.shortDur)
+		lav := collections.NewTList[decimal.Decimal](s.longDur)
+		dat = &figiAvr{sav: &sav, lav: &lav}
+		s.data[c.Figi] = dat
+	}
+	dat.sav.Append(c.Close, c.RetrievedAt)
+	dat.lav.Append(c.Close, c.RetrievedAt)
+	sav, err := calcAvr(dat.sav)
+	if err != nil {
+		s.logger.Errorf("Error while calculating short average: %s", err)
+		return stbase.HoldSignal()
+	}
+	lav, err := calcAvr(dat.lav)
+	if err != nil {
+		s.logger.Errorf("Error while calculating long average: %s", err)
+		return stbase.HoldSignal()
+	}
+	derivative := decimal.Zero
+	if timeDiff := c.RetrievedAt.Sub(dat.prevTime).Minutes(); dat.prevExists && timeDiff > 0 {
+		derivative = sav.Sub(dat.prevSav).Div(decimal.NewFromFloat(timeDiff))
+	}
+	currDiff := sav.Sub(lav)
+	prevDiff, prevExists := dat.prevDiff, dat.prevExists
+	dat.prevSav, dat.prevTime, dat.prevDiff, dat.prevExists = sav, c.RetrievedAt, currDiff, true
+	if !prevExists || sav.IsZero() {
+		return stbase.HoldSignal()
+	}
+	relDer := derivative.Div(sav)
+	s.logger.Debugf("Difference of %s, current: %s, prev: %s, price: %s, derivative: %s", c.Figi, currDiff, prevDiff, c.Close, derivative)
+	switch {
+	case derivative.IsPositive() && ((prevDiff.IsNegative() && currDiff.IsPositive()) ||
+		(prevDiff.IsPositive() && currDiff.IsPositive() && relDer.GreaterThan(s.relDerivative))):
+		return stbase.Signal{Type: stbase.BuySignal, Info: fmt.Sprintf("Short average %s is rising above long %s", sav, lav)}
+	case derivative.IsNegative() && currDiff.IsNegative() && !prevDiff.IsZero():
+		return stbase.Signal{Type: stbase.SellSignal, Info: fmt.Sprintf("Short average %s is falling below long %s", sav, lav)}
+	}
+	return stbase.HoldSignal()
+}
+
+//NewSignalGen creates average signal generator, allows to combine average crossing signals with signals of other strategies
+func NewSignalGen(paramMap map[string]string, logger *zap.SugaredLogger) stbase.SignalGen {
+	return &signalGen{
+		shortDur:      time.Duration(stbase.GetOrDefaultInt(paramMap, ShortDur, 60)) * time.Second,
+		longDur:       time.Duration(stbase.GetOrDefaultInt(paramMap, LongDur, 600)) * time.Second,
+		relDerivative: stbase.GetOrDefaultDecimal(paramMap, RelDerivative, decimal.NewFromFloat(0.01)),
+		data:          make(map[string]*figiAvr),
+		logger:        logger,
+	}
+}
+
+//Prefetch returns duration of history required to fill long average window immediately after start
+func Prefetch(paramMap map[string]string) time.Duration {
+	return time.Duration(stbase.GetOrDefaultInt(paramMap, LongDur, 600)) * time.Second
+}
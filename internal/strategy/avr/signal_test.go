@@ -0,0 +1,25 @@
+package avr
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestSignalGen_crossing(t *testing.T) {
+	gen := NewSignalGen(map[string]string{ShortDur: "60", LongDur: "300"}, zap.NewNop().Sugar())
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	signals := make([]stbase.SignalType, 0)
+	for i, price := range []int64{10, 10, 10, 9, 9, 12, 13, 13, 11, 9} {
+		tm := start.Add(time.Duration(i) * time.Minute)
+		signals = append(signals, gen.Process(&candle.Candle{Figi: "figi", Time: tm, RetrievedAt: tm, Close: decimal.NewFromInt(price)}).Type)
+	}
+	//Falling short average below long one, crossing upwards, rising fast enough, crossing downwards
+	expected := []stbase.SignalType{stbase.Hold, stbase.Hold, stbase.Hold, stbase.Hold, stbase.SellSignal,
+		stbase.BuySignal, stbase.BuySignal, stbase.BuySignal, stbase.Hold, stbase.SellSignal}
+	assert.Equal(t, expected, signals)
+}
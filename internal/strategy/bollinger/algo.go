@@ -0,0 +1,60 @@
+package bollinger
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"go.uber.org/zap"
+	"time"
+)
+
+const strategyName = "bollinger"
+
+//NewProd constructs new Bollinger bands algorithm using production data processor
+func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newBollinger(algo, algRep, logger, newProdDataProc(algo, infoSrv, logger), session.NewApiSchedule(infoSrv, logger), false)
+}
+
+//NewSandbox constructs new Bollinger bands algorithm using production data processor cause it the same for such algorithm
+func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newBollinger(algo, algRep, logger, newProdDataProc(algo, infoSrv, logger), session.NewApiSchedule(infoSrv, logger), false)
+}
+
+//NewHist constructs new Bollinger bands algorithm using history data processor and providing simulation clock to trader
+func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	logger := stbase.HistLogger(rootLogger)
+	return newBollinger(algo, nil, logger, candle.NewHistDataProc(algo, hRep, logger), session.StaticSchedule{}, true)
+}
+
+//NewParamSplitter creates splitter varying window, number of deviations and target band width
+func NewParamSplitter(logger *zap.SugaredLogger) stmodel.ParamSplitter {
+	return stbase.NewRangeSplitter(ParamSpecs, []string{Window, Deviations, TargetWidth}, nil, logger)
+}
+
+//newProdDataProc creates market stream processor prefetching history of the window length to calculate bands immediately
+func newProdDataProc(algo *entity.Algorithm, infoSrv service.InfoSrv, logger *zap.SugaredLogger) candle.DataProc {
+	return candle.NewProdDataProc(algo, infoSrv, Prefetch(entity.ParamsToMap(algo.Params)), logger)
+}
+
+func newBollinger(algo *entity.Algorithm, algRep repository.AlgoRepository, logger *zap.SugaredLogger,
+	proc candle.DataProc, schedule session.Schedule, simClock bool) (stmodel.Algorithm, error) {
+	gen := newSignalGen(entity.ParamsToMap(algo.Params), logger)
+	return stbase.NewSignalAlgorithm(strategyName, algo, algRep, proc, gen, schedule, simClock, logger)
+}
+
+//NewSignalGen creates Bollinger bands signal generator, allows to combine Bollinger bands signals with signals of other strategies
+func NewSignalGen(paramMap map[string]string, logger *zap.SugaredLogger) stbase.SignalGen {
+	return newSignalGen(paramMap, logger)
+}
+
+//Prefetch returns duration of history required to calculate bands immediately after start
+func Prefetch(paramMap map[string]string) time.Duration {
+	window := stbase.GetOrDefaultInt(paramMap, Window, 1200)
+	return time.Duration(window) * time.Second
+}
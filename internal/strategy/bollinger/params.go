@@ -0,0 +1,28 @@
+package bollinger
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+)
+
+//Bollinger bands parameters
+const (
+	Window      string = "window"       //Bands window length in sec
+	Deviations  string = "deviations"   //Number of standard deviations from average to bands
+	TargetWidth string = "target_width" //Band width in percents at which whole money limit is spent
+)
+
+//Description is a human-readable description of the strategy
+const Description = "Bollinger bands breakout: buys when price crosses upper band upwards spending part of money limit " +
+	"inversely proportional to band width and sells when price returns to middle band not cheaper than buy price plus commissions"
+
+//ParamSpecs describes parameters accepted by the Bollinger bands strategy
+var ParamSpecs = stbase.WithParamSpecs(
+	stmodel.ParamSpec{Name: Window, Type: stmodel.IntParam, Description: "Bands window length in seconds",
+		Default: "1200", Min: stmodel.DecLimit(60)},
+	stmodel.ParamSpec{Name: Deviations, Type: stmodel.DecimalParam, Description: "Number of standard deviations from average to upper and lower bands",
+		Default: "2", Min: stmodel.DecLimit(0)},
+	stmodel.ParamSpec{Name: TargetWidth, Type: stmodel.DecimalParam,
+		Description: "Band width in percents of average at which whole money limit is spent; wider bands reduce buy proportionally",
+		Default:     "2", Min: stmodel.DecLimit(0)},
+)
@@ -0,0 +1,87 @@
+package bollinger
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/indicator"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"time"
+)
+
+//figiData keeps window and values calculated on previous candle of single instrument
+type figiData struct {
+	prices    *collections.TList[decimal.Decimal] //Close prices in window
+	full      bool                                //Window already covers full length
+	prevClose decimal.Decimal
+	prevUpper decimal.Decimal
+	prevExist bool
+}
+
+//signalGen calculates bands by close prices in window and signals on upper band crossing and return to middle band
+type signalGen struct {
+	window      time.Duration
+	deviations  decimal.Decimal
+	targetWidth decimal.Decimal
+	data        map[string]*figiData
+	logger      *zap.SugaredLogger
+}
+
+func (s *signalGen) Process(c *candle.Candle) stbase.Signal {
+	dat, ok := s.data[c.Figi]
+	if !ok {
+		prices := collections.NewTList[decimal.Decimal](s.window)
+		dat = &figiData{prices: &prices}
+		s.data[c.Figi] = dat
+	}
+	popped := dat.prices.Append(c.Close, c.RetrievedAt)
+	dat.full = dat.full || popped
+	if !dat.full {
+		return stbase.HoldSignal()
+	}
+	bands, err := indicator.CalcBands(dat.prices, s.deviations)
+	if err != nil {
+		s.logger.Errorf("Error while calculating bands: %s", err)
+		return stbase.HoldSignal()
+	}
+	s.logger.Debugf("Bands of %s: %+v, width: %s, price: %s", c.Figi, bands, bands.Width(), c.Close)
+	crossedUpper := dat.prevExist && dat.prevClose.LessThanOrEqual(dat.prevUpper) && c.Close.GreaterThan(bands.Upper)
+	dat.prevClose = c.Close
+	dat.prevUpper = bands.Upper
+	dat.prevExist = true
+	switch {
+	case crossedUpper:
+		return stbase.Signal{
+			Type:      stbase.BuySignal,
+			LimitPart: s.limitPart(bands.Width()),
+			Info:      fmt.Sprintf("Price %s crossed upper band %s, band width %s%%", c.Close, bands.Upper, bands.Width()),
+		}
+	case c.Close.LessThanOrEqual(bands.Middle):
+		return stbase.Signal{
+			Type: stbase.SellSignal,
+			Info: fmt.Sprintf("Price %s returned to middle band %s", c.Close, bands.Middle),
+		}
+	}
+	return stbase.HoldSignal()
+}
+
+//limitPart returns part of money limit to spend - whole limit for band width not greater than target
+//and proportionally less for wider bands
+func (s *signalGen) limitPart(width decimal.Decimal) decimal.Decimal {
+	if !s.targetWidth.IsPositive() || width.LessThanOrEqual(s.targetWidth) {
+		return decimal.Zero
+	}
+	return s.targetWidth.Div(width)
+}
+
+func newSignalGen(paramMap map[string]string, logger *zap.SugaredLogger) *signalGen {
+	return &signalGen{
+		window:      time.Duration(stbase.GetOrDefaultInt(paramMap, Window, 1200)) * time.Second,
+		deviations:  stbase.GetOrDefaultDecimal(paramMap, Deviations, decimal.NewFromInt(2)),
+		targetWidth: stbase.GetOrDefaultDecimal(paramMap, TargetWidth, decimal.NewFromInt(2)),
+		data:        make(map[string]*figiData),
+		logger:      logger,
+	}
+}
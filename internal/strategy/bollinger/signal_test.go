@@ -0,0 +1,35 @@
+package bollinger
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestSignalGen_breakoutAndExit(t *testing.T) {
+	gen := newSignalGen(map[string]string{Window: "180", Deviations: "1", TargetWidth: "10"}, zap.NewExample().Sugar())
+	start := time.Now()
+	closes := []int64{100, 100, 100, 100, 100, 110, 120, 100}
+	signals := make([]stbase.Signal, 0, len(closes))
+	for i, price := range closes {
+		tm := start.Add(time.Duration(i) * time.Minute)
+		c := candle.Candle{Figi: "figi", Time: tm, RetrievedAt: tm, Close: decimal.NewFromInt(price)}
+		signals = append(signals, gen.Process(&c))
+	}
+	assert.Equal(t, stbase.Hold, signals[3].Type, "Window not full yet")
+	assert.Equal(t, stbase.SellSignal, signals[4].Type, "Price on middle band")
+	assert.Equal(t, stbase.BuySignal, signals[5].Type, "Price crossed upper band")
+	assert.True(t, signals[5].LimitPart.IsZero(), "Band is narrower than target - whole limit")
+	assert.Equal(t, stbase.Hold, signals[6].Type, "Price stays above middle band")
+	assert.Equal(t, stbase.SellSignal, signals[7].Type, "Price returned to middle band")
+}
+
+func TestSignalGen_limitPart(t *testing.T) {
+	gen := newSignalGen(map[string]string{TargetWidth: "2"}, zap.NewExample().Sugar())
+	assert.True(t, gen.limitPart(decimal.NewFromInt(1)).IsZero())
+	assert.True(t, gen.limitPart(decimal.NewFromInt(8)).Equal(decimal.NewFromFloat(0.25)))
+}
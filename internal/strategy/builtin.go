@@ -0,0 +1,80 @@
+package strategy
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/avr"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/bollinger"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/composite"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/dca"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/grid"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/macd"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/pairs"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/rsi"
+)
+
+//Registers strategies provided by the bot out of the box
+func init() {
+	mustRegister("avr", StrategyDescriptor{
+		Description: avr.Description,
+		Params:      avr.ParamSpecs,
+		NewProd:     avr.NewProd,
+		NewSandbox:  avr.NewSandbox,
+		NewHist:     avr.NewHist,
+		NewSplitter: avr.NewParamSplitter,
+	})
+	mustRegister("rsi", StrategyDescriptor{
+		Description: rsi.Description,
+		Params:      rsi.ParamSpecs,
+		NewProd:     rsi.NewProd,
+		NewSandbox:  rsi.NewSandbox,
+		NewHist:     rsi.NewHist,
+		NewSplitter: rsi.NewParamSplitter,
+	})
+	mustRegister("bollinger", StrategyDescriptor{
+		Description: bollinger.Description,
+		Params:      bollinger.ParamSpecs,
+		NewProd:     bollinger.NewProd,
+		NewSandbox:  bollinger.NewSandbox,
+		NewHist:     bollinger.NewHist,
+		NewSplitter: bollinger.NewParamSplitter,
+	})
+	mustRegister("macd", StrategyDescriptor{
+		Description: macd.Description,
+		Params:      macd.ParamSpecs,
+		NewProd:     macd.NewProd,
+		NewSandbox:  macd.NewSandbox,
+		NewHist:     macd.NewHist,
+		NewSplitter: macd.NewParamSplitter,
+	})
+	mustRegister("grid", StrategyDescriptor{
+		Description: grid.Description,
+		Params:      grid.ParamSpecs,
+		NewProd:     grid.NewProd,
+		NewSandbox:  grid.NewSandbox,
+		NewHist:     grid.NewHist,
+		NewSplitter: grid.NewParamSplitter,
+	})
+	mustRegister("dca", StrategyDescriptor{
+		Description: dca.Description,
+		Params:      dca.ParamSpecs,
+		NewProd:     dca.NewProd,
+		NewSandbox:  dca.NewSandbox,
+		NewHist:     dca.NewHist,
+		NewSplitter: dca.NewParamSplitter,
+	})
+	mustRegister("pairs", StrategyDescriptor{
+		Description: pairs.Description,
+		Params:      pairs.ParamSpecs,
+		NewProd:     pairs.NewProd,
+		NewSandbox:  pairs.NewSandbox,
+		NewHist:     pairs.NewHist,
+		NewSplitter: pairs.NewParamSplitter,
+	})
+	mustRegister("composite", StrategyDescriptor{
+		Description: composite.Description,
+		Params:      composite.ParamSpecs,
+		NewProd:     composite.NewProd,
+		NewSandbox:  composite.NewSandbox,
+		NewHist:     composite.NewHist,
+		NewSplitter: composite.NewParamSplitter,
+	})
+}
@@ -0,0 +1,58 @@
+package candle
+
+import (
+	"context"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/convert"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//Candle represents single candle update provided to algorithm.
+//In production the same candle may be sent several times while candle interval not finished
+type Candle struct {
+	Figi        string
+	Time        time.Time       //Candle interval start time - the same for all updates of one candle
+	RetrievedAt time.Time       //Time when data was retrieved - current time in production and candle time for history
+	Open        decimal.Decimal //Open price
+	High        decimal.Decimal //Highest price
+	Low         decimal.Decimal //Lowest price
+	Close       decimal.Decimal //Close price (current price while candle not finished)
+	Volume      int64           //Trading volume in lots (current volume while candle not finished)
+	Prefetched  bool            //Candle is sent from history before stream data only to warm up indicators, orders must not be requested by it
+}
+
+//DataProc provides stream of candles for the algorithm
+type DataProc interface {
+	//GetDataStream provide channel with data stream from processor
+	GetDataStream() (<-chan Candle, error)
+	//Go commands DataProc to start processing data in background
+	Go(ctx context.Context) error
+}
+
+func fromHistory(hist *entity.History) Candle {
+	return Candle{
+		Figi:        hist.Figi,
+		Time:        hist.Time,
+		RetrievedAt: hist.Time,
+		Open:        hist.Open,
+		High:        hist.High,
+		Low:         hist.Low,
+		Close:       hist.Close,
+		Volume:      hist.Volume,
+	}
+}
+
+func fromStream(c *investapi.Candle, retrievedAt time.Time) Candle {
+	return Candle{
+		Figi:        c.Figi,
+		Time:        c.Time.AsTime(),
+		RetrievedAt: retrievedAt,
+		Open:        convert.QuotationToDec(c.Open),
+		High:        convert.QuotationToDec(c.High),
+		Low:         convert.QuotationToDec(c.Low),
+		Close:       convert.QuotationToDec(c.Close),
+		Volume:      c.Volume,
+	}
+}
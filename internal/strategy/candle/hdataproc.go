@@ -0,0 +1,61 @@
+package candle
+
+import (
+	"context"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"go.uber.org/zap"
+	"time"
+)
+
+//DbDataProc sends candles from history stored in database
+type DbDataProc struct {
+	figis  []string
+	rep    repository.HistoryRepository
+	hist   []entity.History
+	dtCh   chan Candle
+	logger *zap.SugaredLogger
+	ctx    context.Context
+}
+
+func (d *DbDataProc) GetDataStream() (<-chan Candle, error) {
+	var err error
+	d.hist, err = d.rep.FindAllByFigis(d.figis)
+	if err != nil {
+		return nil, err
+	}
+	return d.dtCh, nil
+}
+
+func (d *DbDataProc) Go(ctx context.Context) error {
+	d.ctx = ctx
+	go d.procBg()
+	return nil
+}
+
+func (d *DbDataProc) procBg() {
+	d.logger.Infof("Start processing history data, full size: %d", len(d.hist))
+	defer func() {
+		close(d.dtCh)
+		d.logger.Infof("Data processor stopped...")
+	}()
+	for i := range d.hist {
+		select {
+		case <-d.ctx.Done():
+			d.logger.Info("Canceled context, stopping processor...")
+			return
+		case d.dtCh <- fromHistory(&d.hist[i]):
+			time.Sleep(1 * time.Millisecond) //To provide time for mockTrader to finish operation
+		}
+	}
+}
+
+//NewHistDataProc creates data processor sending history candles of algorithm figis
+func NewHistDataProc(req *entity.Algorithm, rep repository.HistoryRepository, logger *zap.SugaredLogger) DataProc {
+	return &DbDataProc{
+		figis:  req.Figis,
+		rep:    rep,
+		dtCh:   make(chan Candle),
+		logger: logger,
+	}
+}
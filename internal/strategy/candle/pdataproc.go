@@ -0,0 +1,214 @@
+package candle
+
+import (
+	"context"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/env"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"time"
+)
+
+//DataProcProd sends 1-minute candles from market data stream.
+//Before subscription history for prefetch duration is sent to let algorithm start working immediately
+type DataProcProd struct {
+	stream   investapi.MarketDataStreamService_MarketDataStreamClient
+	infoSrv  service.InfoSrv
+	algoId   uint                               //Algorithm id
+	figis    []string                           //List of instrument figis to send to algorithm
+	prefetch time.Duration                      //Duration of history to send before stream data
+	ctx      context.Context                    //Data processor context
+	dtCh     chan Candle                        //Channel for algorithm with candles
+	origDtCh chan *investapi.MarketDataResponse //Channel populated from market stream
+	trackId  string                             //Track id in case of incorrect response
+	retryMin int                                //Minutes before consecutive retries when data stream broken
+	retryNum int                                //Number restore retries when data stream broken
+	logger   *zap.SugaredLogger
+}
+
+func (d *DataProcProd) GetDataStream() (<-chan Candle, error) {
+	return d.dtCh, nil
+}
+
+func (d *DataProcProd) Go(ctx context.Context) error {
+	d.ctx = ctx
+	stream, err := d.infoSrv.GetDataStream(d.ctx)
+	if err != nil {
+		return err
+	}
+	d.stream = stream
+	go d.procBg()
+	return nil
+}
+
+func (d *DataProcProd) procBg() {
+	defer func() {
+		close(d.dtCh)
+		d.logger.Infof("Data processor stopped, id %d...", d.algoId)
+	}()
+	if err := d.prefetchHistory(); err != nil {
+		d.logger.Errorf("Error while prefetching history, id %d: %s", d.algoId, err)
+		return
+	}
+	if err := d.subscribe(); err != nil {
+		d.logger.Errorf("Error while subsribing to candles, id %d: %s", d.algoId, err)
+		return
+	}
+	go d.processDataInBg()
+	for {
+		select {
+		case cDat, ok := <-d.origDtCh:
+			if !ok {
+				d.logger.Info("Data channel closed, breaking data processor cycle...")
+				return
+			}
+			candle := cDat.GetCandle()
+			if candle == nil {
+				if cDat.GetPing() == nil {
+					d.logger.Infof("Received nil candle, id: %d, tracking id: %s, full response: %+v", d.algoId, d.trackId, cDat)
+				}
+				continue
+			}
+			dat := fromStream(candle, time.Now())
+			d.logger.Debugf("Sending data for alg %d: %+v", d.algoId, dat)
+			select {
+			case d.dtCh <- dat:
+			case <-d.ctx.Done():
+				d.logger.Info("Algorithm canceling context signal received...")
+				return
+			}
+		case <-d.ctx.Done():
+			d.logger.Info("Algorithm canceling context signal received...")
+			return
+		}
+	}
+}
+
+//Background task which receives data from stream and send it to channel (for simpler support of context and processor stopping)
+func (d *DataProcProd) processDataInBg() {
+	defer func() {
+		d.logger.Info("Closing tin API data channel...")
+		close(d.origDtCh)
+	}()
+	for {
+		cDat, err := d.stream.Recv()
+		if err != nil {
+			//Process end of stream and no need to restore
+			if err == io.EOF {
+				d.logger.Info("Received end of stream...")
+				return
+			}
+			//Process cancel error when context canceled and no need to restore
+			if code := status.Code(err); code == codes.Canceled {
+				d.logger.Info("Received canceled code: ", code)
+				return
+			}
+			//Trying to restore stream with set number of iterations
+			if err = d.restoreDataStreamWithRetries(); err != nil {
+				d.logger.Error(err)
+				return
+			}
+			continue
+		}
+		d.origDtCh <- cDat
+	}
+}
+
+//Retries to create and subscribe to new channel to restore data receiving
+//Uses retryNum and retryMin parameters
+func (d *DataProcProd) restoreDataStreamWithRetries() error {
+	var err error
+	d.logger.Info("Starting stream restoring for alg ", d.algoId, " with ", d.retryNum, " retries and interval (min) ", d.retryMin)
+	for retry := d.retryNum; retry > 0; retry-- {
+		time.Sleep(time.Duration(d.retryMin) * time.Minute)
+		d.logger.Infof("Retry remains %d. Trying to re-create channel after %d min", retry, d.retryMin)
+		d.stream, err = d.infoSrv.GetDataStream(d.ctx)
+		if err != nil {
+			d.logger.Error("Recreate stream failed with error: ", err)
+			continue
+		}
+		if err = d.subscribe(); err != nil {
+			d.logger.Error("Subscribe new stream failed with error: ", err)
+		} else {
+			d.logger.Info("Stream successfully restored for algorithm ", d.algoId)
+			return nil
+		}
+	}
+	return errors.NewUnexpectedError("Not possible to restore data stream, exiting...")
+}
+
+//prefetchHistory sends 1-minute candles for prefetch duration to start algorithm working effective immediate.
+//Candles are marked as prefetched - their prices are stale and must not be used for orders
+func (d *DataProcProd) prefetchHistory() error {
+	if d.prefetch <= 0 {
+		return nil
+	}
+	endTime := time.Now()
+	startTime := endTime.Add(-d.prefetch)
+	history, err := d.infoSrv.GetHistorySorted(d.figis, investapi.CandleInterval_CANDLE_INTERVAL_1_MIN, startTime, endTime, d.ctx)
+	if err != nil {
+		return err
+	}
+	for i := range history {
+		dat := fromHistory(&history[i])
+		dat.Prefetched = true
+		select {
+		case d.dtCh <- dat:
+		case <-d.ctx.Done():
+			return d.ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (d *DataProcProd) subscribe() error {
+	d.logger.Info("Subscribing to figis: ", d.figis)
+	instruments := make([]*investapi.CandleInstrument, 0, len(d.figis))
+	for _, figi := range d.figis {
+		instruments = append(instruments, &investapi.CandleInstrument{
+			Figi:     figi,
+			Interval: investapi.SubscriptionInterval_SUBSCRIPTION_INTERVAL_ONE_MINUTE,
+		})
+	}
+	req := investapi.MarketDataRequest{
+		Payload: &investapi.MarketDataRequest_SubscribeCandlesRequest{
+			SubscribeCandlesRequest: &investapi.SubscribeCandlesRequest{
+				SubscriptionAction: investapi.SubscriptionAction_SUBSCRIPTION_ACTION_SUBSCRIBE,
+				Instruments:        instruments,
+			},
+		},
+	}
+	if err := d.stream.Send(&req); err != nil {
+		d.logger.Errorf("Error while subscribing to stream: %s", err)
+		return err
+	}
+	resp, err := d.stream.Recv()
+	if err != nil {
+		d.logger.Errorf("Error while awaiting subscription response: %s", err)
+		return err
+	}
+	d.logger.Info("Subscription response received: ", resp)
+	d.trackId = resp.GetSubscribeCandlesResponse().GetTrackingId()
+	return nil
+}
+
+//NewProdDataProc creates data processor sending market stream candles of algorithm figis.
+//Prefetch defines duration of history sent before stream data
+func NewProdDataProc(req *entity.Algorithm, infoSrv service.InfoSrv, prefetch time.Duration, logger *zap.SugaredLogger) DataProc {
+	return &DataProcProd{
+		infoSrv:  infoSrv,
+		algoId:   req.ID,
+		figis:    req.Figis,
+		prefetch: prefetch,
+		dtCh:     make(chan Candle),
+		origDtCh: make(chan *investapi.MarketDataResponse),
+		logger:   logger,
+		retryMin: env.GetRetryMin(),
+		retryNum: env.GetRetryNum(),
+	}
+}
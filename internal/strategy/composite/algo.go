@@ -0,0 +1,85 @@
+package composite
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+	"time"
+)
+
+const strategyName = "composite"
+
+//NewProd constructs new composite algorithm using production data processor
+func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newComposite(algo, algRep, logger, func(prefetch time.Duration) candle.DataProc {
+		return candle.NewProdDataProc(algo, infoSrv, prefetch, logger)
+	}, session.NewApiSchedule(infoSrv, logger), false)
+}
+
+//NewSandbox constructs new composite algorithm using production data processor cause it the same for such algorithm
+func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return NewProd(algo, infoSrv, algRep, logger)
+}
+
+//NewHist constructs new composite algorithm using history data processor and providing simulation clock to trader
+func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	logger := stbase.HistLogger(rootLogger)
+	return newComposite(algo, nil, logger, func(time.Duration) candle.DataProc {
+		return candle.NewHistDataProc(algo, hRep, logger)
+	}, session.StaticSchedule{}, true)
+}
+
+//ParamSplitter splits ranges of any composite or child parameter, e.g. 'rsi.window': '10:2:20'
+type ParamSplitter struct {
+	logger *zap.SugaredLogger
+}
+
+func (p *ParamSplitter) ParseAndSplit(param map[string]string) ([]map[string]string, error) {
+	ranged := make([]string, 0)
+	for key, value := range param {
+		if strings.Contains(value, stbase.RangeSeparator) {
+			ranged = append(ranged, key)
+		}
+	}
+	sort.Strings(ranged)
+	return stbase.NewRangeSplitter(ParamSpecs, ranged, nil, p.logger).ParseAndSplit(param)
+}
+
+//NewParamSplitter creates splitter varying parameters set as range
+func NewParamSplitter(logger *zap.SugaredLogger) stmodel.ParamSplitter {
+	return &ParamSplitter{logger: logger}
+}
+
+func newComposite(algo *entity.Algorithm, algRep repository.AlgoRepository, logger *zap.SugaredLogger,
+	newProc func(prefetch time.Duration) candle.DataProc, schedule session.Schedule, simClock bool) (stmodel.Algorithm, error) {
+	paramMap := entity.ParamsToMap(algo.Params)
+	children, err := parseChildren(paramMap)
+	if err != nil {
+		return nil, err
+	}
+	quorum := stbase.GetOrDefaultInt(paramMap, Quorum, len(children))
+	if quorum < 1 || quorum > len(children) {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be between 1 and number of children %d", Quorum, len(children)))
+	}
+	gen := signalGen{quorum: quorum, logger: logger}
+	var prefetch time.Duration
+	for _, ch := range children {
+		gen.gens = append(gen.gens, namedGen{name: ch.name, gen: ch.tp.newGen(ch.param, logger)})
+		if childPrefetch := ch.tp.prefetch(ch.param); childPrefetch > prefetch {
+			prefetch = childPrefetch
+		}
+	}
+	logger.Infof("Composite algorithm %d children: %s, quorum: %d", algo.ID, paramMap[Children], quorum)
+	return stbase.NewSignalAlgorithm(strategyName, algo, algRep, newProc(prefetch), &gen, schedule, simClock, logger)
+}
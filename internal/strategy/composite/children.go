@@ -0,0 +1,68 @@
+package composite
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/avr"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/bollinger"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/macd"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/rsi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+//childType describes strategy which may be used as a child of composite strategy
+type childType struct {
+	specs    []stmodel.ParamSpec                                                          //Child parameter specs
+	newGen   func(paramMap map[string]string, logger *zap.SugaredLogger) stbase.SignalGen //Signal generator constructor
+	prefetch func(paramMap map[string]string) time.Duration                               //Duration of history required by child
+}
+
+var childTypes = map[string]childType{
+	"avr":       {specs: avr.ParamSpecs, newGen: avr.NewSignalGen, prefetch: avr.Prefetch},
+	"rsi":       {specs: rsi.ParamSpecs, newGen: rsi.NewSignalGen, prefetch: rsi.Prefetch},
+	"bollinger": {specs: bollinger.ParamSpecs, newGen: bollinger.NewSignalGen, prefetch: bollinger.Prefetch},
+	"macd":      {specs: macd.ParamSpecs, newGen: macd.NewSignalGen, prefetch: macd.Prefetch},
+	"volume":    {specs: volumeParamSpecs, newGen: newVolumeGen, prefetch: volumePrefetch},
+}
+
+//child is configured child strategy of composite algorithm
+type child struct {
+	name  string
+	tp    childType
+	param map[string]string //Child parameters without child name prefix
+}
+
+//parseChildren extracts child strategies and their parameters from composite parameters.
+//Child parameters are validated and populated with defaults by child parameter specs
+func parseChildren(paramMap map[string]string) ([]child, error) {
+	names := strings.Split(paramMap[Children], ",")
+	res := make([]child, 0, len(names))
+	used := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		tp, ok := childTypes[name]
+		if !ok {
+			return nil, errors.NewValidationErr(fmt.Sprintf("Unknown child strategy '%s'", name))
+		}
+		if used[name] {
+			return nil, errors.NewValidationErr(fmt.Sprintf("Child strategy '%s' used twice", name))
+		}
+		used[name] = true
+		param := make(map[string]string)
+		prefix := name + ChildSeparator
+		for key, value := range paramMap {
+			if strings.HasPrefix(key, prefix) {
+				param[strings.TrimPrefix(key, prefix)] = value
+			}
+		}
+		if err := stmodel.ApplyParamSpecs(tp.specs, param); err != nil {
+			return nil, errors.NewValidationErr(fmt.Sprintf("Child strategy '%s': %s", name, err))
+		}
+		res = append(res, child{name: name, tp: tp, param: param})
+	}
+	return res, nil
+}
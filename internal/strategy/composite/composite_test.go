@@ -0,0 +1,97 @@
+package composite
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+//stubGen returns predefined signal and counts processed candles
+type stubGen struct {
+	signal    stbase.Signal
+	processed int
+}
+
+func (s *stubGen) Process(*candle.Candle) stbase.Signal {
+	s.processed++
+	return s.signal
+}
+
+func TestSignalGen_quorum(t *testing.T) {
+	buy := &stubGen{signal: stbase.Signal{Type: stbase.BuySignal, LimitPart: decimal.NewFromFloat(0.5), Info: "buy"}}
+	buyAll := &stubGen{signal: stbase.Signal{Type: stbase.BuySignal, Info: "buy all"}}
+	sell := &stubGen{signal: stbase.Signal{Type: stbase.SellSignal, Info: "sell"}}
+	hold := &stubGen{signal: stbase.HoldSignal()}
+	logger := zap.NewNop().Sugar()
+	c := &candle.Candle{Figi: "figi"}
+
+	gen := signalGen{gens: []namedGen{{"a", buy}, {"b", buyAll}, {"c", sell}, {"d", hold}}, quorum: 2, logger: logger}
+	sig := gen.Process(c)
+	assert.Equal(t, stbase.BuySignal, sig.Type)
+	assert.True(t, sig.LimitPart.Equal(decimal.NewFromFloat(0.5)), "The most cautious limit part expected")
+	assert.Equal(t, 1, hold.processed, "Every child must process candle")
+
+	gen.quorum = 3
+	assert.Equal(t, stbase.Hold, gen.Process(c).Type)
+
+	gen = signalGen{gens: []namedGen{{"a", buy}, {"c", sell}}, quorum: 1, logger: logger}
+	assert.Equal(t, stbase.Hold, gen.Process(c).Type, "Equal votes for opposite actions must not trade")
+}
+
+func TestParseChildren(t *testing.T) {
+	children, err := parseChildren(map[string]string{Children: "rsi, volume", "rsi.window": "10", "volume.factor": "3", "macd.fast_period": "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(children))
+	assert.Equal(t, "10", children[0].param["window"])
+	assert.Equal(t, "70", children[0].param["overbought"], "Child defaults must be applied")
+	assert.Equal(t, "3", children[1].param[VolumeFactor])
+
+	_, err = parseChildren(map[string]string{Children: "rsi,unknown"})
+	assert.NotNil(t, err)
+	_, err = parseChildren(map[string]string{Children: "rsi,rsi"})
+	assert.NotNil(t, err)
+	_, err = parseChildren(map[string]string{Children: "rsi", "rsi.window": "1"})
+	assert.NotNil(t, err, "Child parameters must be validated")
+}
+
+func TestVolumeGen(t *testing.T) {
+	gen := newVolumeGen(map[string]string{VolumeWindow: "2", VolumeFactor: "2"}, zap.NewNop().Sugar())
+	start := time.Now()
+	process := func(i int, volume int64, open int64, cl int64) stbase.SignalType {
+		return gen.Process(&candle.Candle{Figi: "figi", Time: start.Add(time.Duration(i) * time.Minute), Volume: volume,
+			Open: decimal.NewFromInt(open), Close: decimal.NewFromInt(cl)}).Type
+	}
+	assert.Equal(t, stbase.Hold, process(0, 10, 1, 2))
+	assert.Equal(t, stbase.Hold, process(1, 10, 1, 2))
+	assert.Equal(t, stbase.Hold, process(2, 20, 1, 2), "Volume equal to average multiplied by factor is not a spike")
+	assert.Equal(t, stbase.BuySignal, process(3, 40, 1, 2))
+	assert.Equal(t, stbase.SellSignal, process(4, 100, 2, 1))
+}
+
+func TestParamSplitter_childRanges(t *testing.T) {
+	split, err := NewParamSplitter(zap.NewNop().Sugar()).ParseAndSplit(map[string]string{
+		Children: "rsi,macd", "rsi.window": "10:2:14", Quorum: "1:2", "macd.fast_period": "5"})
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(split))
+	for _, param := range split {
+		assert.Equal(t, "5", param["macd.fast_period"])
+		assert.Equal(t, "rsi,macd", param[Children])
+	}
+}
+
+func TestNewHist_quorum(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	params := []*entity.Param{{Key: Children, Value: "rsi,macd"}}
+	alg, err := NewHist(&entity.Algorithm{Figis: []string{"figi"}, Params: params}, nil, logger)
+	assert.Nil(t, err)
+	assert.NotNil(t, alg)
+
+	params = append(params, &entity.Param{Key: Quorum, Value: "3"})
+	_, err = NewHist(&entity.Algorithm{Figis: []string{"figi"}, Params: params}, nil, logger)
+	assert.NotNil(t, err)
+}
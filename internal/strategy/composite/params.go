@@ -0,0 +1,27 @@
+package composite
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+)
+
+//Composite parameters
+const (
+	Children string = "children" //Comma separated names of child strategies
+	Quorum   string = "quorum"   //Number of children voting for the same action to emit order
+)
+
+//ChildSeparator separates child strategy name and its parameter name - 'rsi.window'
+const ChildSeparator string = "."
+
+//Description is a human-readable description of the strategy
+const Description = "Composite strategy: feeds the same candles to several child strategies and trades only when " +
+	"configured quorum of them votes for the same action; child parameters are set with child name prefix, e.g. 'rsi.window'"
+
+//ParamSpecs describes own parameters of the composite strategy, child parameters are validated by child specs
+var ParamSpecs = stbase.WithParamSpecs(
+	stmodel.ParamSpec{Name: Children, Type: stmodel.StringParam,
+		Description: "Comma separated child strategies, available: avr, rsi, bollinger, macd, volume", Required: true},
+	stmodel.ParamSpec{Name: Quorum, Type: stmodel.IntParam,
+		Description: "Number of children voting for the same action to emit order; all children by default", Min: stmodel.DecLimit(1)},
+)
@@ -0,0 +1,56 @@
+package composite
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"strings"
+)
+
+//namedGen is signal generator of child strategy
+type namedGen struct {
+	name string
+	gen  stbase.SignalGen
+}
+
+//signalGen feeds every candle to all children and turns their votes to a single signal.
+//Signal is emitted when at least quorum children vote for it and the opposite action has fewer votes
+type signalGen struct {
+	gens   []namedGen
+	quorum int
+	logger *zap.SugaredLogger
+}
+
+func (s *signalGen) Process(c *candle.Candle) stbase.Signal {
+	votes := make(map[stbase.SignalType][]string)
+	limitPart := decimal.Zero
+	for _, ng := range s.gens {
+		//Every child must process the candle to keep its state actual
+		sig := ng.gen.Process(c)
+		if sig.Type == stbase.Hold {
+			continue
+		}
+		votes[sig.Type] = append(votes[sig.Type], fmt.Sprintf("%s: %s", ng.name, sig.Info))
+		//The most cautious child defines part of limit to spend
+		if sig.Type == stbase.BuySignal && sig.LimitPart.IsPositive() && (limitPart.IsZero() || sig.LimitPart.LessThan(limitPart)) {
+			limitPart = sig.LimitPart
+		}
+	}
+	buys, sells := len(votes[stbase.BuySignal]), len(votes[stbase.SellSignal])
+	switch {
+	case buys >= s.quorum && buys > sells:
+		return stbase.Signal{Type: stbase.BuySignal, LimitPart: limitPart, Info: s.info(buys, votes[stbase.BuySignal])}
+	case sells >= s.quorum && sells > buys:
+		return stbase.Signal{Type: stbase.SellSignal, Info: s.info(sells, votes[stbase.SellSignal])}
+	}
+	if buys > 0 || sells > 0 {
+		s.logger.Debugf("No quorum %d for %s; buy votes: %v, sell votes: %v", s.quorum, c.Figi, votes[stbase.BuySignal], votes[stbase.SellSignal])
+	}
+	return stbase.HoldSignal()
+}
+
+func (s *signalGen) info(num int, votes []string) string {
+	return fmt.Sprintf("%d of %d children agreed (%s)", num, len(s.gens), strings.Join(votes, "; "))
+}
@@ -0,0 +1,77 @@
+package composite
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/indicator"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"time"
+)
+
+//Volume filter parameters
+const (
+	VolumeWindow string = "window" //Number of previous 1-minute candles to average volume
+	VolumeFactor string = "factor" //Volume spike - candle volume greater than average volume multiplied by factor
+)
+
+var volumeParamSpecs = []stmodel.ParamSpec{
+	{Name: VolumeWindow, Type: stmodel.IntParam, Description: "Number of previous 1-minute candles to average volume",
+		Default: "20", Min: stmodel.DecLimit(1)},
+	{Name: VolumeFactor, Type: stmodel.DecimalParam, Description: "Candle volume must exceed average volume multiplied by factor",
+		Default: "2", Min: stmodel.DecLimit(0)},
+}
+
+//volumeGen confirms price movement by volume spike: votes for buy on rising candle and for sell on falling one
+//when candle volume exceeds average volume of previous candles
+type volumeGen struct {
+	window  int
+	factor  decimal.Decimal
+	volumes map[string]*indicator.Series //Volumes of previous candles and the current one by figi
+	logger  *zap.SugaredLogger
+}
+
+func (v *volumeGen) Process(c *candle.Candle) stbase.Signal {
+	volumes, ok := v.volumes[c.Figi]
+	if !ok {
+		volumes = indicator.NewSeries(v.window + 1)
+		v.volumes[c.Figi] = volumes
+	}
+	volumes.Add(c.Time, decimal.NewFromInt(c.Volume))
+	if !volumes.IsFull() {
+		return stbase.HoldSignal()
+	}
+	prev := volumes.Values()[:v.window]
+	sum := decimal.Zero
+	for _, vol := range prev {
+		sum = sum.Add(vol)
+	}
+	avr := sum.Div(decimal.NewFromInt(int64(v.window)))
+	//History without volumes has zero average, spike is not defined
+	if !avr.IsPositive() || !volumes.Last().GreaterThan(avr.Mul(v.factor)) {
+		return stbase.HoldSignal()
+	}
+	info := fmt.Sprintf("Volume %s exceeds average %s", volumes.Last(), avr)
+	switch {
+	case c.Close.GreaterThan(c.Open):
+		return stbase.Signal{Type: stbase.BuySignal, Info: info + " on rising candle"}
+	case c.Close.LessThan(c.Open):
+		return stbase.Signal{Type: stbase.SellSignal, Info: info + " on falling candle"}
+	}
+	return stbase.HoldSignal()
+}
+
+func newVolumeGen(paramMap map[string]string, logger *zap.SugaredLogger) stbase.SignalGen {
+	return &volumeGen{
+		window:  stbase.GetOrDefaultInt(paramMap, VolumeWindow, 20),
+		factor:  stbase.GetOrDefaultDecimal(paramMap, VolumeFactor, decimal.NewFromInt(2)),
+		volumes: make(map[string]*indicator.Series),
+		logger:  logger,
+	}
+}
+
+func volumePrefetch(paramMap map[string]string) time.Duration {
+	return time.Duration(stbase.GetOrDefaultInt(paramMap, VolumeWindow, 20)+1) * time.Minute
+}
@@ -0,0 +1,382 @@
+package dca
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/indicator"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"github.com/tevino/abool/v2"
+	"go.uber.org/zap"
+	"time"
+	_ "time/tzdata" //Schedule time zone must be available regardless of system zoneinfo
+)
+
+//SpentField is a context parameter key with money spent by algorithm in each currency
+const SpentField string = "dcaSpent"
+
+//figiState keeps schedule and order state of single instrument
+type figiState struct {
+	nextBuy time.Time         //Next scheduled buy time, zero until first candle received
+	prices  *indicator.Series //Close prices for moving average, nil when check disabled
+	action  *entity.Action    //Buy order in progress, nil when no order posted
+	amount  int64             //Amount of bought lots
+}
+
+//AlgorithmImpl is dollar-cost averaging algorithm.
+//At each scheduled time algorithm buys fixed money amount of every instrument by limited order with the current price.
+//Money limits are used as a total budget: amount of each order is bounded by limit minus money already spent
+type AlgorithmImpl struct {
+	id        uint                       //Algorithm id extracted for more convenience
+	isActive  *abool.AtomicBool          //Atomic bool indicating is algorithm active
+	dataProc  candle.DataProc            //Data processor - provides candles as the channel for algorithm
+	accountId string                     //Account id extracted for more convenience
+	limits    []*entity.MoneyLimit       //Total budget of algorithm
+	algorithm *entity.Algorithm          //Link to original object which algorithm based
+	param     map[string]string          //Map of algorithm configuration parameters
+	schedule  *Schedule                  //Schedule of buys
+	amount    decimal.Decimal            //Money amount to spend on each instrument at scheduled time
+	ordExp    time.Duration              //Expiration duration of posted orders
+	figis     map[string]*figiState      //State of each instrument
+	spent     map[string]decimal.Decimal //Money spent in each currency
+	aChan     chan *stmodel.ActionReq    //Channel to send order requests to trader
+	arChan    chan *stmodel.ActionResp   //Channel to receive responses from trader about action result
+	algRep    repository.AlgoRepository
+	clock     *stbase.SimClock //Simulation clock of history trader, disabled in production
+	ctx       context.Context
+	cancelF   context.CancelFunc
+
+	logger *zap.SugaredLogger
+}
+
+func (a *AlgorithmImpl) Subscribe() (*stmodel.Subscription, error) {
+	if a.aChan != nil || a.arChan != nil {
+		return nil, errors.NewDoubleSubErr("DCA algorithm multiple subscription not implemented")
+	}
+	//Each instrument may have order in progress, buffer must fit all of them to not block algorithm
+	a.aChan = make(chan *stmodel.ActionReq, len(a.figis))
+	a.arChan = make(chan *stmodel.ActionResp, 1) //must not block trader, so size = 1
+	sub := &stmodel.Subscription{AlgoID: a.id, AChan: a.aChan, RChan: a.arChan}
+	a.clock.Subscribe(sub)
+	return sub, nil
+}
+
+func (a *AlgorithmImpl) IsActive() bool {
+	return a.isActive.IsSet()
+}
+
+func (a *AlgorithmImpl) Go(parCtx context.Context) error {
+	a.ctx, a.cancelF = context.WithCancel(parCtx)
+	ch, err := a.dataProc.GetDataStream()
+	if err != nil {
+		return err
+	}
+	go a.procBg(ch)
+	err = a.dataProc.Go(a.ctx)
+	if err != nil {
+		a.logger.Error("Error while starting data processor: ", err)
+		a.stopInternal()
+		return errors.NewUnexpectedError("Error while starting data processor " + err.Error())
+	}
+	a.isActive.Set()
+	return nil
+}
+
+func (a *AlgorithmImpl) procBg(datCh <-chan candle.Candle) {
+	defer func() {
+		a.isActive.UnSet()
+		close(a.aChan)
+		a.clock.Close(a.arChan)
+		a.logger.Infof("Stopping algorithm background; ID: %d", a.id)
+	}()
+	a.logger.Infof("Starting background algorithm processing; id: %d , strategy: dca , budget: %+v", a.id, a.limits)
+	for {
+		select {
+		case resp, ok := <-a.arChan:
+			if !ok {
+				a.logger.Warn("Trader closed response channel, stopping algorithm...")
+				return
+			}
+			a.processTraderResp(resp)
+		case cDat, ok := <-datCh:
+			if !ok {
+				a.logger.Infof("Closed data processor stream, stopping algorithm...")
+				return
+			}
+			a.processCandle(&cDat)
+			if !a.clock.Send(a.ctx, cDat.RetrievedAt, a.arChan, a.processTraderResp) {
+				return
+			}
+		case <-a.ctx.Done():
+			a.logger.Info("Context canceled, stopping...")
+			return
+		}
+	}
+}
+
+//processCandle buys instrument when scheduled time reached
+func (a *AlgorithmImpl) processCandle(cDat *candle.Candle) {
+	st, ok := a.figis[cDat.Figi]
+	if !ok {
+		a.logger.Warnf("Received candle of unexpected figi %s", cDat.Figi)
+		return
+	}
+	if st.prices != nil {
+		st.prices.Add(cDat.Time, cDat.Close)
+	}
+	if cDat.Prefetched {
+		return
+	}
+	if st.nextBuy.IsZero() {
+		a.scheduleNext(st, cDat.Figi, cDat.RetrievedAt)
+		return
+	}
+	if cDat.RetrievedAt.Before(st.nextBuy) {
+		return
+	}
+	a.scheduleNext(st, cDat.Figi, cDat.RetrievedAt)
+	if st.action != nil {
+		a.logger.Infof("Previous buy of %s not finished, skipping scheduled buy", cDat.Figi)
+		return
+	}
+	if st.prices != nil && cDat.Close.GreaterThan(st.prices.Mean()) {
+		a.logger.Infof("Price %s of %s is above moving average %s, skipping scheduled buy", cDat.Close, cDat.Figi, st.prices.Mean())
+		return
+	}
+	limits := a.remainingLimits()
+	if len(limits) == 0 {
+		a.logger.Infof("Budget exhausted, skipping scheduled buy of %s; spent: %v", cDat.Figi, a.spent)
+		return
+	}
+	action := entity.Action{
+		AlgorithmID:    a.id,
+		Direction:      entity.Buy,
+		InstrFigi:      cDat.Figi,
+		ReqPrice:       cDat.Close,
+		ExpirationTime: cDat.RetrievedAt.Add(a.ordExp),
+		Status:         entity.Created,
+		OrderType:      entity.Limited,
+		RetrievedAt:    cDat.RetrievedAt,
+		AccountID:      a.accountId,
+	}
+	st.action = &action
+	a.logger.Infof("Scheduled buy, requesting action: %+v", action)
+	a.aChan <- &stmodel.ActionReq{Action: &action, Limits: limits}
+}
+
+//scheduleNext sets next buy time of instrument after the time, instrument is never bought if schedule never matches
+func (a *AlgorithmImpl) scheduleNext(st *figiState, figi string, after time.Time) {
+	next, ok := a.schedule.Next(after)
+	if !ok {
+		a.logger.Warnf("Schedule of algorithm %d has no time in a year, %s will not be bought", a.id, figi)
+		next = after.Add(maxSearch)
+	}
+	st.nextBuy = next
+	a.logger.Infof("Next buy of %s scheduled at %s", figi, next)
+}
+
+//remainingLimits returns limits of single order - buy amount bounded by not spent budget of each currency
+func (a *AlgorithmImpl) remainingLimits() []*entity.MoneyLimit {
+	res := make([]*entity.MoneyLimit, 0, len(a.limits))
+	for _, limit := range a.limits {
+		remaining := limit.Amount.Sub(a.spent[limit.Currency])
+		if !remaining.IsPositive() {
+			continue
+		}
+		res = append(res, &entity.MoneyLimit{
+			AlgorithmID: limit.AlgorithmID,
+			Currency:    limit.Currency,
+			Amount:      decimal.Min(a.amount, remaining),
+		})
+	}
+	return res
+}
+
+//process response from trade.Trader after requested passed trading stages
+func (a *AlgorithmImpl) processTraderResp(resp *stmodel.ActionResp) {
+	action := resp.Action
+	a.logger.Debug("Processing trader response: ", *action)
+	st, ok := a.figis[action.InstrFigi]
+	if !ok {
+		a.logger.Warnf("Received response of unexpected figi %s", action.InstrFigi)
+		return
+	}
+	st.action = nil
+	//Canceled order may be partially filled - spent money and amount are updated by really executed lots
+	executed := action.ExecutedAmount()
+	if executed == 0 {
+		a.logger.Infof("Operation failed %+v", resp)
+		return
+	}
+	if action.Status != entity.Success {
+		a.logger.Infof("Order partially executed: %s", action.Info)
+	}
+	st.amount += executed
+	a.spent[action.Currency] = a.spent[action.Currency].Add(action.TotalPrice)
+	a.logger.Infof("Bought %d lots of %s for %s %s, spent: %v", executed, action.InstrFigi,
+		action.TotalPrice, action.Currency, a.spent)
+	a.updateState()
+	stbase.PersistState(a.algRep, a.algorithm, action, a.logger)
+}
+
+//updateState updates algorithm context parameters - spent money and amount of instruments
+func (a *AlgorithmImpl) updateState() {
+	res, err := json.Marshal(a.spent)
+	if err != nil {
+		a.logger.Errorf("Error while marshalling state of algorithm %d: %s", a.id, err)
+		return
+	}
+	a.algorithm.SetCtxParam(SpentField, string(res))
+	instrAmount := make(map[string]int64, len(a.figis))
+	for figi, st := range a.figis {
+		instrAmount[figi] = st.amount
+	}
+	if err = stbase.SetInstrumentsState(a.algorithm, instrAmount, nil); err != nil {
+		a.logger.Errorf("Error while marshalling state of algorithm %d: %s", a.id, err)
+	}
+}
+
+func (a *AlgorithmImpl) Stop() error {
+	if a.isActive.IsNotSet() {
+		a.logger.Info("Algorithm already stopped, do nothing...")
+		return nil
+	}
+	a.stopInternal()
+	return nil
+}
+
+func (a *AlgorithmImpl) stopInternal() {
+	a.cancelF()
+	a.logger.Infof("Algorithm %d successfully stopped", a.id)
+}
+
+//Configure restores spent money, amounts of bought instruments and posted orders
+func (a *AlgorithmImpl) Configure(ctx []*entity.CtxParam) error {
+	confCtx := entity.ContextToMap(ctx)
+	if spentStr, ok := confCtx[SpentField]; ok {
+		if err := json.Unmarshal([]byte(spentStr), &a.spent); err != nil {
+			a.logger.Warnf("Unable unmarshal '%s' to spent money", spentStr)
+			return err
+		}
+	}
+	instrAmount, _, err := stbase.GetInstrumentsState(confCtx)
+	if err != nil {
+		a.logger.Warnf("Unable restore instruments state of algorithm %d", a.id)
+		return err
+	}
+	for figi, amount := range instrAmount {
+		if st, ok := a.figis[figi]; ok {
+			st.amount = amount
+		}
+	}
+	for _, action := range a.algorithm.Actions {
+		if st, ok := a.figis[action.InstrFigi]; ok && action.Status == entity.Posted {
+			st.action = action
+		}
+	}
+	return nil
+}
+
+func (a *AlgorithmImpl) GetParam() map[string]string {
+	return a.param
+}
+
+func (a *AlgorithmImpl) GetAlgorithm() *entity.Algorithm {
+	return a.algorithm
+}
+
+//NewProd constructs new DCA algorithm using production data processor
+func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newDca(algo, algRep, logger, false, func(maPeriod int) candle.DataProc {
+		return candle.NewProdDataProc(algo, infoSrv, time.Duration(maPeriod)*time.Minute, logger)
+	})
+}
+
+//NewSandbox constructs new DCA algorithm using production data processor cause it the same for such algorithm
+func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return NewProd(algo, infoSrv, algRep, logger)
+}
+
+//NewHist constructs new DCA algorithm using history data processor and providing simulation clock to trader
+func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	logger := stbase.HistLogger(rootLogger)
+	return newDca(algo, nil, logger, true, func(int) candle.DataProc {
+		return candle.NewHistDataProc(algo, hRep, logger)
+	})
+}
+
+//NewParamSplitter creates splitter varying buy amount and moving average period
+func NewParamSplitter(logger *zap.SugaredLogger) stmodel.ParamSplitter {
+	return stbase.NewRangeSplitter(ParamSpecs, []string{Amount, MaPeriod}, nil, logger)
+}
+
+func newDca(algo *entity.Algorithm, algRep repository.AlgoRepository, logger *zap.SugaredLogger, simClock bool,
+	newProc func(maPeriod int) candle.DataProc) (stmodel.Algorithm, error) {
+	paramMap := entity.ParamsToMap(algo.Params)
+	zone := getOrDefault(paramMap, TimeZone, "Europe/Moscow")
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Unknown time zone '%s'", zone))
+	}
+	schedule, err := ParseSchedule(getOrDefault(paramMap, ScheduleParam, "15 10 * * 1"), loc)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := schedule.Next(time.Now()); !ok {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Schedule '%s' never matches", paramMap[ScheduleParam]))
+	}
+	amount, ok := stbase.GetDecimal(paramMap, Amount)
+	if !ok || !amount.IsPositive() {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be positive", Amount))
+	}
+	if len(algo.Figis) == 0 {
+		return nil, errors.NewValidationErr("DCA requires at least 1 instrument")
+	}
+	maPeriod := stbase.GetOrDefaultInt(paramMap, MaPeriod, 0)
+	figis := make(map[string]*figiState, len(algo.Figis))
+	for _, figi := range algo.Figis {
+		st := figiState{}
+		if maPeriod > 0 {
+			st.prices = indicator.NewSeries(maPeriod)
+		}
+		figis[figi] = &st
+	}
+	algorithm := &AlgorithmImpl{
+		id:        algo.ID,
+		isActive:  abool.NewBool(true),
+		dataProc:  newProc(maPeriod),
+		accountId: algo.AccountId,
+		limits:    algo.MoneyLimits,
+		algorithm: algo,
+		param:     paramMap,
+		schedule:  schedule,
+		amount:    amount,
+		ordExp:    time.Duration(stbase.GetOrDefaultInt(paramMap, stbase.OrderExpiration, 300)) * time.Second,
+		figis:     figis,
+		spent:     make(map[string]decimal.Decimal),
+		algRep:    algRep,
+		clock:     stbase.NewSimClock(simClock, logger),
+		logger:    logger,
+	}
+	if err = algorithm.Configure(algo.CtxParams); err != nil {
+		logger.Errorf("Failed configure algorithm %d with configuration %+v", algo.ID, algo.CtxParams)
+		return nil, err
+	}
+	return algorithm, nil
+}
+
+func getOrDefault(paramMap map[string]string, param string, def string) string {
+	if res, ok := paramMap[param]; ok && res != "" {
+		return res
+	}
+	return def
+}
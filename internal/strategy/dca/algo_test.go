@@ -0,0 +1,89 @@
+package dca
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	mock_repository "github.com/ldmi3i/tinkoff-invest-bot/internal/mocks/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestDca_history(t *testing.T) {
+	start := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+	hist := make([]entity.History, 0)
+	for i, price := range []int64{100, 100, 50, 125, 100} {
+		hist = append(hist, entity.History{Figi: "figi", Close: decimal.NewFromInt(price), Time: start.Add(time.Duration(i) * 24 * time.Hour)})
+	}
+	tests := []struct {
+		name     string
+		maPeriod string
+		buyNum   uint
+		balance  int64
+	}{
+		//Buys 2 lots by 100, 5 lots by 50 and 1 lot by 125 with rest of budget, last buy fails - budget spent
+		{name: "without average", maPeriod: "0", buyNum: 3, balance: 8*100 - 575},
+		//Buy by 125 skipped as above average 87.5, last buy of 1 lot by 100 uses rest of budget
+		{name: "with average", maPeriod: "2", buyNum: 3, balance: 8*100 - 550},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			hRep := mock_repository.NewMockHistoryRepository(ctrl)
+			hRep.EXPECT().FindAllByFigis(gomock.Any()).Return(hist, nil).AnyTimes()
+			logger := zap.NewNop().Sugar()
+			algo := &entity.Algorithm{
+				Figis:       []string{"figi"},
+				MoneyLimits: []*entity.MoneyLimit{{Currency: "rub", Amount: decimal.NewFromInt(600)}},
+				Params: []*entity.Param{
+					{Key: ScheduleParam, Value: "0 10 * * *"},
+					{Key: TimeZone, Value: "UTC"},
+					{Key: Amount, Value: "250"},
+					{Key: MaPeriod, Value: tt.maPeriod},
+				},
+			}
+			alg, err := NewHist(algo, hRep, logger)
+			assert.Nil(t, err)
+			sub, err := alg.Subscribe()
+			assert.Nil(t, err)
+			trader := trade.NewMockTrader(hRep, map[string]int64{"figi": 1}, map[string]string{"figi": "rub"}, nil, logger)
+			assert.Nil(t, trader.AddSubscription(sub))
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			trader.Go(ctx)
+			assert.Nil(t, alg.Go(ctx))
+			stat := <-trader.GetStatCh()
+
+			assert.Equal(t, tt.buyNum, stat.BuyOpNum)
+			assert.True(t, stat.CurBalance["rub"].Equal(decimal.NewFromInt(tt.balance)), "expected %d, got %s", tt.balance, stat.CurBalance["rub"])
+		})
+	}
+}
+
+func TestProcessTraderResp_partialFill(t *testing.T) {
+	algo := &entity.Algorithm{
+		Figis:  []string{"figi"},
+		Params: []*entity.Param{{Key: Amount, Value: "250"}},
+	}
+	alg, err := NewHist(algo, nil, zap.NewNop().Sugar())
+	assert.Nil(t, err)
+	a := alg.(*AlgorithmImpl)
+
+	//Expired buy canceled after 2 of 5 lots executed - only executed lots and their price are counted
+	buy := &entity.Action{InstrFigi: "figi", Direction: entity.Buy, Status: entity.Canceled, LotAmount: 5, LotsExecuted: 2,
+		TotalPrice: decimal.NewFromInt(200), Currency: "rub"}
+	a.processTraderResp(&stmodel.ActionResp{Action: buy})
+	assert.Equal(t, int64(2), a.figis["figi"].amount)
+	assert.True(t, a.spent["rub"].Equal(decimal.NewFromInt(200)), "got %s", a.spent["rub"])
+
+	//Canceled buy without executed lots changes nothing
+	buy = &entity.Action{InstrFigi: "figi", Direction: entity.Buy, Status: entity.Canceled, LotAmount: 5, Currency: "rub"}
+	a.processTraderResp(&stmodel.ActionResp{Action: buy})
+	assert.Equal(t, int64(2), a.figis["figi"].amount)
+	assert.True(t, a.spent["rub"].Equal(decimal.NewFromInt(200)), "got %s", a.spent["rub"])
+}
@@ -0,0 +1,32 @@
+package dca
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+)
+
+//DCA parameters
+const (
+	ScheduleParam string = "schedule"  //Cron-like schedule of buys: minute hour day month weekday
+	TimeZone      string = "time_zone" //Time zone of the schedule
+	Amount        string = "amount"    //Money amount to spend on each instrument at scheduled time
+	MaPeriod      string = "ma_period" //Number of 1-minute candles in moving average, buy skipped when price is above it
+)
+
+//Description is a human-readable description of the strategy
+const Description = "Dollar-cost averaging: buys a fixed money amount of each instrument by cron-like schedule, " +
+	"optionally skipping buys when price is above moving average; money limit is a total budget of all buys"
+
+//ParamSpecs describes parameters accepted by the DCA strategy
+var ParamSpecs = []stmodel.ParamSpec{
+	{Name: ScheduleParam, Type: stmodel.StringParam, Description: "Cron-like schedule of buys 'minute hour day month weekday', e.g. '15 10 * * 1' - every Monday at 10:15",
+		Default: "15 10 * * 1"},
+	{Name: TimeZone, Type: stmodel.StringParam, Description: "IANA time zone of the schedule",
+		Default: "Europe/Moscow"},
+	{Name: Amount, Type: stmodel.DecimalParam, Description: "Money amount to spend on each instrument at scheduled time in limit currency",
+		Required: true, Min: stmodel.DecLimit(0)},
+	{Name: MaPeriod, Type: stmodel.IntParam, Description: "Number of 1-minute candles in moving average, buy is skipped when price is above average; 0 disables check",
+		Default: "0", Min: stmodel.DecLimit(0)},
+	{Name: stbase.OrderExpiration, Type: stmodel.IntParam, Description: "Limited order expiration time in seconds",
+		Default: "300", Min: stmodel.DecLimit(1)},
+}
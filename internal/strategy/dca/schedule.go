@@ -0,0 +1,100 @@
+package dca
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//maxSearch limits search of the next schedule time to avoid infinite loop on never matching schedule (e.g. 30 of February)
+const maxSearch = 366 * 24 * time.Hour
+
+//Schedule is a cron-like schedule with 5 fields: minute, hour, day of month, month and day of week (0 or 7 is Sunday).
+//Each field supports '*', numbers, ranges 'a-b', lists 'a,b' and steps '*/n' or 'a-b/n'
+type Schedule struct {
+	minutes  map[int]bool
+	hours    map[int]bool
+	days     map[int]bool
+	months   map[int]bool
+	weekdays map[int]bool
+	loc      *time.Location
+}
+
+//Match checks if time minute fits schedule in schedule location
+func (s *Schedule) Match(tm time.Time) bool {
+	tm = tm.In(s.loc)
+	return s.minutes[tm.Minute()] && s.hours[tm.Hour()] && s.days[tm.Day()] &&
+		s.months[int(tm.Month())] && s.weekdays[int(tm.Weekday())]
+}
+
+//Next returns the first scheduled minute strictly after the time, returns false if schedule never matches
+func (s *Schedule) Next(after time.Time) (time.Time, bool) {
+	tm := after.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	for end := tm.Add(maxSearch); tm.Before(end); tm = tm.Add(time.Minute) {
+		if s.Match(tm) {
+			return tm, true
+		}
+	}
+	return time.Time{}, false
+}
+
+//ParseSchedule parses cron-like expression with times in the location
+func ParseSchedule(expr string, loc *time.Location) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Schedule '%s' must have 5 fields: minute hour day month weekday", expr))
+	}
+	limits := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := make([]map[int]bool, 0, 5)
+	for i, field := range fields {
+		set, err := parseField(field, limits[i][0], limits[i][1])
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+	//Both 0 and 7 are Sunday
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &Schedule{minutes: sets[0], hours: sets[1], days: sets[2], months: sets[3], weekdays: sets[4], loc: loc}, nil
+}
+
+func parseField(field string, min int, max int) (map[int]bool, error) {
+	res := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return nil, errors.NewValidationErr(fmt.Sprintf("Wrong step in schedule field '%s'", field))
+			}
+		}
+		from, to := min, max
+		if rng != "*" {
+			fromStr, toStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(fromStr); err != nil {
+				return nil, errors.NewValidationErr(fmt.Sprintf("Wrong value in schedule field '%s'", field))
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(toStr); err != nil {
+					return nil, errors.NewValidationErr(fmt.Sprintf("Wrong range in schedule field '%s'", field))
+				}
+			} else if hasStep {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return nil, errors.NewValidationErr(fmt.Sprintf("Schedule field '%s' out of range %d-%d", field, min, max))
+		}
+		for value := from; value <= to; value += step {
+			res[value] = true
+		}
+	}
+	return res, nil
+}
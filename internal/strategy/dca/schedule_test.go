@@ -0,0 +1,40 @@
+package dca
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSchedule_next(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	assert.Nil(t, err)
+	schedule, err := ParseSchedule("15 10 * * 1", loc)
+	assert.Nil(t, err)
+	//Wednesday
+	after := time.Date(2022, 6, 1, 12, 0, 0, 0, loc)
+	next, ok := schedule.Next(after)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2022, 6, 6, 10, 15, 0, 0, loc), next)
+	assert.True(t, schedule.Match(next.In(time.UTC)))
+
+	next, _ = schedule.Next(next)
+	assert.Equal(t, time.Date(2022, 6, 13, 10, 15, 0, 0, loc), next, "Scheduled time itself must be excluded")
+}
+
+func TestParseSchedule(t *testing.T) {
+	schedule, err := ParseSchedule("*/20 9-18/3 1,15 * 7", time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]bool{0: true, 20: true, 40: true}, schedule.minutes)
+	assert.Equal(t, map[int]bool{9: true, 12: true, 15: true, 18: true}, schedule.hours)
+	assert.Equal(t, map[int]bool{1: true, 15: true}, schedule.days)
+	assert.True(t, schedule.weekdays[0], "7 must be Sunday as 0")
+
+	for _, wrong := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err = ParseSchedule(wrong, time.UTC)
+		assert.NotNil(t, err, "expected error for '%s'", wrong)
+	}
+	never, _ := ParseSchedule("0 0 30 2 *", time.UTC)
+	_, ok := never.Next(time.Now())
+	assert.False(t, ok)
+}
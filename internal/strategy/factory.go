@@ -1,47 +1,16 @@
 package strategy
 
 import (
-	"fmt"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
-	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/avr"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/search"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
 	"go.uber.org/zap"
 )
 
-//algProdFunc represents common production algorithm factory method
-type algProdFunc func(req *entity.Algorithm, infoSrv service.InfoSrv, logger *zap.SugaredLogger) (stmodel.Algorithm, error)
-
-//algSandboxFunc represents common sandbox algorithm factory method
-type algSandboxFunc func(req *entity.Algorithm, infoSrv service.InfoSrv, logger *zap.SugaredLogger) (stmodel.Algorithm, error)
-
-//algHistFunc represents common historical algorithm factory method
-type algHistFunc func(req *entity.Algorithm, rep repository.HistoryRepository, logger *zap.SugaredLogger) (stmodel.Algorithm, error)
-
-//Mapping for algorithm creation strategy
-var algMapping = make(map[string]factoryStruct)
-
-//Mapping for parameter splitter for algorithm
-var parSplMapping = make(map[string]stmodel.ParamSplitter)
-
-func initialize(logger *zap.SugaredLogger) {
-	algMapping["avr"] = factoryStruct{
-		algProd:    avr.NewProd,
-		algSandbox: avr.NewSandbox,
-		algHist:    avr.NewHist,
-	}
-	parSplMapping["avr"] = avr.NewParamSplitter(logger)
-}
-
-type factoryStruct struct {
-	algProd    algProdFunc
-	algHist    algHistFunc
-	algSandbox algSandboxFunc
-}
-
 //AlgFactory provides methods to create new algorithms for different environments
 //Also factory caches created algorithms and provide methods with active one
 type AlgFactory interface {
@@ -51,8 +20,10 @@ type AlgFactory interface {
 	NewSandbox(alg *entity.Algorithm) (stmodel.Algorithm, error)
 	//NewHist returns algorithm for simulation on historical data
 	NewHist(alg *entity.Algorithm) (stmodel.Algorithm, error)
-	//NewRange returns slice of algorithms from provided range for simulation on historical data
-	NewRange(alg *entity.Algorithm) ([]stmodel.Algorithm, error)
+	//NewHistOn returns algorithm for simulation on historical data of provided repository, i.e. on part of history
+	NewHistOn(alg *entity.Algorithm, hRep repository.HistoryRepository) (stmodel.Algorithm, error)
+	//NewSearch returns searcher of parameter sets for simulation on historical data from parameter ranges of algorithm
+	NewSearch(alg *entity.Algorithm, conf search.Conf) (search.Searcher, error)
 	//GetProdAlgs returns active algorithms running production environment
 	GetProdAlgs() ([]stmodel.Algorithm, error)
 	//GetSdbxAlgs returns active algorithms running sandbox environment
@@ -63,6 +34,7 @@ type AlgFactory interface {
 
 type DefaultAlgFactory struct {
 	hRep           repository.HistoryRepository
+	algRep         repository.AlgoRepository
 	infoSdxSrv     service.InfoSrv
 	infoProdSrv    service.InfoSrv
 	prodAlgorithms collections.SyncMap[uint, stmodel.Algorithm]
@@ -107,13 +79,11 @@ func (a *DefaultAlgFactory) GetSdbxAlgs() ([]stmodel.Algorithm, error) {
 
 func (a *DefaultAlgFactory) NewProd(alg *entity.Algorithm) (stmodel.Algorithm, error) {
 	a.logger.Infof("Creating new PROD algorithm with strategy: %s and params: %+v", alg.Strategy, alg.Params)
-	factory, exist := algMapping[alg.Strategy]
-	if !exist {
-		return nil, errors.NewUnexpectedError(
-			fmt.Sprintf("Algorithm '%s' does not exist - add mapping to strategy.factory.algMapping", alg.Strategy),
-		)
+	factory, err := a.prepare(alg)
+	if err != nil {
+		return nil, err
 	}
-	res, err := factory.algProd(alg, a.infoSdxSrv, a.logger)
+	res, err := factory.NewProd(alg, a.infoSdxSrv, a.algRep, a.logger)
 	if err == nil {
 		a.prodAlgorithms.Put(alg.ID, res)
 	}
@@ -122,14 +92,11 @@ func (a *DefaultAlgFactory) NewProd(alg *entity.Algorithm) (stmodel.Algorithm, e
 
 func (a *DefaultAlgFactory) NewSandbox(alg *entity.Algorithm) (stmodel.Algorithm, error) {
 	a.logger.Infof("Creating new SANDBOX algorithm with strategy: %s and params: %+v", alg.Strategy, alg.Params)
-	factory, exist := algMapping[alg.Strategy]
-	if !exist {
-		a.logger.Error("No mapping for requested algorithm strategy ", alg.Strategy)
-		return nil, errors.NewUnexpectedError(
-			fmt.Sprintf("Algorithm '%s' does not exist - add mapping to strategy.factory.algMapping", alg.Strategy),
-		)
-	}
-	res, err := factory.algSandbox(alg, a.infoSdxSrv, a.logger)
+	factory, err := a.prepare(alg)
+	if err != nil {
+		return nil, err
+	}
+	res, err := factory.NewSandbox(alg, a.infoSdxSrv, a.algRep, a.logger)
 	if err == nil {
 		a.sdbxAlgorithms.Put(alg.ID, res)
 	}
@@ -137,54 +104,74 @@ func (a *DefaultAlgFactory) NewSandbox(alg *entity.Algorithm) (stmodel.Algorithm
 }
 
 func (a *DefaultAlgFactory) NewHist(alg *entity.Algorithm) (stmodel.Algorithm, error) {
+	return a.NewHistOn(alg, a.hRep)
+}
+
+func (a *DefaultAlgFactory) NewHistOn(alg *entity.Algorithm, hRep repository.HistoryRepository) (stmodel.Algorithm, error) {
 	a.logger.Infof("Creating new history algorithm with strategy: %s , id: %d", alg.Strategy, alg.ID)
-	factory, exist := algMapping[alg.Strategy]
-	if !exist {
-		return nil, errors.NewUnexpectedError(
-			fmt.Sprintf("Algorithm '%s' does not exist - add mapping to strategy.factory.algMapping", alg.Strategy),
-		)
+	factory, err := a.prepare(alg)
+	if err != nil {
+		return nil, err
 	}
-	return factory.algHist(alg, a.hRep, a.logger)
+	return factory.NewHist(alg, hRep, a.logger)
 }
 
-// NewRange Generates range of algorithms working on history data
-func (a *DefaultAlgFactory) NewRange(alg *entity.Algorithm) ([]stmodel.Algorithm, error) {
-	a.logger.Infof("Split algo with strategy: %s with params: %+v", alg.Strategy, alg.Params)
-	splitter, ok := parSplMapping[alg.Strategy]
-	if !ok {
+func (a *DefaultAlgFactory) NewSearch(alg *entity.Algorithm, conf search.Conf) (search.Searcher, error) {
+	a.logger.Infof("Search %s params of algo with strategy: %s with params: %+v", conf.Mode, alg.Strategy, alg.Params)
+	desc, err := getDescriptor(alg.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	var splitter stmodel.ParamSplitter
+	if desc.NewSplitter != nil {
+		splitter = desc.NewSplitter(a.logger)
+	}
+	parMap := entity.ParamsToMap(alg.Params)
+	//Parameter set is valid when strategy splitter keeps it
+	valid := func(params map[string]string) bool {
+		if splitter == nil {
+			return true
+		}
+		split, err := splitter.ParseAndSplit(params)
+		return err == nil && len(split) == 1
+	}
+	space, err := search.NewSpace(parMap, desc.Params, valid)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Mode != search.GridMode {
+		return search.New(conf, space, nil), nil
+	}
+	if splitter == nil {
 		a.logger.Errorf("Splitter for strategy: %s not found", alg.Strategy)
 		return nil, errors.NewUnexpectedError("Splitter not found")
 	}
-	parMap := entity.ParamsToMap(alg.Params)
 	split, err := splitter.ParseAndSplit(parMap)
 	if err != nil {
 		return nil, err
 	}
-	algoRange := make([]stmodel.Algorithm, 0, len(split))
-	for id, param := range split {
-		currAlg := alg.CopyNoParam()
-		currAlg.ID = uint(id)
-		paramStruct := make([]*entity.Param, 0, len(param))
-		for key, value := range param {
-			paramStruct = append(paramStruct, &entity.Param{Key: key, Value: value})
-		}
-		currAlg.Params = paramStruct
-		algo, err := a.NewHist(currAlg)
+	return search.New(conf, space, space.Expand(split)), nil
+}
 
-		if err != nil {
-			return nil, err
-		}
-		algoRange = append(algoRange, algo)
+//prepare finds strategy descriptor and validates algorithm parameters
+func (a *DefaultAlgFactory) prepare(alg *entity.Algorithm) (*StrategyDescriptor, error) {
+	factory, err := getDescriptor(alg.Strategy)
+	if err != nil {
+		a.logger.Error("No registered strategy ", alg.Strategy)
+		return nil, err
+	}
+	if err = Validate(alg); err != nil {
+		a.logger.Errorf("Algorithm parameters not valid: %s", err)
+		return nil, err
 	}
-	return algoRange, nil
+	return factory, nil
 }
 
 func NewAlgFactory(infoSdxSrv service.InfoSrv, infoProdSrv service.InfoSrv, rep repository.HistoryRepository,
-	logger *zap.SugaredLogger) AlgFactory {
-	initialize(logger)
-
+	algRep repository.AlgoRepository, logger *zap.SugaredLogger) AlgFactory {
 	return &DefaultAlgFactory{
 		hRep:           rep,
+		algRep:         algRep,
 		infoSdxSrv:     infoSdxSrv,
 		infoProdSrv:    infoProdSrv,
 		prodAlgorithms: collections.NewSyncMap[uint, stmodel.Algorithm](),
@@ -0,0 +1,348 @@
+package grid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"github.com/tevino/abool/v2"
+	"go.uber.org/zap"
+	"time"
+)
+
+//SlotsField is a context parameter key with lots held by grid slots
+const SlotsField string = "gridSlots"
+
+//AlgorithmImpl is grid trading algorithm.
+//Price range between lower and upper prices is split to levels, neighbour levels make slot.
+//Algorithm keeps limit buy order on each slot which buy level is below current price,
+//when buy is filled - posts limit sell order on the upper level of the slot, when sell is filled - slot is re-armed.
+//Unlike other algorithms grid keeps several orders in progress for each instrument - one per slot.
+//In history mode algorithm sends time of processed candles to trader, so resting orders are filled by history prices
+type AlgorithmImpl struct {
+	id        uint                     //Algorithm id extracted for more convenience
+	isActive  *abool.AtomicBool        //Atomic bool indicating is algorithm active
+	dataProc  candle.DataProc          //Data processor - provides candles as the channel for algorithm
+	accountId string                   //Account id extracted for more convenience
+	limits    []*entity.MoneyLimit     //Limits of money available for algorithm
+	algorithm *entity.Algorithm        //Link to original object which algorithm based
+	param     map[string]string        //Map of algorithm configuration parameters
+	grids     map[string]*figiGrid     //Grid of each instrument
+	limitPart decimal.Decimal          //Part of money limit to spend on a single slot buy
+	ordExp    time.Duration            //Expiration duration of posted orders
+	lastTime  time.Time                //Time of the last processed candle
+	aChan     chan *stmodel.ActionReq  //Channel to send order requests to trader
+	arChan    chan *stmodel.ActionResp //Channel to receive responses from trader about action result
+	clock     *stbase.SimClock         //Simulation clock of history trader, disabled in production
+	algRep    repository.AlgoRepository
+	ctx       context.Context
+	cancelF   context.CancelFunc
+
+	logger *zap.SugaredLogger
+}
+
+func (a *AlgorithmImpl) Subscribe() (*stmodel.Subscription, error) {
+	if a.aChan != nil || a.arChan != nil {
+		return nil, errors.NewDoubleSubErr("Grid algorithm multiple subscription not implemented")
+	}
+	//Each slot may have order in progress, buffer must fit all of them to not block algorithm
+	size := 1
+	for _, grid := range a.grids {
+		size += len(grid.slots)
+	}
+	a.aChan = make(chan *stmodel.ActionReq, size)
+	a.arChan = make(chan *stmodel.ActionResp, 1) //must not block trader, so size = 1
+	sub := &stmodel.Subscription{AlgoID: a.id, AChan: a.aChan, RChan: a.arChan}
+	a.clock.Subscribe(sub)
+	return sub, nil
+}
+
+func (a *AlgorithmImpl) IsActive() bool {
+	return a.isActive.IsSet()
+}
+
+func (a *AlgorithmImpl) Go(parCtx context.Context) error {
+	a.ctx, a.cancelF = context.WithCancel(parCtx)
+	ch, err := a.dataProc.GetDataStream()
+	if err != nil {
+		return err
+	}
+	go a.procBg(ch)
+	err = a.dataProc.Go(a.ctx)
+	if err != nil {
+		a.logger.Error("Error while starting data processor: ", err)
+		a.stopInternal()
+		return errors.NewUnexpectedError("Error while starting data processor " + err.Error())
+	}
+	a.isActive.Set()
+	return nil
+}
+
+func (a *AlgorithmImpl) procBg(datCh <-chan candle.Candle) {
+	defer func() {
+		a.isActive.UnSet()
+		close(a.aChan)
+		a.clock.Close(a.arChan)
+		a.logger.Infof("Stopping algorithm background; ID: %d", a.id)
+	}()
+	a.logger.Infof("Starting background algorithm processing; id: %d , strategy: grid , limits: %+v", a.id, a.limits)
+	for {
+		select {
+		case resp, ok := <-a.arChan:
+			if !ok {
+				a.logger.Warn("Trader closed response channel, stopping algorithm...")
+				return
+			}
+			a.processTraderResp(resp)
+		case cDat, ok := <-datCh:
+			if !ok {
+				a.logger.Infof("Closed data processor stream, stopping algorithm...")
+				return
+			}
+			a.processCandle(&cDat)
+			if !a.clock.Send(a.ctx, cDat.RetrievedAt, a.arChan, a.processTraderResp) {
+				return
+			}
+		case <-a.ctx.Done():
+			a.logger.Info("Context canceled, stopping...")
+			return
+		}
+	}
+}
+
+//processCandle posts orders of slots which must be armed by the candle price
+func (a *AlgorithmImpl) processCandle(cDat *candle.Candle) {
+	a.lastTime = cDat.RetrievedAt
+	grid, ok := a.grids[cDat.Figi]
+	if !ok {
+		a.logger.Warnf("Received candle of figi %s not presented in grid", cDat.Figi)
+		return
+	}
+	for _, sl := range grid.toPost(cDat.Close) {
+		a.post(grid, sl, cDat.Close)
+	}
+}
+
+//process response from trade.Trader after requested passed trading stages
+func (a *AlgorithmImpl) processTraderResp(resp *stmodel.ActionResp) {
+	action := resp.Action
+	a.logger.Debug("Processing trader response: ", *action)
+	grid, ok := a.grids[action.InstrFigi]
+	if !ok {
+		a.logger.Warnf("Received response of figi %s not presented in grid", action.InstrFigi)
+		return
+	}
+	sl, ok := grid.findSlot(action)
+	if !ok {
+		a.logger.Warnf("Slot of action not found: %+v", action)
+		return
+	}
+	if sl.onResponse(action) {
+		if action.Status != entity.Success {
+			a.logger.Infof("Order partially executed: %s", action.Info)
+		}
+		a.logger.Infof("Order completed: %+v, slot lots: %d", action, sl.lots)
+		//Re-arm slot immediately; failed orders are re-armed with the next candle
+		if sl.status == holding {
+			a.post(grid, sl, action.ReqPrice)
+		}
+	} else {
+		a.logger.Infof("Operation failed %+v", resp)
+	}
+	a.updateState()
+	stbase.PersistState(a.algRep, a.algorithm, action, a.logger)
+}
+
+func (a *AlgorithmImpl) post(grid *figiGrid, sl *slot, price decimal.Decimal) {
+	//Time of processed data is used instead of current one to make expiration correct in history mode
+	tm := a.lastTime
+	if tm.IsZero() {
+		tm = time.Now()
+	}
+	action := entity.Action{
+		AlgorithmID:    a.id,
+		InstrFigi:      grid.figi,
+		ExpirationTime: tm.Add(a.ordExp),
+		Status:         entity.Created,
+		OrderType:      entity.Limited,
+		RetrievedAt:    tm,
+		AccountID:      a.accountId,
+	}
+	req := stmodel.ActionReq{Action: &action, Limits: a.limits}
+	if sl.status == holding {
+		action.Direction = entity.Sell
+		action.ReqPrice = sl.sellPrice
+		action.LotAmount = sl.lots
+		sl.status = sellPosted
+	} else {
+		action.Direction = entity.Buy
+		action.ReqPrice = sl.buyPrice
+		req.LimitPart = a.limitPart
+		sl.status = buyPosted
+	}
+	sl.action = &action
+	a.logger.Infof("Arming grid slot by price %s, requesting action: %+v", price, action)
+	a.aChan <- &req
+}
+
+//updateState updates algorithm context parameters - lots of slots and amount of instruments
+func (a *AlgorithmImpl) updateState() {
+	slots := make(map[string][]int64)
+	instrAmount := make(map[string]int64)
+	for figi, grid := range a.grids {
+		lots := make([]int64, 0, len(grid.slots))
+		for _, sl := range grid.slots {
+			lots = append(lots, sl.lots)
+		}
+		slots[figi] = lots
+		instrAmount[figi] = grid.amount()
+	}
+	res, err := json.Marshal(slots)
+	if err != nil {
+		a.logger.Errorf("Error while marshalling state of algorithm %d: %s", a.id, err)
+		return
+	}
+	a.algorithm.SetCtxParam(SlotsField, string(res))
+	if err = stbase.SetInstrumentsState(a.algorithm, instrAmount, nil); err != nil {
+		a.logger.Errorf("Error while marshalling state of algorithm %d: %s", a.id, err)
+	}
+}
+
+func (a *AlgorithmImpl) Stop() error {
+	if a.isActive.IsNotSet() {
+		a.logger.Info("Algorithm already stopped, do nothing...")
+		return nil
+	}
+	a.stopInternal()
+	return nil
+}
+
+func (a *AlgorithmImpl) stopInternal() {
+	a.cancelF()
+	a.logger.Infof("Algorithm %d successfully stopped", a.id)
+}
+
+//Configure restores lots held by slots and re-attaches posted orders to slots
+func (a *AlgorithmImpl) Configure(ctx []*entity.CtxParam) error {
+	slotsStr, ok := entity.ContextToMap(ctx)[SlotsField]
+	if ok {
+		slots := make(map[string][]int64)
+		if err := json.Unmarshal([]byte(slotsStr), &slots); err != nil {
+			a.logger.Warnf("Unable unmarshal '%s' to grid slots", slotsStr)
+			return err
+		}
+		for figi, lots := range slots {
+			grid, exist := a.grids[figi]
+			if !exist || len(grid.slots) != len(lots) {
+				a.logger.Warnf("Grid of %s does not match saved slots %v, skipping", figi, lots)
+				continue
+			}
+			for i, lot := range lots {
+				grid.slots[i].lots = lot
+				if lot > 0 {
+					grid.slots[i].status = holding
+				}
+			}
+		}
+	}
+	for _, action := range a.algorithm.Actions {
+		if action.Status != entity.Posted {
+			continue
+		}
+		grid, exist := a.grids[action.InstrFigi]
+		if !exist {
+			continue
+		}
+		if sl, found := grid.findSlot(action); found {
+			sl.action = action
+			if action.Direction == entity.Buy {
+				sl.status = buyPosted
+			} else {
+				sl.status = sellPosted
+			}
+		}
+	}
+	return nil
+}
+
+func (a *AlgorithmImpl) GetParam() map[string]string {
+	return a.param
+}
+
+func (a *AlgorithmImpl) GetAlgorithm() *entity.Algorithm {
+	return a.algorithm
+}
+
+//NewProd constructs new grid algorithm using production data processor
+func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newGrid(algo, algRep, logger, candle.NewProdDataProc(algo, infoSrv, 0, logger), false)
+}
+
+//NewSandbox constructs new grid algorithm using production data processor cause it the same for such algorithm
+func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newGrid(algo, algRep, logger, candle.NewProdDataProc(algo, infoSrv, 0, logger), false)
+}
+
+//NewHist constructs new grid algorithm using history data processor and providing simulation clock to trader
+func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	logger := stbase.HistLogger(rootLogger)
+	return newGrid(algo, nil, logger, candle.NewHistDataProc(algo, hRep, logger), true)
+}
+
+//NewParamSplitter creates splitter varying grid prices and number of levels, only combinations with lower price below upper one are used
+func NewParamSplitter(logger *zap.SugaredLogger) stmodel.ParamSplitter {
+	return stbase.NewRangeSplitter(ParamSpecs, []string{LowerPrice, UpperPrice, Levels}, pricesFilter, logger)
+}
+
+func pricesFilter(values map[string]decimal.Decimal) bool {
+	return values[LowerPrice].LessThan(values[UpperPrice])
+}
+
+func newGrid(algo *entity.Algorithm, algRep repository.AlgoRepository, logger *zap.SugaredLogger,
+	proc candle.DataProc, simClock bool) (stmodel.Algorithm, error) {
+	paramMap := entity.ParamsToMap(algo.Params)
+	lower, _ := stbase.GetDecimal(paramMap, LowerPrice)
+	upper, _ := stbase.GetDecimal(paramMap, UpperPrice)
+	if !lower.LessThan(upper) {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be less than '%s'", LowerPrice, UpperPrice))
+	}
+	levels := stbase.GetOrDefaultInt(paramMap, Levels, 5)
+	if levels < 2 || len(algo.Figis) == 0 {
+		return nil, errors.NewValidationErr("Grid requires at least 2 levels and 1 instrument")
+	}
+	grids := make(map[string]*figiGrid)
+	for _, figi := range algo.Figis {
+		grids[figi] = newFigiGrid(figi, lower, upper, levels)
+	}
+	//Money limit is split equally between all slots of all instruments
+	slotNum := int64((levels - 1) * len(algo.Figis))
+	algorithm := &AlgorithmImpl{
+		id:        algo.ID,
+		isActive:  abool.NewBool(true),
+		dataProc:  proc,
+		accountId: algo.AccountId,
+		limits:    algo.MoneyLimits,
+		algorithm: algo,
+		param:     paramMap,
+		grids:     grids,
+		limitPart: decimal.NewFromInt(1).Div(decimal.NewFromInt(slotNum)),
+		ordExp:    time.Duration(stbase.GetOrDefaultInt(paramMap, stbase.OrderExpiration, 86400)) * time.Second,
+		clock:     stbase.NewSimClock(simClock, logger),
+		algRep:    algRep,
+		logger:    logger,
+	}
+	if err := algorithm.Configure(algo.CtxParams); err != nil {
+		logger.Errorf("Failed configure algorithm %d with configuration %+v", algo.ID, algo.CtxParams)
+		return nil, err
+	}
+	return algorithm, nil
+}
@@ -0,0 +1,95 @@
+package grid
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/shopspring/decimal"
+)
+
+type slotStatus int
+
+const (
+	idle       slotStatus = iota //No order and no instruments - buy will be posted when price is above buy level
+	buyPosted                    //Buy order posted and waiting for result
+	holding                      //Instruments bought, sell order must be posted
+	sellPosted                   //Sell order posted and waiting for result
+)
+
+//slot is a pair of neighbour grid levels - instruments bought on the lower level are sold on the upper one
+type slot struct {
+	buyPrice  decimal.Decimal
+	sellPrice decimal.Decimal
+	status    slotStatus
+	lots      int64          //Lots bought on the slot and not sold yet
+	action    *entity.Action //Order in progress
+}
+
+//figiGrid keeps slots of single instrument, each slot may have its own order in progress
+type figiGrid struct {
+	figi  string
+	slots []*slot
+}
+
+//toPost returns slots which orders must be posted by the current price:
+//idle slots with buy level lower than price and slots holding instruments without sell order
+func (g *figiGrid) toPost(price decimal.Decimal) []*slot {
+	res := make([]*slot, 0)
+	for _, sl := range g.slots {
+		if (sl.status == idle && sl.buyPrice.LessThan(price)) || sl.status == holding {
+			res = append(res, sl)
+		}
+	}
+	return res
+}
+
+//findSlot searches slot the action was requested for - by action itself or by direction and level price for restored actions
+func (g *figiGrid) findSlot(action *entity.Action) (*slot, bool) {
+	for _, sl := range g.slots {
+		if sl.action == action {
+			return sl, true
+		}
+	}
+	for _, sl := range g.slots {
+		if (action.Direction == entity.Buy && sl.buyPrice.Equal(action.ReqPrice)) ||
+			(action.Direction == entity.Sell && sl.sellPrice.Equal(action.ReqPrice)) {
+			return sl, true
+		}
+	}
+	return nil, false
+}
+
+//onResponse updates slot by trader response, returns true if lots were executed by order -
+//canceled order may be partially filled
+func (sl *slot) onResponse(action *entity.Action) bool {
+	sl.action = nil
+	executed := action.ExecutedAmount()
+	sl.lots += executed
+	if sl.lots < 0 {
+		sl.lots = 0
+	}
+	if sl.lots > 0 {
+		sl.status = holding
+	} else {
+		sl.status = idle
+	}
+	return executed != 0
+}
+
+//amount returns lots of instrument held by all slots
+func (g *figiGrid) amount() int64 {
+	var res int64
+	for _, sl := range g.slots {
+		res += sl.lots
+	}
+	return res
+}
+
+//newFigiGrid creates grid with levels evenly spaced between lower and upper prices
+func newFigiGrid(figi string, lower decimal.Decimal, upper decimal.Decimal, levels int) *figiGrid {
+	step := upper.Sub(lower).Div(decimal.NewFromInt(int64(levels - 1)))
+	slots := make([]*slot, 0, levels-1)
+	for i := 0; i < levels-1; i++ {
+		buyPrice := lower.Add(step.Mul(decimal.NewFromInt(int64(i))))
+		slots = append(slots, &slot{buyPrice: buyPrice, sellPrice: buyPrice.Add(step)})
+	}
+	return &figiGrid{figi: figi, slots: slots}
+}
@@ -0,0 +1,98 @@
+package grid
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	mock_repository "github.com/ldmi3i/tinkoff-invest-bot/internal/mocks/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestFigiGrid_slots(t *testing.T) {
+	grid := newFigiGrid("figi", decimal.NewFromInt(90), decimal.NewFromInt(110), 5)
+	assert.Equal(t, 4, len(grid.slots))
+	assert.True(t, grid.slots[1].buyPrice.Equal(decimal.NewFromInt(95)))
+	assert.True(t, grid.slots[1].sellPrice.Equal(decimal.NewFromInt(100)))
+
+	toPost := grid.toPost(decimal.NewFromInt(100))
+	assert.Equal(t, 2, len(toPost), "Only slots with buy level below price must be armed")
+
+	buy := &entity.Action{Direction: entity.Buy, ReqPrice: decimal.NewFromInt(95), LotAmount: 3, Status: entity.Success}
+	sl, ok := grid.findSlot(buy)
+	assert.True(t, ok)
+	assert.True(t, sl.onResponse(buy))
+	assert.Equal(t, holding, sl.status)
+	assert.Equal(t, int64(3), grid.amount())
+
+	sell := &entity.Action{Direction: entity.Sell, ReqPrice: decimal.NewFromInt(100), LotAmount: 3, Status: entity.Canceled}
+	sl, _ = grid.findSlot(sell)
+	assert.False(t, sl.onResponse(sell))
+	assert.Equal(t, holding, sl.status, "Instruments are still held after canceled sell")
+}
+
+func TestGrid_history(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	hist := make([]entity.History, 0)
+	for i, price := range []int64{100, 97, 95, 92, 90, 93, 95, 98, 100, 101} {
+		hist = append(hist, entity.History{Figi: "figi", Close: decimal.NewFromInt(price), Time: start.Add(time.Duration(i) * time.Minute)})
+	}
+	hRep := mock_repository.NewMockHistoryRepository(ctrl)
+	hRep.EXPECT().FindAllByFigis(gomock.Any()).Return(hist, nil).AnyTimes()
+	logger := zap.NewNop().Sugar()
+
+	algo := &entity.Algorithm{
+		Figis:       []string{"figi"},
+		MoneyLimits: []*entity.MoneyLimit{{Currency: "rub", Amount: decimal.NewFromInt(1000)}},
+		Params: []*entity.Param{
+			{Key: LowerPrice, Value: "90"},
+			{Key: UpperPrice, Value: "110"},
+			{Key: Levels, Value: "5"},
+		},
+	}
+	alg, err := NewHist(algo, hRep, logger)
+	assert.Nil(t, err)
+	sub, err := alg.Subscribe()
+	assert.Nil(t, err)
+	trader := trade.NewMockTrader(hRep, map[string]int64{"figi": 1}, map[string]string{"figi": "rub"}, nil, logger)
+	assert.Nil(t, trader.AddSubscription(sub))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	trader.Go(ctx)
+	assert.Nil(t, alg.Go(ctx))
+	stat := <-trader.GetStatCh()
+
+	//Buys on 95 and 90 levels are filled on the way down, sells on 95 and 100 levels on the way up
+	assert.Equal(t, uint(2), stat.BuyOpNum)
+	assert.Equal(t, uint(2), stat.SellOpNum)
+	//250 money per slot: 2 lots by 95 sold by 100 and 2 lots by 90 sold by 95
+	assert.True(t, stat.CurBalance["rub"].Equal(decimal.NewFromInt(20)), "expected 20, got %s", stat.CurBalance["rub"])
+}
+
+func TestSlot_partialFill(t *testing.T) {
+	grid := newFigiGrid("figi", decimal.NewFromInt(90), decimal.NewFromInt(110), 5)
+
+	//Expired buy canceled after 2 of 3 lots executed - slot holds executed lots
+	buy := &entity.Action{Direction: entity.Buy, ReqPrice: decimal.NewFromInt(95), LotAmount: 3, LotsExecuted: 2, Status: entity.Canceled}
+	sl, _ := grid.findSlot(buy)
+	assert.True(t, sl.onResponse(buy))
+	assert.Equal(t, holding, sl.status)
+	assert.Equal(t, int64(2), grid.amount())
+
+	//Sell canceled after 1 of 2 lots executed - rest of lots is still held
+	sell := &entity.Action{Direction: entity.Sell, ReqPrice: decimal.NewFromInt(100), LotAmount: 2, LotsExecuted: 1, Status: entity.Canceled}
+	assert.True(t, sl.onResponse(sell))
+	assert.Equal(t, holding, sl.status)
+	assert.Equal(t, int64(1), grid.amount())
+
+	//Filled sell of the rest frees slot
+	sell = &entity.Action{Direction: entity.Sell, ReqPrice: decimal.NewFromInt(100), LotAmount: 1, LotsExecuted: 1, Status: entity.Success}
+	assert.True(t, sl.onResponse(sell))
+	assert.Equal(t, idle, sl.status)
+	assert.Equal(t, int64(0), grid.amount())
+}
@@ -0,0 +1,29 @@
+package grid
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+)
+
+//Grid parameters
+const (
+	LowerPrice string = "lower_price" //Price of the lowest grid level
+	UpperPrice string = "upper_price" //Price of the highest grid level
+	Levels     string = "levels"      //Number of grid levels including lower and upper ones
+)
+
+//Description is a human-readable description of the strategy
+const Description = "Grid trading for range-bound instruments: keeps limit buy orders on grid levels below current price " +
+	"and limit sell order on the next level above each filled buy, re-arming levels when orders are filled"
+
+//ParamSpecs describes parameters accepted by the grid strategy
+var ParamSpecs = []stmodel.ParamSpec{
+	{Name: LowerPrice, Type: stmodel.DecimalParam, Description: "Price of the lowest grid level",
+		Required: true, Min: stmodel.DecLimit(0)},
+	{Name: UpperPrice, Type: stmodel.DecimalParam, Description: "Price of the highest grid level, must be greater than lower price",
+		Required: true, Min: stmodel.DecLimit(0)},
+	{Name: Levels, Type: stmodel.IntParam, Description: "Number of evenly spaced grid levels including lower and upper prices",
+		Default: "5", Min: stmodel.DecLimit(2)},
+	{Name: stbase.OrderExpiration, Type: stmodel.IntParam, Description: "Resting limit order expiration time in seconds, expired levels are re-armed",
+		Default: "86400", Min: stmodel.DecLimit(1)},
+}
@@ -0,0 +1,48 @@
+package indicator
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+	"math"
+)
+
+//Bands represents Bollinger bands - simple average and bands on k standard deviations around it
+type Bands struct {
+	Middle decimal.Decimal
+	Upper  decimal.Decimal
+	Lower  decimal.Decimal
+}
+
+//Width returns band width relative to middle band in percents
+func (b Bands) Width() decimal.Decimal {
+	if b.Middle.IsZero() {
+		return decimal.Zero
+	}
+	return b.Upper.Sub(b.Lower).Div(b.Middle).Mul(hundred)
+}
+
+//CalcBands calculates average and population standard deviation of values in the list
+//and returns bands on k deviations from average
+func CalcBands(lst *collections.TList[decimal.Decimal], k decimal.Decimal) (Bands, error) {
+	if lst.IsEmpty() {
+		return Bands{}, errors.NewUnexpectedError("requested bands calc on empty list")
+	}
+	num := decimal.NewFromInt(int64(lst.GetSize()))
+	sum := decimal.Zero
+	for node := lst.First(); node != nil; node = node.Next() {
+		sum = sum.Add(node.GetData())
+	}
+	avr := sum.Div(num)
+	sqSum := decimal.Zero
+	for node := lst.First(); node != nil; node = node.Next() {
+		diff := node.GetData().Sub(avr)
+		sqSum = sqSum.Add(diff.Mul(diff))
+	}
+	std := decimal.NewFromFloat(math.Sqrt(sqSum.Div(num).InexactFloat64()))
+	return Bands{
+		Middle: avr,
+		Upper:  avr.Add(std.Mul(k)),
+		Lower:  avr.Sub(std.Mul(k)),
+	}, nil
+}
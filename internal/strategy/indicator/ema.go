@@ -0,0 +1,56 @@
+package indicator
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//EMA calculates exponential moving average of candle values with smoothing factor 2 / (period + 1).
+//Value of the same candle replaces the last one - average is recalculated from the previous candle value.
+//The first candle value is used as initial average
+type EMA struct {
+	period  int
+	alpha   decimal.Decimal
+	num     int             //Number of candles processed
+	lastTm  time.Time       //Time of the last candle
+	prev    decimal.Decimal //Average on previous candle
+	current decimal.Decimal //Average including the last candle
+}
+
+//Add processes value of new candle or replaces value of the last candle when candle time is the same.
+//Returns current average
+func (e *EMA) Add(tm time.Time, value decimal.Decimal) decimal.Decimal {
+	if e.num > 0 && e.lastTm.Equal(tm) {
+		e.current = e.calc(value)
+		return e.current
+	}
+	e.prev = e.current
+	e.lastTm = tm
+	e.num++
+	e.current = e.calc(value)
+	return e.current
+}
+
+func (e *EMA) calc(value decimal.Decimal) decimal.Decimal {
+	if e.num == 1 {
+		return value
+	}
+	return value.Sub(e.prev).Mul(e.alpha).Add(e.prev)
+}
+
+//Value returns current average
+func (e *EMA) Value() decimal.Decimal {
+	return e.current
+}
+
+//IsReady returns true when number of processed candles reached period
+func (e *EMA) IsReady() bool {
+	return e.num >= e.period
+}
+
+func NewEMA(period int) *EMA {
+	return &EMA{
+		period: period,
+		alpha:  decimal.NewFromInt(2).Div(decimal.NewFromInt(int64(period + 1))),
+	}
+}
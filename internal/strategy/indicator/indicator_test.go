@@ -0,0 +1,101 @@
+package indicator
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSeries_sameCandleReplacesValue(t *testing.T) {
+	series := NewSeries(2)
+	start := time.Now()
+	assert.True(t, series.Add(start, decimal.NewFromInt(1)))
+	assert.False(t, series.Add(start, decimal.NewFromInt(2)))
+	assert.Equal(t, 1, series.Len())
+	assert.True(t, series.Last().Equal(decimal.NewFromInt(2)))
+
+	series.Add(start.Add(time.Minute), decimal.NewFromInt(3))
+	series.Add(start.Add(2*time.Minute), decimal.NewFromInt(4))
+	assert.True(t, series.IsFull())
+	assert.Equal(t, 2, series.Len())
+	assert.True(t, series.Values()[0].Equal(decimal.NewFromInt(3)))
+}
+
+func TestSeries_mean(t *testing.T) {
+	series := NewSeries(2)
+	assert.True(t, series.Mean().IsZero())
+	start := time.Now()
+	series.Add(start, decimal.NewFromInt(10))
+	series.Add(start.Add(time.Minute), decimal.NewFromInt(20))
+	series.Add(start.Add(2*time.Minute), decimal.NewFromInt(40))
+	assert.True(t, series.Mean().Equal(decimal.NewFromInt(30)), "expected 30, got %s", series.Mean())
+}
+
+func TestRSI(t *testing.T) {
+	values := []decimal.Decimal{
+		decimal.NewFromInt(10),
+		decimal.NewFromInt(13), //+3
+		decimal.NewFromInt(12), //-1
+		decimal.NewFromInt(12), //0
+	}
+	rsi, err := RSI(values)
+	assert.Nil(t, err)
+	assert.True(t, rsi.Equal(decimal.NewFromInt(75)), "expected 75, got %s", rsi)
+
+	growth := []decimal.Decimal{decimal.NewFromInt(1), decimal.NewFromInt(2)}
+	rsi, _ = RSI(growth)
+	assert.True(t, rsi.Equal(decimal.NewFromInt(100)))
+
+	flat := []decimal.Decimal{decimal.NewFromInt(1), decimal.NewFromInt(1)}
+	rsi, _ = RSI(flat)
+	assert.True(t, rsi.Equal(decimal.NewFromInt(50)))
+
+	_, err = RSI(values[:1])
+	assert.NotNil(t, err)
+}
+
+func TestCalcBands(t *testing.T) {
+	lst := collections.NewTList[decimal.Decimal](time.Hour)
+	start := time.Now()
+	for i, value := range []int64{2, 4, 4, 4, 5, 5, 7, 9} {
+		lst.Append(decimal.NewFromInt(value), start.Add(time.Duration(i)*time.Minute))
+	}
+	bands, err := CalcBands(&lst, decimal.NewFromInt(2))
+	assert.Nil(t, err)
+	assert.True(t, bands.Middle.Equal(decimal.NewFromInt(5)), "expected 5, got %s", bands.Middle)
+	assert.True(t, bands.Upper.Equal(decimal.NewFromInt(9)), "expected 9, got %s", bands.Upper)
+	assert.True(t, bands.Lower.Equal(decimal.NewFromInt(1)), "expected 1, got %s", bands.Lower)
+	assert.True(t, bands.Width().Equal(decimal.NewFromInt(160)), "expected 160, got %s", bands.Width())
+
+	empty := collections.NewTList[decimal.Decimal](time.Hour)
+	_, err = CalcBands(&empty, decimal.NewFromInt(2))
+	assert.NotNil(t, err)
+}
+
+func TestEMA_sameCandleRecalculated(t *testing.T) {
+	ema := NewEMA(3) //alpha = 0.5
+	start := time.Now()
+	assert.True(t, ema.Add(start, decimal.NewFromInt(10)).Equal(decimal.NewFromInt(10)))
+	assert.False(t, ema.IsReady())
+	assert.True(t, ema.Add(start.Add(time.Minute), decimal.NewFromInt(20)).Equal(decimal.NewFromInt(15)))
+	//Update of the same candle must be calculated from the previous candle average
+	assert.True(t, ema.Add(start.Add(time.Minute), decimal.NewFromInt(14)).Equal(decimal.NewFromInt(12)))
+	assert.True(t, ema.Add(start.Add(2*time.Minute), decimal.NewFromInt(16)).Equal(decimal.NewFromInt(14)))
+	assert.True(t, ema.IsReady())
+}
+
+func TestZScore(t *testing.T) {
+	values := []decimal.Decimal{decimal.NewFromInt(2), decimal.NewFromInt(4), decimal.NewFromInt(4), decimal.NewFromInt(4),
+		decimal.NewFromInt(5), decimal.NewFromInt(5), decimal.NewFromInt(7), decimal.NewFromInt(9)}
+	//Average 5, deviation 2
+	z, err := ZScore(values, decimal.NewFromInt(9))
+	assert.Nil(t, err)
+	assert.True(t, z.Equal(decimal.NewFromInt(2)), "expected 2, got %s", z)
+
+	z, _ = ZScore([]decimal.Decimal{decimal.NewFromInt(1), decimal.NewFromInt(1)}, decimal.NewFromInt(1))
+	assert.True(t, z.IsZero())
+	_, err = ZScore(nil, decimal.Zero)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,41 @@
+package indicator
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//MACD calculates moving average convergence/divergence: difference of fast and slow EMA,
+//signal line as EMA of the difference and histogram as difference of MACD and signal line
+type MACD struct {
+	fast   *EMA
+	slow   *EMA
+	signal *EMA
+}
+
+//MACDValue is a result of MACD calculation
+type MACDValue struct {
+	MACD      decimal.Decimal
+	Signal    decimal.Decimal
+	Histogram decimal.Decimal
+}
+
+//Add processes value of the candle and returns current MACD values
+func (m *MACD) Add(tm time.Time, value decimal.Decimal) MACDValue {
+	macd := m.fast.Add(tm, value).Sub(m.slow.Add(tm, value))
+	signal := m.signal.Add(tm, macd)
+	return MACDValue{MACD: macd, Signal: signal, Histogram: macd.Sub(signal)}
+}
+
+//IsReady returns true when all averages processed number of candles not less than their periods
+func (m *MACD) IsReady() bool {
+	return m.fast.IsReady() && m.slow.IsReady() && m.signal.IsReady()
+}
+
+func NewMACD(fast int, slow int, signal int) *MACD {
+	return &MACD{
+		fast:   NewEMA(fast),
+		slow:   NewEMA(slow),
+		signal: NewEMA(signal),
+	}
+}
@@ -0,0 +1,33 @@
+package indicator
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+//RSI calculates relative strength index of values using simple averages of gains and losses (Cutler's RSI).
+//Number of periods is number of values minus one.
+//When there were no price changes at all - returns neutral 50
+func RSI(values []decimal.Decimal) (decimal.Decimal, error) {
+	if len(values) < 2 {
+		return decimal.Zero, errors.NewUnexpectedError("RSI requires at least 2 values")
+	}
+	gain := decimal.Zero
+	loss := decimal.Zero
+	for i := 1; i < len(values); i++ {
+		diff := values[i].Sub(values[i-1])
+		if diff.IsPositive() {
+			gain = gain.Add(diff)
+		} else {
+			loss = loss.Sub(diff)
+		}
+	}
+	total := gain.Add(loss)
+	if total.IsZero() {
+		return decimal.NewFromInt(50), nil
+	}
+	//RSI = 100 - 100 / (1 + gain / loss) which equals to 100 * gain / (gain + loss)
+	return hundred.Mul(gain).Div(total), nil
+}
@@ -0,0 +1,74 @@
+package indicator
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//Series keeps last values of candles - one value per candle.
+//In production the same candle is received several times while it's not finished,
+//so value of the same candle replaces the last one instead of appending
+type Series struct {
+	size   int
+	times  []time.Time
+	values []decimal.Decimal
+}
+
+//Add appends value of new candle or replaces the last value when candle time is the same.
+//Returns true if new value was appended
+func (s *Series) Add(tm time.Time, value decimal.Decimal) bool {
+	ln := len(s.values)
+	if ln > 0 && s.times[ln-1].Equal(tm) {
+		s.values[ln-1] = value
+		return false
+	}
+	s.times = append(s.times, tm)
+	s.values = append(s.values, value)
+	if len(s.values) > s.size {
+		s.times = s.times[1:]
+		s.values = s.values[1:]
+	}
+	return true
+}
+
+//Values returns values from the oldest to the newest one
+func (s *Series) Values() []decimal.Decimal {
+	return s.values
+}
+
+func (s *Series) Len() int {
+	return len(s.values)
+}
+
+//IsFull returns true when series contains required number of values
+func (s *Series) IsFull() bool {
+	return len(s.values) == s.size
+}
+
+//Last returns the newest value or zero if series is empty
+func (s *Series) Last() decimal.Decimal {
+	if len(s.values) == 0 {
+		return decimal.Zero
+	}
+	return s.values[len(s.values)-1]
+}
+
+func NewSeries(size int) *Series {
+	return &Series{
+		size:   size,
+		times:  make([]time.Time, 0, size+1),
+		values: make([]decimal.Decimal, 0, size+1),
+	}
+}
+
+//Mean returns simple moving average of series values or zero if series is empty
+func (s *Series) Mean() decimal.Decimal {
+	if len(s.values) == 0 {
+		return decimal.Zero
+	}
+	sum := decimal.Zero
+	for _, value := range s.values {
+		sum = sum.Add(value)
+	}
+	return sum.Div(decimal.NewFromInt(int64(len(s.values))))
+}
@@ -0,0 +1,31 @@
+package indicator
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+	"math"
+)
+
+//ZScore calculates number of population standard deviations between the value and average of values.
+//Returns zero when all values are equal
+func ZScore(values []decimal.Decimal, value decimal.Decimal) (decimal.Decimal, error) {
+	if len(values) == 0 {
+		return decimal.Zero, errors.NewUnexpectedError("requested z-score calc on empty values")
+	}
+	num := decimal.NewFromInt(int64(len(values)))
+	sum := decimal.Zero
+	for _, val := range values {
+		sum = sum.Add(val)
+	}
+	avr := sum.Div(num)
+	sqSum := decimal.Zero
+	for _, val := range values {
+		diff := val.Sub(avr)
+		sqSum = sqSum.Add(diff.Mul(diff))
+	}
+	std := decimal.NewFromFloat(math.Sqrt(sqSum.Div(num).InexactFloat64()))
+	if std.IsZero() {
+		return decimal.Zero, nil
+	}
+	return value.Sub(avr).Div(std), nil
+}
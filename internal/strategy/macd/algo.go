@@ -0,0 +1,67 @@
+package macd
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"time"
+)
+
+const strategyName = "macd"
+
+//NewProd constructs new MACD algorithm using production data processor
+func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newMacd(algo, algRep, logger, newProdDataProc(algo, infoSrv, logger), session.NewApiSchedule(infoSrv, logger), false)
+}
+
+//NewSandbox constructs new MACD algorithm using production data processor cause it the same for such algorithm
+func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newMacd(algo, algRep, logger, newProdDataProc(algo, infoSrv, logger), session.NewApiSchedule(infoSrv, logger), false)
+}
+
+//NewHist constructs new MACD algorithm using history data processor and providing simulation clock to trader
+func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	logger := stbase.HistLogger(rootLogger)
+	return newMacd(algo, nil, logger, candle.NewHistDataProc(algo, hRep, logger), session.StaticSchedule{}, true)
+}
+
+//NewParamSplitter creates splitter varying all periods, only combinations with fast period lower than slow one are used
+func NewParamSplitter(logger *zap.SugaredLogger) stmodel.ParamSplitter {
+	return stbase.NewRangeSplitter(ParamSpecs, []string{FastPeriod, SlowPeriod, SignalPeriod}, periodsFilter, logger)
+}
+
+func periodsFilter(values map[string]decimal.Decimal) bool {
+	return values[FastPeriod].LessThan(values[SlowPeriod])
+}
+
+//newProdDataProc creates market stream processor prefetching history enough for EMA warm-up -
+//three slow periods plus signal period
+func newProdDataProc(algo *entity.Algorithm, infoSrv service.InfoSrv, logger *zap.SugaredLogger) candle.DataProc {
+	return candle.NewProdDataProc(algo, infoSrv, Prefetch(entity.ParamsToMap(algo.Params)), logger)
+}
+
+func newMacd(algo *entity.Algorithm, algRep repository.AlgoRepository, logger *zap.SugaredLogger,
+	proc candle.DataProc, schedule session.Schedule, simClock bool) (stmodel.Algorithm, error) {
+	gen := newSignalGen(entity.ParamsToMap(algo.Params), logger)
+	return stbase.NewSignalAlgorithm(strategyName, algo, algRep, proc, gen, schedule, simClock, logger)
+}
+
+//NewSignalGen creates MACD signal generator, allows to combine MACD signals with signals of other strategies
+func NewSignalGen(paramMap map[string]string, logger *zap.SugaredLogger) stbase.SignalGen {
+	return newSignalGen(paramMap, logger)
+}
+
+//Prefetch returns duration of history required to calculate MACD immediately after start
+func Prefetch(paramMap map[string]string) time.Duration {
+	slow := stbase.GetOrDefaultInt(paramMap, SlowPeriod, 26)
+	signal := stbase.GetOrDefaultInt(paramMap, SignalPeriod, 9)
+	return time.Duration(3*slow+signal) * time.Minute
+}
@@ -0,0 +1,27 @@
+package macd
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+)
+
+//MACD periods in 1-minute candles
+const (
+	FastPeriod   string = "fast_period"   //Fast EMA period
+	SlowPeriod   string = "slow_period"   //Slow EMA period
+	SignalPeriod string = "signal_period" //Signal line EMA period
+)
+
+//Description is a human-readable description of the strategy
+const Description = "MACD crossover: buys when MACD line crosses signal line upwards " +
+	"and sells when it crosses downwards not cheaper than buy price plus commissions"
+
+//ParamSpecs describes parameters accepted by the MACD strategy
+var ParamSpecs = stbase.WithParamSpecs(
+	stmodel.ParamSpec{Name: FastPeriod, Type: stmodel.IntParam, Description: "Fast EMA period in 1-minute candles",
+		Default: "12", Min: stmodel.DecLimit(1)},
+	stmodel.ParamSpec{Name: SlowPeriod, Type: stmodel.IntParam, Description: "Slow EMA period in 1-minute candles",
+		Default: "26", Min: stmodel.DecLimit(2)},
+	stmodel.ParamSpec{Name: SignalPeriod, Type: stmodel.IntParam, Description: "Signal line EMA period in 1-minute candles",
+		Default: "9", Min: stmodel.DecLimit(1)},
+)
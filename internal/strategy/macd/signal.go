@@ -0,0 +1,56 @@
+package macd
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/indicator"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+//signalGen calculates MACD by close prices and signals when histogram changes its sign
+type signalGen struct {
+	fast     int
+	slow     int
+	signal   int
+	macd     map[string]*indicator.MACD //MACD calculator by figi
+	prevHist map[string]decimal.Decimal //Histogram calculated on previous candle update by figi
+	logger   *zap.SugaredLogger
+}
+
+func (s *signalGen) Process(c *candle.Candle) stbase.Signal {
+	macd, ok := s.macd[c.Figi]
+	if !ok {
+		macd = indicator.NewMACD(s.fast, s.slow, s.signal)
+		s.macd[c.Figi] = macd
+	}
+	value := macd.Add(c.Time, c.Close)
+	if !macd.IsReady() {
+		return stbase.HoldSignal()
+	}
+	prev, prevExists := s.prevHist[c.Figi]
+	s.prevHist[c.Figi] = value.Histogram
+	s.logger.Debugf("MACD of %s: %+v, prev histogram: %s, price: %s", c.Figi, value, prev, c.Close)
+	if !prevExists {
+		return stbase.HoldSignal()
+	}
+	switch {
+	case !prev.IsPositive() && value.Histogram.IsPositive():
+		return stbase.Signal{Type: stbase.BuySignal, Info: fmt.Sprintf("MACD %s crossed signal line %s upwards", value.MACD, value.Signal)}
+	case !prev.IsNegative() && value.Histogram.IsNegative():
+		return stbase.Signal{Type: stbase.SellSignal, Info: fmt.Sprintf("MACD %s crossed signal line %s downwards", value.MACD, value.Signal)}
+	}
+	return stbase.HoldSignal()
+}
+
+func newSignalGen(paramMap map[string]string, logger *zap.SugaredLogger) *signalGen {
+	return &signalGen{
+		fast:     stbase.GetOrDefaultInt(paramMap, FastPeriod, 12),
+		slow:     stbase.GetOrDefaultInt(paramMap, SlowPeriod, 26),
+		signal:   stbase.GetOrDefaultInt(paramMap, SignalPeriod, 9),
+		macd:     make(map[string]*indicator.MACD),
+		prevHist: make(map[string]decimal.Decimal),
+		logger:   logger,
+	}
+}
@@ -0,0 +1,39 @@
+package macd
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestSignalGen_crossings(t *testing.T) {
+	gen := newSignalGen(map[string]string{FastPeriod: "1", SlowPeriod: "3", SignalPeriod: "2"}, zap.NewExample().Sugar())
+	start := time.Now()
+	closes := []int64{10, 10, 10, 12, 14, 10}
+	signals := make([]stbase.SignalType, 0, len(closes))
+	for i, price := range closes {
+		c := candle.Candle{Figi: "figi", Time: start.Add(time.Duration(i) * time.Minute), Close: decimal.NewFromInt(price)}
+		signals = append(signals, gen.Process(&c).Type)
+	}
+	expected := []stbase.SignalType{stbase.Hold, stbase.Hold, stbase.Hold, stbase.BuySignal, stbase.Hold, stbase.SellSignal}
+	assert.Equal(t, expected, signals)
+}
+
+func TestParamSplitter_fastLowerThanSlow(t *testing.T) {
+	split, err := NewParamSplitter(zap.NewExample().Sugar()).ParseAndSplit(map[string]string{
+		FastPeriod: "10:5:30",
+		SlowPeriod: "20:5:30",
+	})
+	assert.Nil(t, err)
+	for _, param := range split {
+		assert.Equal(t, "9", param[SignalPeriod], "Default signal period expected")
+		fast, _ := decimal.NewFromString(param[FastPeriod])
+		slow, _ := decimal.NewFromString(param[SlowPeriod])
+		assert.True(t, fast.LessThan(slow))
+	}
+	assert.Equal(t, 9, len(split))
+}
@@ -0,0 +1,351 @@
+package pairs
+
+import (
+	"context"
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"github.com/tevino/abool/v2"
+	"go.uber.org/zap"
+	"time"
+)
+
+//leg keeps state of single instrument of the pair
+type leg struct {
+	figi   string
+	amount int64          //Amount of held lots, negative for short position
+	action *entity.Action //Order in progress, nil when no order posted
+}
+
+//AlgorithmImpl is pairs trading algorithm.
+//Unlike other algorithms it reasons about two instruments jointly: spread of their prices from the common candle stream
+//decides which leg is held, and transitions request coordinated orders on both legs.
+//Algorithm trades only own instruments, so the expensive leg is sold only when it's held,
+//unless short selling is enabled - then the expensive leg is sold short on position transition
+type AlgorithmImpl struct {
+	id        uint                     //Algorithm id extracted for more convenience
+	isActive  *abool.AtomicBool        //Atomic bool indicating is algorithm active
+	dataProc  candle.DataProc          //Data processor - provides candles as the channel for algorithm
+	accountId string                   //Account id extracted for more convenience
+	limits    []*entity.MoneyLimit     //Limits of money available for algorithm
+	algorithm *entity.Algorithm        //Link to original object which algorithm based
+	param     map[string]string        //Map of algorithm configuration parameters
+	spread    *spreadCalc              //Spread z-score calculator
+	legs      [2]*leg                  //Legs of the pair in order of algorithm figis
+	pos       position                 //Current position of the pair
+	ordExp    time.Duration            //Expiration duration of posted orders
+	short     bool                     //Is short selling of the expensive leg enabled
+	aChan     chan *stmodel.ActionReq  //Channel to send order requests to trader
+	arChan    chan *stmodel.ActionResp //Channel to receive responses from trader about action result
+	algRep    repository.AlgoRepository
+	clock     *stbase.SimClock //Simulation clock of history trader, disabled in production
+	ctx       context.Context
+	cancelF   context.CancelFunc
+
+	logger *zap.SugaredLogger
+}
+
+func (a *AlgorithmImpl) Subscribe() (*stmodel.Subscription, error) {
+	if a.aChan != nil || a.arChan != nil {
+		return nil, errors.NewDoubleSubErr("Pairs algorithm multiple subscription not implemented")
+	}
+	a.aChan = make(chan *stmodel.ActionReq, len(a.legs)) //both legs may have order in progress
+	a.arChan = make(chan *stmodel.ActionResp, 1)         //must not block trader, so size = 1
+	sub := &stmodel.Subscription{AlgoID: a.id, AChan: a.aChan, RChan: a.arChan}
+	a.clock.Subscribe(sub)
+	return sub, nil
+}
+
+func (a *AlgorithmImpl) IsActive() bool {
+	return a.isActive.IsSet()
+}
+
+func (a *AlgorithmImpl) Go(parCtx context.Context) error {
+	a.ctx, a.cancelF = context.WithCancel(parCtx)
+	ch, err := a.dataProc.GetDataStream()
+	if err != nil {
+		return err
+	}
+	go a.procBg(ch)
+	err = a.dataProc.Go(a.ctx)
+	if err != nil {
+		a.logger.Error("Error while starting data processor: ", err)
+		a.stopInternal()
+		return errors.NewUnexpectedError("Error while starting data processor " + err.Error())
+	}
+	a.isActive.Set()
+	return nil
+}
+
+func (a *AlgorithmImpl) procBg(datCh <-chan candle.Candle) {
+	defer func() {
+		a.isActive.UnSet()
+		close(a.aChan)
+		a.clock.Close(a.arChan)
+		a.logger.Infof("Stopping algorithm background; ID: %d", a.id)
+	}()
+	a.logger.Infof("Starting background algorithm processing; id: %d , strategy: pairs , limits: %+v", a.id, a.limits)
+	for {
+		select {
+		case resp, ok := <-a.arChan:
+			if !ok {
+				a.logger.Warn("Trader closed response channel, stopping algorithm...")
+				return
+			}
+			a.processTraderResp(resp)
+		case cDat, ok := <-datCh:
+			if !ok {
+				a.logger.Infof("Closed data processor stream, stopping algorithm...")
+				return
+			}
+			a.processCandle(&cDat)
+			if !a.clock.Send(a.ctx, cDat.RetrievedAt, a.arChan, a.processTraderResp) {
+				return
+			}
+		case <-a.ctx.Done():
+			a.logger.Info("Context canceled, stopping...")
+			return
+		}
+	}
+}
+
+//processCandle recalculates spread z-score and moves the pair to target position when no leg order is in progress
+func (a *AlgorithmImpl) processCandle(cDat *candle.Candle) {
+	z, ok := a.spread.Process(cDat)
+	if !ok || cDat.Prefetched {
+		return
+	}
+	if a.legs[0].action != nil || a.legs[1].action != nil {
+		a.logger.Debug("Orders of pair legs in progress, waiting...")
+		return
+	}
+	target := a.spread.Target(a.pos, z)
+	transition := target != a.pos
+	if transition {
+		a.logger.Infof("Spread z-score %s, changing position %d -> %d", z, a.pos, target)
+		a.pos = target
+	}
+	a.rebalance(cDat.RetrievedAt, transition)
+}
+
+//rebalance sells legs which must not be held in current position and buys the held one on position transition.
+//Short legs are covered when they must be held or position is flat, the not held leg is sold short on transition if enabled.
+//Failed sells and covers are repeated with the next candle, failed buys and short sells wait for the next transition
+func (a *AlgorithmImpl) rebalance(tm time.Time, transition bool) {
+	for i, lg := range a.legs {
+		price := a.spread.prices[i]
+		switch {
+		case a.isHeld(i):
+			if lg.amount < 0 {
+				a.request(lg, entity.Buy, price, tm)
+			} else if transition && lg.amount == 0 {
+				a.request(lg, entity.Buy, price, tm)
+			}
+		case lg.amount > 0:
+			a.request(lg, entity.Sell, price, tm)
+		case lg.amount < 0 && a.pos == flat:
+			a.request(lg, entity.Buy, price, tm)
+		case a.short && transition && lg.amount == 0 && a.pos != flat:
+			a.request(lg, entity.Sell, price, tm)
+		}
+	}
+}
+
+//isHeld returns true if leg with index must be held in current position
+func (a *AlgorithmImpl) isHeld(idx int) bool {
+	return (a.pos == longFirst && idx == 0) || (a.pos == longSecond && idx == 1)
+}
+
+func (a *AlgorithmImpl) request(lg *leg, direction entity.ActionDirection, price decimal.Decimal, tm time.Time) {
+	action := entity.Action{
+		AlgorithmID:    a.id,
+		Direction:      direction,
+		InstrFigi:      lg.figi,
+		ReqPrice:       price,
+		ExpirationTime: tm.Add(a.ordExp),
+		Status:         entity.Created,
+		OrderType:      entity.Limited,
+		RetrievedAt:    tm,
+		AccountID:      a.accountId,
+	}
+	switch {
+	case direction == entity.Sell && lg.amount > 0:
+		action.LotAmount = lg.amount
+	case direction == entity.Sell:
+		//Amount of short sell is defined by trader using money limit
+		action.Short = true
+	case lg.amount < 0:
+		//Buy to cover short position
+		action.LotAmount = -lg.amount
+	}
+	lg.action = &action
+	a.logger.Infof("Requesting pair leg action: %+v", action)
+	a.aChan <- &stmodel.ActionReq{Action: &action, Limits: a.limits}
+}
+
+//process response from trade.Trader after requested passed trading stages
+func (a *AlgorithmImpl) processTraderResp(resp *stmodel.ActionResp) {
+	action := resp.Action
+	a.logger.Debug("Processing trader response: ", *action)
+	lg := a.findLeg(action.InstrFigi)
+	if lg == nil {
+		a.logger.Warnf("Received response of figi %s not presented in pair", action.InstrFigi)
+		return
+	}
+	lg.action = nil
+	//Canceled order may be partially filled - leg is updated by really executed lots
+	executed := action.ExecutedAmount()
+	if executed == 0 {
+		a.logger.Infof("Operation failed %+v", resp)
+		return
+	}
+	if action.Status != entity.Success {
+		a.logger.Infof("Order partially executed: %s", action.Info)
+	}
+	lg.amount += executed
+	a.logger.Infof("Order completed: %+v, leg lots: %d", action, lg.amount)
+	a.updateState()
+	stbase.PersistState(a.algRep, a.algorithm, action, a.logger)
+}
+
+func (a *AlgorithmImpl) findLeg(figi string) *leg {
+	for _, lg := range a.legs {
+		if lg.figi == figi {
+			return lg
+		}
+	}
+	return nil
+}
+
+//updateState updates algorithm context parameters with amount of instruments
+func (a *AlgorithmImpl) updateState() {
+	instrAmount := make(map[string]int64, len(a.legs))
+	for _, lg := range a.legs {
+		instrAmount[lg.figi] = lg.amount
+	}
+	if err := stbase.SetInstrumentsState(a.algorithm, instrAmount, nil); err != nil {
+		a.logger.Errorf("Error while marshalling state of algorithm %d: %s", a.id, err)
+	}
+}
+
+func (a *AlgorithmImpl) Stop() error {
+	if a.isActive.IsNotSet() {
+		a.logger.Info("Algorithm already stopped, do nothing...")
+		return nil
+	}
+	a.stopInternal()
+	return nil
+}
+
+func (a *AlgorithmImpl) stopInternal() {
+	a.cancelF()
+	a.logger.Infof("Algorithm %d successfully stopped", a.id)
+}
+
+//Configure restores amounts of legs and posted orders, position is defined by held leg
+func (a *AlgorithmImpl) Configure(ctx []*entity.CtxParam) error {
+	instrAmount, _, err := stbase.GetInstrumentsState(entity.ContextToMap(ctx))
+	if err != nil {
+		a.logger.Warnf("Unable restore instruments state of algorithm %d", a.id)
+		return err
+	}
+	for _, lg := range a.legs {
+		lg.amount = instrAmount[lg.figi]
+	}
+	switch {
+	case a.legs[0].amount > 0, a.legs[1].amount < 0:
+		a.pos = longFirst
+	case a.legs[1].amount > 0, a.legs[0].amount < 0:
+		a.pos = longSecond
+	}
+	for _, action := range a.algorithm.Actions {
+		if lg := a.findLeg(action.InstrFigi); lg != nil && action.Status == entity.Posted {
+			lg.action = action
+		}
+	}
+	return nil
+}
+
+func (a *AlgorithmImpl) GetParam() map[string]string {
+	return a.param
+}
+
+func (a *AlgorithmImpl) GetAlgorithm() *entity.Algorithm {
+	return a.algorithm
+}
+
+//NewProd constructs new pairs algorithm using production data processor
+func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	prefetch := time.Duration(stbase.GetOrDefaultInt(entity.ParamsToMap(algo.Params), Window, 60)) * time.Minute
+	return newPairs(algo, algRep, logger, candle.NewProdDataProc(algo, infoSrv, prefetch, logger), false)
+}
+
+//NewSandbox constructs new pairs algorithm using production data processor cause it the same for such algorithm
+func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return NewProd(algo, infoSrv, algRep, logger)
+}
+
+//NewHist constructs new pairs algorithm using history data processor and providing simulation clock to trader
+func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	logger := stbase.HistLogger(rootLogger)
+	return newPairs(algo, nil, logger, candle.NewHistDataProc(algo, hRep, logger), true)
+}
+
+//NewParamSplitter creates splitter varying window and z-score thresholds, only combinations with exit below entry are used
+func NewParamSplitter(logger *zap.SugaredLogger) stmodel.ParamSplitter {
+	return stbase.NewRangeSplitter(ParamSpecs, []string{Window, EntryZ, ExitZ}, thresholdsFilter, logger)
+}
+
+func thresholdsFilter(values map[string]decimal.Decimal) bool {
+	return values[ExitZ].LessThan(values[EntryZ])
+}
+
+func newPairs(algo *entity.Algorithm, algRep repository.AlgoRepository, logger *zap.SugaredLogger,
+	proc candle.DataProc, simClock bool) (stmodel.Algorithm, error) {
+	if len(algo.Figis) != 2 || algo.Figis[0] == algo.Figis[1] {
+		return nil, errors.NewValidationErr("Pairs strategy requires exactly 2 different instruments")
+	}
+	paramMap := entity.ParamsToMap(algo.Params)
+	entry := stbase.GetOrDefaultDecimal(paramMap, EntryZ, decimal.NewFromInt(2))
+	exit := stbase.GetOrDefaultDecimal(paramMap, ExitZ, decimal.NewFromFloat(0.5))
+	if !exit.LessThan(entry) {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be less than '%s'", ExitZ, EntryZ))
+	}
+	spreadType, ok := paramMap[SpreadType]
+	if !ok {
+		spreadType = RatioSpread
+	}
+	if spreadType != RatioSpread && spreadType != DiffSpread {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be '%s' or '%s'", SpreadType, RatioSpread, DiffSpread))
+	}
+	figis := [2]string{algo.Figis[0], algo.Figis[1]}
+	algorithm := &AlgorithmImpl{
+		id:        algo.ID,
+		isActive:  abool.NewBool(true),
+		dataProc:  proc,
+		accountId: algo.AccountId,
+		limits:    algo.MoneyLimits,
+		algorithm: algo,
+		param:     paramMap,
+		spread:    newSpreadCalc(figis, stbase.GetOrDefaultInt(paramMap, Window, 60), spreadType == RatioSpread, entry, exit),
+		legs:      [2]*leg{{figi: figis[0]}, {figi: figis[1]}},
+		ordExp:    time.Duration(stbase.GetOrDefaultInt(paramMap, stbase.OrderExpiration, 300)) * time.Second,
+		short:     stbase.GetOrDefaultBool(paramMap, stbase.ShortEnabled, false),
+		algRep:    algRep,
+		clock:     stbase.NewSimClock(simClock, logger),
+		logger:    logger,
+	}
+	if err := algorithm.Configure(algo.CtxParams); err != nil {
+		logger.Errorf("Failed configure algorithm %d with configuration %+v", algo.ID, algo.CtxParams)
+		return nil, err
+	}
+	return algorithm, nil
+}
@@ -0,0 +1,150 @@
+package pairs
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	mock_repository "github.com/ldmi3i/tinkoff-invest-bot/internal/mocks/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestSpreadCalc_target(t *testing.T) {
+	calc := newSpreadCalc([2]string{"a", "b"}, 3, false, decimal.NewFromInt(1), decimal.NewFromFloat(0.5))
+	start := time.Now()
+	_, ok := calc.Process(&candle.Candle{Figi: "a", Time: start, Close: decimal.NewFromInt(10)})
+	assert.False(t, ok, "Spread is unknown until both legs have price")
+	for i, price := range []int64{10, 10, 12} {
+		tm := start.Add(time.Duration(i) * time.Minute)
+		calc.Process(&candle.Candle{Figi: "a", Time: tm, Close: decimal.NewFromInt(price)})
+		_, ok = calc.Process(&candle.Candle{Figi: "b", Time: tm, Close: decimal.NewFromInt(10)})
+	}
+	assert.True(t, ok)
+	//Spread values 0, 0, 2 - z-score of the last one is sqrt(2)
+	z, _ := calc.Process(&candle.Candle{Figi: "b", Time: start.Add(2 * time.Minute), Close: decimal.NewFromInt(10)})
+	assert.True(t, z.GreaterThan(decimal.NewFromInt(1)))
+
+	assert.Equal(t, longSecond, calc.Target(flat, z), "First leg is expensive - second must be held")
+	assert.Equal(t, longFirst, calc.Target(flat, z.Neg()))
+	assert.Equal(t, longSecond, calc.Target(longSecond, decimal.NewFromFloat(0.7)), "Position kept between thresholds")
+	assert.Equal(t, flat, calc.Target(longSecond, decimal.NewFromFloat(-0.3)))
+}
+
+func TestPairs_history(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	hist := make([]entity.History, 0)
+	for i, price := range []int64{100, 100, 100, 80, 80, 100} {
+		tm := start.Add(time.Duration(i) * time.Minute)
+		hist = append(hist, entity.History{Figi: "b", Close: decimal.NewFromInt(price), Time: tm})
+		hist = append(hist, entity.History{Figi: "a", Close: decimal.NewFromInt(100), Time: tm})
+	}
+	hRep := mock_repository.NewMockHistoryRepository(ctrl)
+	hRep.EXPECT().FindAllByFigis(gomock.Any()).Return(hist, nil).AnyTimes()
+	logger := zap.NewNop().Sugar()
+
+	algo := &entity.Algorithm{
+		Figis:       []string{"a", "b"},
+		MoneyLimits: []*entity.MoneyLimit{{Currency: "rub", Amount: decimal.NewFromInt(1000)}},
+		Params: []*entity.Param{
+			{Key: Window, Value: "3"},
+			{Key: EntryZ, Value: "1"},
+			{Key: ExitZ, Value: "0.5"},
+		},
+	}
+	alg, err := NewHist(algo, hRep, logger)
+	assert.Nil(t, err)
+	sub, err := alg.Subscribe()
+	assert.Nil(t, err)
+	trader := trade.NewMockTrader(hRep, map[string]int64{"a": 1, "b": 1}, map[string]string{"a": "rub", "b": "rub"}, nil, logger)
+	assert.Nil(t, trader.AddSubscription(sub))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	trader.Go(ctx)
+	assert.Nil(t, alg.Go(ctx))
+	stat := <-trader.GetStatCh()
+
+	//Cheap second leg bought by 80, when ratio falls below average - sold by 100 and the first leg bought
+	assert.Equal(t, uint(2), stat.BuyOpNum)
+	assert.Equal(t, uint(1), stat.SellOpNum)
+	//12 lots of b bought by 80 and sold by 100
+	assert.True(t, stat.CurBalance["rub"].Equal(decimal.NewFromInt(240)), "expected 240, got %s", stat.CurBalance["rub"])
+}
+
+func TestPairs_historyShort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	hist := make([]entity.History, 0)
+	for i, price := range []int64{100, 100, 100, 80, 80, 100} {
+		tm := start.Add(time.Duration(i) * time.Minute)
+		hist = append(hist, entity.History{Figi: "b", Close: decimal.NewFromInt(price), Time: tm})
+		hist = append(hist, entity.History{Figi: "a", Close: decimal.NewFromInt(100), Time: tm})
+	}
+	hRep := mock_repository.NewMockHistoryRepository(ctrl)
+	hRep.EXPECT().FindAllByFigis(gomock.Any()).Return(hist, nil).AnyTimes()
+	logger := zap.NewNop().Sugar()
+
+	algo := &entity.Algorithm{
+		Figis:       []string{"a", "b"},
+		MoneyLimits: []*entity.MoneyLimit{{Currency: "rub", Amount: decimal.NewFromInt(1000)}},
+		Params: []*entity.Param{
+			{Key: Window, Value: "3"},
+			{Key: EntryZ, Value: "1"},
+			{Key: ExitZ, Value: "0.5"},
+			{Key: stbase.ShortEnabled, Value: "true"},
+		},
+	}
+	alg, err := NewHist(algo, hRep, logger)
+	assert.Nil(t, err)
+	sub, err := alg.Subscribe()
+	assert.Nil(t, err)
+	trader := trade.NewMockTrader(hRep, map[string]int64{"a": 1, "b": 1}, map[string]string{"a": "rub", "b": "rub"},
+		map[string]bool{"a": true}, logger)
+	assert.Nil(t, trader.AddSubscription(sub))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	trader.Go(ctx)
+	assert.Nil(t, alg.Go(ctx))
+	stat := <-trader.GetStatCh()
+
+	//Second leg bought and the first sold short by 100, then b sold and a covered by the same price
+	assert.Equal(t, uint(2), stat.BuyOpNum)
+	assert.Equal(t, uint(2), stat.SellOpNum)
+	assert.True(t, stat.CurBalance["rub"].Equal(decimal.NewFromInt(240)), "expected 240, got %s", stat.CurBalance["rub"])
+}
+
+func TestNewHist_validation(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	_, err := NewHist(&entity.Algorithm{Figis: []string{"a"}}, nil, logger)
+	assert.NotNil(t, err)
+	_, err = NewHist(&entity.Algorithm{Figis: []string{"a", "b"}, Params: []*entity.Param{{Key: ExitZ, Value: "3"}}}, nil, logger)
+	assert.NotNil(t, err)
+}
+
+func TestProcessTraderResp_partialFill(t *testing.T) {
+	alg, err := NewHist(&entity.Algorithm{Figis: []string{"a", "b"}}, nil, zap.NewNop().Sugar())
+	assert.Nil(t, err)
+	a := alg.(*AlgorithmImpl)
+
+	//Expired buy canceled after 3 of 10 lots executed - leg holds executed lots
+	buy := &entity.Action{InstrFigi: "b", Direction: entity.Buy, Status: entity.Canceled, LotAmount: 10, LotsExecuted: 3}
+	a.processTraderResp(&stmodel.ActionResp{Action: buy})
+	assert.Equal(t, int64(3), a.findLeg("b").amount)
+
+	//Sell canceled after 1 of 3 lots executed - rest of lots is still held
+	sell := &entity.Action{InstrFigi: "b", Direction: entity.Sell, Status: entity.Canceled, LotAmount: 3, LotsExecuted: 1}
+	a.processTraderResp(&stmodel.ActionResp{Action: sell})
+	assert.Equal(t, int64(2), a.findLeg("b").amount)
+
+	//Short sell canceled after 2 lots executed opens short position of the first leg
+	short := &entity.Action{InstrFigi: "a", Direction: entity.Sell, Short: true, Status: entity.Canceled, LotAmount: 4, LotsExecuted: 2}
+	a.processTraderResp(&stmodel.ActionResp{Action: short})
+	assert.Equal(t, int64(-2), a.findLeg("a").amount)
+}
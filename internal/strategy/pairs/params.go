@@ -0,0 +1,40 @@
+package pairs
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+)
+
+//Pairs parameters
+const (
+	Window     string = "window"      //Number of 1-minute candles in rolling z-score window
+	EntryZ     string = "entry_z"     //Absolute z-score to cross to open position
+	ExitZ      string = "exit_z"      //Absolute z-score to revert below to close position
+	SpreadType string = "spread_type" //Relation of pair prices - ratio or difference
+)
+
+//Spread types
+const (
+	RatioSpread string = "ratio" //Price of the first instrument divided by price of the second one
+	DiffSpread  string = "diff"  //Price of the first instrument minus price of the second one
+)
+
+//Description is a human-readable description of the strategy
+const Description = "Pairs trading of two correlated instruments: tracks rolling z-score of price ratio or difference, " +
+	"buys the cheap leg and sells the expensive one when z-score crosses entry threshold and closes both when it reverts"
+
+//ParamSpecs describes parameters accepted by the pairs strategy
+var ParamSpecs = []stmodel.ParamSpec{
+	{Name: Window, Type: stmodel.IntParam, Description: "Number of 1-minute candles in rolling z-score window",
+		Default: "60", Min: stmodel.DecLimit(2)},
+	{Name: EntryZ, Type: stmodel.DecimalParam, Description: "Absolute z-score of spread to cross to open position",
+		Default: "2", Min: stmodel.DecLimit(0)},
+	{Name: ExitZ, Type: stmodel.DecimalParam, Description: "Absolute z-score of spread to revert below to close position, must be less than entry",
+		Default: "0.5", Min: stmodel.DecLimit(0)},
+	{Name: SpreadType, Type: stmodel.StringParam, Description: "Relation of prices of the first and the second instrument: 'ratio' or 'diff'",
+		Default: RatioSpread},
+	{Name: stbase.OrderExpiration, Type: stmodel.IntParam, Description: "Limited order expiration time in seconds",
+		Default: "300", Min: stmodel.DecLimit(1)},
+	{Name: stbase.ShortEnabled, Type: stmodel.BoolParam, Description: "Sell the expensive leg short when it's not held, if instrument and account support it",
+		Default: "false"},
+}
@@ -0,0 +1,78 @@
+package pairs
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/indicator"
+	"github.com/shopspring/decimal"
+)
+
+//position of the pair - which leg is held
+type position int
+
+const (
+	flat       position = iota //No leg held
+	longFirst                  //First leg is cheap and held, second one sold
+	longSecond                 //Second leg is cheap and held, first one sold
+)
+
+//spreadCalc tracks spread of pair prices from common candle stream and calculates its rolling z-score
+type spreadCalc struct {
+	figis  [2]string
+	prices [2]decimal.Decimal //Last close prices of legs, zero until first candle
+	ratio  bool               //If true - spread is ratio of prices, else - difference
+	series *indicator.Series  //Spread values, one per candle time
+	entry  decimal.Decimal
+	exit   decimal.Decimal
+}
+
+//Process updates price of candle leg and returns z-score of current spread; false when z-score is not ready yet
+func (s *spreadCalc) Process(c *candle.Candle) (decimal.Decimal, bool) {
+	switch c.Figi {
+	case s.figis[0]:
+		s.prices[0] = c.Close
+	case s.figis[1]:
+		s.prices[1] = c.Close
+	default:
+		return decimal.Zero, false
+	}
+	if s.prices[0].IsZero() || s.prices[1].IsZero() {
+		return decimal.Zero, false
+	}
+	spread := s.prices[0].Sub(s.prices[1])
+	if s.ratio {
+		spread = s.prices[0].Div(s.prices[1])
+	}
+	s.series.Add(c.Time, spread)
+	if !s.series.IsFull() {
+		return decimal.Zero, false
+	}
+	z, err := indicator.ZScore(s.series.Values(), spread)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return z, true
+}
+
+//Target returns position to hold by z-score: expensive first leg (high spread) makes second leg cheap and vice versa.
+//Position is kept while z-score is between exit and entry thresholds
+func (s *spreadCalc) Target(curr position, z decimal.Decimal) position {
+	switch {
+	case z.GreaterThan(s.entry):
+		return longSecond
+	case z.LessThan(s.entry.Neg()):
+		return longFirst
+	case z.Abs().LessThan(s.exit):
+		return flat
+	}
+	return curr
+}
+
+func newSpreadCalc(figis [2]string, window int, ratio bool, entry decimal.Decimal, exit decimal.Decimal) *spreadCalc {
+	return &spreadCalc{
+		figis:  figis,
+		ratio:  ratio,
+		series: indicator.NewSeries(window),
+		entry:  entry,
+		exit:   exit,
+	}
+}
@@ -0,0 +1,144 @@
+package strategy
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/sizing"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/fill"
+	"go.uber.org/zap"
+	"sort"
+)
+
+//AlgProdFunc represents common production algorithm factory method
+type AlgProdFunc func(req *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error)
+
+//AlgSandboxFunc represents common sandbox algorithm factory method
+type AlgSandboxFunc func(req *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error)
+
+//AlgHistFunc represents common historical algorithm factory method
+type AlgHistFunc func(req *entity.Algorithm, rep repository.HistoryRepository, logger *zap.SugaredLogger) (stmodel.Algorithm, error)
+
+//SplitterFunc represents parameter splitter factory method
+type SplitterFunc func(logger *zap.SugaredLogger) stmodel.ParamSplitter
+
+//StrategyDescriptor describes strategy and provides constructors of strategy algorithms for all environments
+type StrategyDescriptor struct {
+	Description string              //Human-readable strategy description
+	Params      []stmodel.ParamSpec //Parameters accepted by strategy with defaults and limits
+	NewProd     AlgProdFunc         //Production algorithm constructor
+	NewSandbox  AlgSandboxFunc      //Sandbox algorithm constructor
+	NewHist     AlgHistFunc         //History algorithm constructor
+	NewSplitter SplitterFunc        //Optional - parameter splitter constructor, required for range analysis
+}
+
+//sizingParamSpecs describes buy order sizing parameters processed by traders, added to parameters of every strategy
+var sizingParamSpecs = []stmodel.ParamSpec{
+	{Name: sizing.SizingType, Type: stmodel.StringParam, Description: "Buy order sizing: limit (whole limit), lots, money, percent (of limit), equal (limit split by figis) or atr",
+		Default: string(sizing.FullLimit)},
+	{Name: sizing.SizingValue, Type: stmodel.DecimalParam, Description: "Lots number, money amount, percent of limit or percent of limit risked on stop distance for atr sizing",
+		Min: stmodel.DecLimit(0)},
+	{Name: sizing.SizingAtr, Type: stmodel.IntParam, Description: "Number of minute candles to calculate average true range for atr sizing",
+		Default: "14", Min: stmodel.DecLimit(1)},
+	{Name: sizing.SizingAtrMult, Type: stmodel.DecimalParam, Description: "Stop distance in average true ranges for atr sizing",
+		Default: "2", Min: stmodel.DecLimit(0)},
+}
+
+//fillParamSpecs describes order fill simulation parameters processed by history trader, added to parameters of every strategy
+var fillParamSpecs = []stmodel.ParamSpec{
+	{Name: fill.FillType, Type: stmodel.StringParam, Description: "History order fill: instant (by close price) or candle (by following candles with slippage and commission)",
+		Default: string(fill.InstantFill)},
+	{Name: fill.FillSlippage, Type: stmodel.DecimalParam, Description: "Slippage of market order in percents of price for candle fill",
+		Default: "0", Min: stmodel.DecLimit(0)},
+	{Name: fill.Commission, Type: stmodel.DecimalParam, Description: "Commission of single order in percents",
+		Default: fill.DefaultCommission.String(), Min: stmodel.DecLimit(0)},
+}
+
+//Registered strategies by name
+var registry = collections.NewSyncMap[string, *StrategyDescriptor]()
+
+//Register adds strategy to the registry, so it may be requested by name in algorithm requests.
+//Sizing and fill parameters processed by traders are added to strategy parameters, unless strategy declares them itself.
+//Returns error if strategy with the same name already registered or descriptor misses constructors.
+//Registry is internal to the module - strategy is added as package under /internal/strategy and registered in builtin.go
+func Register(name string, desc StrategyDescriptor) error {
+	if name == "" {
+		return errors.NewUnexpectedError("Strategy name must not be empty")
+	}
+	if desc.NewProd == nil || desc.NewSandbox == nil || desc.NewHist == nil {
+		return errors.NewUnexpectedError(fmt.Sprintf("Strategy '%s' must provide prod, sandbox and history constructors", name))
+	}
+	if _, exist := registry.Get(name); exist {
+		return errors.NewUnexpectedError(fmt.Sprintf("Strategy '%s' already registered", name))
+	}
+	params := make([]stmodel.ParamSpec, 0, len(desc.Params)+len(sizingParamSpecs)+len(fillParamSpecs))
+	params = append(append(params, desc.Params...), sizingParamSpecs...)
+	declared := make(map[string]bool)
+	for _, spec := range params {
+		declared[spec.Name] = true
+	}
+	for _, spec := range fillParamSpecs {
+		if !declared[spec.Name] {
+			params = append(params, spec)
+		}
+	}
+	desc.Params = params
+	registry.Put(name, &desc)
+	return nil
+}
+
+//GetStrategies returns registered strategies sorted by name
+func GetStrategies() []*collections.MapEntry[string, *StrategyDescriptor] {
+	res := registry.GetSlice()
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Key < res[j].Key
+	})
+	return res
+}
+
+//Validate populates missing algorithm parameters with strategy defaults and validates them by strategy parameter specs
+func Validate(alg *entity.Algorithm) error {
+	desc, err := getDescriptor(alg.Strategy)
+	if err != nil {
+		return err
+	}
+	paramMap := entity.ParamsToMap(alg.Params)
+	if err = stmodel.ApplyParamSpecs(desc.Params, paramMap); err != nil {
+		return err
+	}
+	if _, err = sizing.FromParams(paramMap, len(alg.Figis)); err != nil {
+		return err
+	}
+	if _, err = fill.FromParams(paramMap); err != nil {
+		return err
+	}
+	//Add defaults to algorithm parameters
+	for key, value := range paramMap {
+		if _, exist := alg.GetParam(key); !exist {
+			alg.Params = append(alg.Params, &entity.Param{AlgorithmID: alg.ID, Key: key, Value: value})
+		}
+	}
+	return nil
+}
+
+func getDescriptor(name string) (*StrategyDescriptor, error) {
+	desc, exist := registry.Get(name)
+	if !exist {
+		return nil, errors.NewNotFound(
+			fmt.Sprintf("Strategy '%s' does not exist - register it with strategy.Register", name),
+		)
+	}
+	return desc, nil
+}
+
+func mustRegister(name string, desc StrategyDescriptor) {
+	if err := Register(name, desc); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,51 @@
+package strategy
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/avr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRegister_duplicateNameRejected(t *testing.T) {
+	err := Register("avr", StrategyDescriptor{NewProd: avr.NewProd, NewSandbox: avr.NewSandbox, NewHist: avr.NewHist})
+	assert.NotNil(t, err)
+}
+
+func TestRegister_constructorsRequired(t *testing.T) {
+	err := Register("no-constructors", StrategyDescriptor{NewProd: avr.NewProd})
+	assert.NotNil(t, err)
+	_, exist := registry.Get("no-constructors")
+	assert.False(t, exist)
+}
+
+func TestValidate_populatesDefaults(t *testing.T) {
+	alg := &entity.Algorithm{
+		Strategy: "avr",
+		Params: []*entity.Param{
+			{Key: avr.ShortDur, Value: "100"},
+			{Key: avr.LongDur, Value: "300"},
+		},
+	}
+	err := Validate(alg)
+	assert.Nil(t, err)
+	param, ok := alg.GetParam(avr.OrderExpiration)
+	assert.True(t, ok)
+	assert.Equal(t, "300", param.Value)
+	_, ok = alg.GetParam(avr.StopLoss)
+	assert.False(t, ok, "Parameter without default must not be populated")
+}
+
+func TestValidate_wrongParams(t *testing.T) {
+	missing := &entity.Algorithm{Strategy: "avr", Params: []*entity.Param{{Key: avr.ShortDur, Value: "100"}}}
+	assert.NotNil(t, Validate(missing))
+
+	wrongType := &entity.Algorithm{Strategy: "avr", Params: []*entity.Param{
+		{Key: avr.ShortDur, Value: "100"},
+		{Key: avr.LongDur, Value: "long"},
+	}}
+	assert.NotNil(t, Validate(wrongType))
+
+	unknown := &entity.Algorithm{Strategy: "unknown"}
+	assert.NotNil(t, Validate(unknown))
+}
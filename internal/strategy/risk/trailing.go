@@ -0,0 +1,132 @@
+package risk
+
+import (
+	"encoding/json"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//TrailingStopField is a name of algorithm context parameter keeping high water marks of trailing stop
+const TrailingStopField string = "trailingStop"
+
+//TrailingStop tracks the highest price of held long positions and signals sell by market when price
+//falls by configured percent from it. Candle low is checked against highs of previous candles, so stop
+//is triggered by history candles the same way as by real stream of prices, even if candle closes above stop
+type TrailingStop struct {
+	rel     decimal.Decimal      //Relative to high water mark price to sell at
+	marks   map[string]*highMark //High water marks of tracked positions by figi
+	changed bool                 //Is persisted part of marks changed since last state serialization
+}
+
+//highMark is high water mark of single position
+type highMark struct {
+	High    decimal.Decimal `json:"high"`   //Highest price of completed candles since position opened
+	Opened  time.Time       `json:"opened"` //Time of position opening - candles started before it don't trigger stop by low price
+	curTime time.Time       //Start time of current candle, it may be updated by stream
+	curHigh decimal.Decimal //Highest price of current candle
+}
+
+//Enabled returns true if trailing stop percent is set
+func (ts *TrailingStop) Enabled() bool {
+	return ts.rel.LessThan(decimal.NewFromInt(1))
+}
+
+//Open starts tracking of position from open price, does nothing when position is already tracked or stop disabled
+func (ts *TrailingStop) Open(figi string, price decimal.Decimal, tm time.Time) {
+	if !ts.Enabled() {
+		return
+	}
+	if _, ok := ts.marks[figi]; ok {
+		return
+	}
+	ts.marks[figi] = &highMark{High: price, Opened: tm}
+	ts.changed = true
+}
+
+//Close stops tracking of closed position
+func (ts *TrailingStop) Close(figi string) {
+	if _, ok := ts.marks[figi]; ok {
+		delete(ts.marks, figi)
+		ts.changed = true
+	}
+}
+
+//IsOpen returns true if position by figi is tracked
+func (ts *TrailingStop) IsOpen(figi string) bool {
+	_, ok := ts.marks[figi]
+	return ok
+}
+
+//Check updates high water mark by candle and returns true if stop is triggered,
+//together with estimated price of market sell execution
+func (ts *TrailingStop) Check(c *candle.Candle) (decimal.Decimal, bool) {
+	mark, ok := ts.marks[c.Figi]
+	if !ok {
+		return decimal.Zero, false
+	}
+	if c.Time.After(mark.curTime) {
+		//New candle started - the previous one completed
+		if mark.curHigh.GreaterThan(mark.High) {
+			mark.High = mark.curHigh
+			ts.changed = true
+		}
+		mark.curTime = c.Time
+		mark.curHigh = decimal.Zero
+	}
+	stop := mark.High.Mul(ts.rel)
+	if c.Time.After(mark.Opened) && c.Low.IsPositive() && c.Low.LessThanOrEqual(stop) {
+		//Price was lower than stop during candle; if candle opened lower - price gapped over stop
+		if c.Open.IsPositive() && c.Open.LessThan(stop) {
+			return c.Open, true
+		}
+		return stop, true
+	}
+	mark.curHigh = decimal.Max(mark.curHigh, c.High, c.Close)
+	if c.Close.LessThanOrEqual(decimal.Max(mark.High, mark.curHigh).Mul(ts.rel)) {
+		return c.Close, true
+	}
+	return decimal.Zero, false
+}
+
+//IsChanged returns true if high water marks changed since last state serialization
+func (ts *TrailingStop) IsChanged() bool {
+	return ts.changed
+}
+
+//SetState serializes high water marks to algorithm context parameter
+func (ts *TrailingStop) SetState(algo *entity.Algorithm) error {
+	if !ts.Enabled() {
+		return nil
+	}
+	res, err := json.Marshal(ts.marks)
+	if err != nil {
+		return err
+	}
+	algo.SetCtxParam(TrailingStopField, string(res))
+	ts.changed = false
+	return nil
+}
+
+//Restore deserializes high water marks from algorithm context, does nothing if context has no trailing stop state
+func (ts *TrailingStop) Restore(confCtx map[string]string) error {
+	state, ok := confCtx[TrailingStopField]
+	if !ok || !ts.Enabled() {
+		return nil
+	}
+	marks := make(map[string]*highMark)
+	if err := json.Unmarshal([]byte(state), &marks); err != nil {
+		return err
+	}
+	ts.marks = marks
+	return nil
+}
+
+//NewTrailingStop creates trailing stop selling when price falls by percent from the highest one, zero percent disables it
+func NewTrailingStop(percent decimal.Decimal) *TrailingStop {
+	return &TrailingStop{
+		rel:   decimal.NewFromInt(1).Sub(percent.Div(decimal.NewFromInt(100))),
+		marks: make(map[string]*highMark),
+	}
+}
@@ -0,0 +1,75 @@
+package risk
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func cndl(tm time.Time, open, high, low, close int64) *candle.Candle {
+	return &candle.Candle{Figi: "a", Time: tm, Open: decimal.NewFromInt(open), High: decimal.NewFromInt(high),
+		Low: decimal.NewFromInt(low), Close: decimal.NewFromInt(close)}
+}
+
+func TestTrailingStop_check(t *testing.T) {
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	ts := NewTrailingStop(decimal.NewFromInt(10))
+	_, stopped := ts.Check(cndl(start, 100, 100, 10, 100))
+	assert.False(t, stopped, "Not opened position must not be stopped")
+
+	ts.Open("a", decimal.NewFromInt(100), start)
+	_, stopped = ts.Check(cndl(start, 100, 100, 80, 100))
+	assert.False(t, stopped, "Candle started before opening must not trigger stop by low")
+	_, stopped = ts.Check(cndl(start.Add(time.Minute), 101, 110, 100, 108))
+	assert.False(t, stopped)
+	//The highest price 110 - stop price 99 crossed by low, candle opened above it
+	price, stopped := ts.Check(cndl(start.Add(2*time.Minute), 107, 107, 95, 105))
+	assert.True(t, stopped)
+	assert.True(t, price.Equal(decimal.NewFromInt(99)), "expected 99, got %s", price)
+	//Gap over stop price - executed by open price
+	price, stopped = ts.Check(cndl(start.Add(3*time.Minute), 90, 92, 88, 91))
+	assert.True(t, stopped)
+	assert.True(t, price.Equal(decimal.NewFromInt(90)), "expected 90, got %s", price)
+
+	ts.Close("a")
+	assert.False(t, ts.IsOpen("a"))
+}
+
+func TestTrailingStop_closeOfCurrentCandle(t *testing.T) {
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	ts := NewTrailingStop(decimal.NewFromInt(10))
+	ts.Open("a", decimal.NewFromInt(100), start)
+	//Stream updates of one candle: high grows to 120, then price falls below 108
+	_, stopped := ts.Check(cndl(start.Add(time.Minute), 100, 120, 100, 119))
+	assert.False(t, stopped)
+	price, stopped := ts.Check(cndl(start.Add(time.Minute), 100, 120, 100, 107))
+	assert.True(t, stopped)
+	assert.True(t, price.Equal(decimal.NewFromInt(107)))
+}
+
+func TestTrailingStop_state(t *testing.T) {
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	ts := NewTrailingStop(decimal.NewFromInt(10))
+	ts.Open("a", decimal.NewFromInt(100), start)
+	ts.Check(cndl(start.Add(time.Minute), 100, 120, 110, 115))
+	ts.Check(cndl(start.Add(2*time.Minute), 115, 116, 112, 114))
+	assert.True(t, ts.IsChanged())
+	algo := &entity.Algorithm{}
+	assert.Nil(t, ts.SetState(algo))
+	assert.False(t, ts.IsChanged())
+
+	restored := NewTrailingStop(decimal.NewFromInt(10))
+	assert.Nil(t, restored.Restore(entity.ContextToMap(algo.CtxParams)))
+	assert.True(t, restored.IsOpen("a"))
+	//Restored high water mark is 120 - stop price 108
+	price, stopped := restored.Check(cndl(start.Add(3*time.Minute), 110, 110, 105, 109))
+	assert.True(t, stopped)
+	assert.True(t, price.Equal(decimal.NewFromInt(108)), "expected 108, got %s", price)
+
+	disabled := NewTrailingStop(decimal.Zero)
+	disabled.Open("a", decimal.NewFromInt(100), start)
+	assert.False(t, disabled.IsOpen("a"))
+}
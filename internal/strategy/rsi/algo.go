@@ -0,0 +1,65 @@
+package rsi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"time"
+)
+
+const strategyName = "rsi"
+
+//NewProd constructs new RSI algorithm using production data processor
+func NewProd(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newRsi(algo, algRep, logger, newProdDataProc(algo, infoSrv, logger), session.NewApiSchedule(infoSrv, logger), false)
+}
+
+//NewSandbox constructs new RSI algorithm using production data processor cause it the same for such algorithm
+func NewSandbox(algo *entity.Algorithm, infoSrv service.InfoSrv, algRep repository.AlgoRepository,
+	logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	return newRsi(algo, algRep, logger, newProdDataProc(algo, infoSrv, logger), session.NewApiSchedule(infoSrv, logger), false)
+}
+
+//NewHist constructs new RSI algorithm using history data processor and providing simulation clock to trader
+func NewHist(algo *entity.Algorithm, hRep repository.HistoryRepository, rootLogger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	logger := stbase.HistLogger(rootLogger)
+	return newRsi(algo, nil, logger, candle.NewHistDataProc(algo, hRep, logger), session.StaticSchedule{}, true)
+}
+
+//NewParamSplitter creates splitter varying window and RSI levels, only combinations with oversold below overbought are used
+func NewParamSplitter(logger *zap.SugaredLogger) stmodel.ParamSplitter {
+	return stbase.NewRangeSplitter(ParamSpecs, []string{Window, Oversold, Overbought}, levelsFilter, logger)
+}
+
+func levelsFilter(values map[string]decimal.Decimal) bool {
+	return values[Oversold].LessThan(values[Overbought])
+}
+
+//newProdDataProc creates market stream processor prefetching history enough to calculate RSI immediately
+func newProdDataProc(algo *entity.Algorithm, infoSrv service.InfoSrv, logger *zap.SugaredLogger) candle.DataProc {
+	return candle.NewProdDataProc(algo, infoSrv, Prefetch(entity.ParamsToMap(algo.Params)), logger)
+}
+
+func newRsi(algo *entity.Algorithm, algRep repository.AlgoRepository, logger *zap.SugaredLogger,
+	proc candle.DataProc, schedule session.Schedule, simClock bool) (stmodel.Algorithm, error) {
+	gen := newSignalGen(entity.ParamsToMap(algo.Params), logger)
+	return stbase.NewSignalAlgorithm(strategyName, algo, algRep, proc, gen, schedule, simClock, logger)
+}
+
+//NewSignalGen creates RSI signal generator, allows to combine RSI signals with signals of other strategies
+func NewSignalGen(paramMap map[string]string, logger *zap.SugaredLogger) stbase.SignalGen {
+	return newSignalGen(paramMap, logger)
+}
+
+//Prefetch returns duration of history required to calculate RSI immediately after start
+func Prefetch(paramMap map[string]string) time.Duration {
+	window := stbase.GetOrDefaultInt(paramMap, Window, 14)
+	return time.Duration(window+1) * time.Minute
+}
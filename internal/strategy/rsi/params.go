@@ -0,0 +1,27 @@
+package rsi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+)
+
+//RSI parameters
+const (
+	Window     string = "window"     //Number of 1-minute candles (periods) in RSI window
+	Oversold   string = "oversold"   //RSI level to cross upwards to buy
+	Overbought string = "overbought" //RSI level to cross downwards to sell
+)
+
+//Description is a human-readable description of the strategy
+const Description = "RSI mean reversion: buys when relative strength index crosses oversold level upwards " +
+	"and sells when it crosses overbought level downwards not cheaper than buy price plus commissions"
+
+//ParamSpecs describes parameters accepted by the RSI strategy
+var ParamSpecs = stbase.WithParamSpecs(
+	stmodel.ParamSpec{Name: Window, Type: stmodel.IntParam, Description: "Number of 1-minute candles in RSI window",
+		Default: "14", Min: stmodel.DecLimit(2)},
+	stmodel.ParamSpec{Name: Oversold, Type: stmodel.DecimalParam, Description: "RSI level to cross upwards to buy",
+		Default: "30", Min: stmodel.DecLimit(0), Max: stmodel.DecLimit(100)},
+	stmodel.ParamSpec{Name: Overbought, Type: stmodel.DecimalParam, Description: "RSI level to cross downwards to sell",
+		Default: "70", Min: stmodel.DecLimit(0), Max: stmodel.DecLimit(100)},
+)
@@ -0,0 +1,61 @@
+package rsi
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/indicator"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+//signalGen calculates RSI by close prices of last window candles and signals when it crosses levels
+type signalGen struct {
+	window     int
+	oversold   decimal.Decimal
+	overbought decimal.Decimal
+	closes     map[string]*indicator.Series //Close prices of last window+1 candles by figi
+	prevRsi    map[string]decimal.Decimal   //RSI calculated on previous candle update by figi
+	logger     *zap.SugaredLogger
+}
+
+func (s *signalGen) Process(c *candle.Candle) stbase.Signal {
+	closes, ok := s.closes[c.Figi]
+	if !ok {
+		closes = indicator.NewSeries(s.window + 1)
+		s.closes[c.Figi] = closes
+	}
+	closes.Add(c.Time, c.Close)
+	if !closes.IsFull() {
+		return stbase.HoldSignal()
+	}
+	rsi, err := indicator.RSI(closes.Values())
+	if err != nil {
+		s.logger.Errorf("Error while calculating RSI: %s", err)
+		return stbase.HoldSignal()
+	}
+	prev, prevExists := s.prevRsi[c.Figi]
+	s.prevRsi[c.Figi] = rsi
+	s.logger.Debugf("RSI of %s, current: %s, prev: %s, price: %s", c.Figi, rsi, prev, c.Close)
+	if !prevExists {
+		return stbase.HoldSignal()
+	}
+	switch {
+	case prev.LessThan(s.oversold) && rsi.GreaterThanOrEqual(s.oversold):
+		return stbase.Signal{Type: stbase.BuySignal, Info: fmt.Sprintf("RSI crossed oversold %s upwards: %s -> %s", s.oversold, prev, rsi)}
+	case prev.GreaterThan(s.overbought) && rsi.LessThanOrEqual(s.overbought):
+		return stbase.Signal{Type: stbase.SellSignal, Info: fmt.Sprintf("RSI crossed overbought %s downwards: %s -> %s", s.overbought, prev, rsi)}
+	}
+	return stbase.HoldSignal()
+}
+
+func newSignalGen(paramMap map[string]string, logger *zap.SugaredLogger) *signalGen {
+	return &signalGen{
+		window:     stbase.GetOrDefaultInt(paramMap, Window, 14),
+		oversold:   stbase.GetOrDefaultDecimal(paramMap, Oversold, decimal.NewFromInt(30)),
+		overbought: stbase.GetOrDefaultDecimal(paramMap, Overbought, decimal.NewFromInt(70)),
+		closes:     make(map[string]*indicator.Series),
+		prevRsi:    make(map[string]decimal.Decimal),
+		logger:     logger,
+	}
+}
@@ -0,0 +1,51 @@
+package rsi
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestSignalGen_crossings(t *testing.T) {
+	gen := newSignalGen(map[string]string{Window: "2", Oversold: "30", Overbought: "70"}, zap.NewExample().Sugar())
+	start := time.Now()
+	closes := []int64{
+		10, 9, 8, //RSI 0
+		9,  //RSI 50 - crossed oversold upwards
+		10, //RSI 100
+		11, //RSI 100
+		10, //RSI 50 - crossed overbought downwards
+	}
+	signals := make([]stbase.SignalType, 0, len(closes))
+	for i, price := range closes {
+		c := candle.Candle{Figi: "figi", Time: start.Add(time.Duration(i) * time.Minute), Close: decimal.NewFromInt(price)}
+		signals = append(signals, gen.Process(&c).Type)
+	}
+	expected := []stbase.SignalType{
+		stbase.Hold, stbase.Hold, stbase.Hold,
+		stbase.BuySignal,
+		stbase.Hold, stbase.Hold,
+		stbase.SellSignal,
+	}
+	assert.Equal(t, expected, signals)
+}
+
+func TestParamSplitter_levelsFiltered(t *testing.T) {
+	splitter := NewParamSplitter(zap.NewExample().Sugar())
+	split, err := splitter.ParseAndSplit(map[string]string{
+		Window:     "10:5:20",
+		Oversold:   "40:10:60",
+		Overbought: "50",
+		"any":      "value",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(split), "Only oversold 40 is lower than overbought 50 for 3 windows")
+	for _, param := range split {
+		assert.Equal(t, "40", param[Oversold])
+		assert.Equal(t, "value", param["any"])
+	}
+}
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"math/rand"
+)
+
+//geneticSearch evolves generations of parameter sets: the first one is random, next ones are children of
+//the best half of all analyzed parameter sets made by uniform crossover and mutation of single values
+type geneticSearch struct {
+	space      *Space
+	rnd        *rand.Rand
+	trials     int
+	population int
+	generation int
+	analyzed   []*Result
+	seen       map[string]bool
+}
+
+func (g *geneticSearch) Next() *Batch {
+	size := g.trials - len(g.seen)
+	if size <= 0 {
+		return nil
+	}
+	if size > g.population {
+		size = g.population
+	}
+	var candidates []map[string]string
+	if len(g.analyzed) == 0 {
+		candidates = g.space.sampleUnique(g.rnd, size, g.seen)
+	} else {
+		candidates = g.breed(size)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	batch := &Batch{Candidates: candidates, Fidelity: 1, Stage: g.generation}
+	g.generation++
+	return batch
+}
+
+func (g *geneticSearch) Report(results []*Result) {
+	g.analyzed = append(g.analyzed, results...)
+	sortResults(g.analyzed)
+}
+
+//breed makes up to n new valid children of the best half of analyzed parameter sets
+func (g *geneticSearch) breed(n int) []map[string]string {
+	parents := g.analyzed[:(len(g.analyzed)+1)/2]
+	res := make([]map[string]string, 0, n)
+	for attempt := 0; attempt < n*maxAttempts && len(res) < n; attempt++ {
+		first := parents[g.rnd.Intn(len(parents))].Params
+		second := parents[g.rnd.Intn(len(parents))].Params
+		child := copyParams(g.space.Fixed)
+		for _, dim := range g.space.Dims {
+			switch {
+			case g.rnd.Intn(len(g.space.Dims)) == 0:
+				//Mutation - one value per child on average
+				child[dim.Name] = dim.Values[g.rnd.Intn(len(dim.Values))].String()
+			case g.rnd.Intn(2) == 0:
+				child[dim.Name] = first[dim.Name]
+			default:
+				child[dim.Name] = second[dim.Name]
+			}
+		}
+		if g.space.accept(child, g.seen) {
+			res = append(res, child)
+		}
+	}
+	return res
+}
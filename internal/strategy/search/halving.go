@@ -0,0 +1,67 @@
+package search
+
+import (
+	"math"
+	"math/rand"
+)
+
+//halvingSearch is a successive halving: random parameter sets are analyzed on the short beginning of history,
+//the best 1/eta of them proceed to the next rung analyzed on eta times longer history until the whole history is used
+type halvingSearch struct {
+	space   *Space
+	rnd     *rand.Rand
+	trials  int
+	eta     int
+	rung    int
+	rungs   int                 //Number of rungs after the first one
+	next    []map[string]string //Parameter sets of the next rung
+	started bool
+}
+
+func (h *halvingSearch) Next() *Batch {
+	if !h.started {
+		h.started = true
+		h.next = h.space.sampleUnique(h.rnd, h.firstRungSize(), make(map[string]bool))
+		for size := len(h.next); size > h.eta; size = h.survivors(size) {
+			h.rungs++
+		}
+	}
+	if len(h.next) == 0 || h.rung > h.rungs {
+		return nil
+	}
+	fidelity := 1 / math.Pow(float64(h.eta), float64(h.rungs-h.rung))
+	return &Batch{Candidates: h.next, Fidelity: fidelity, Stage: h.rung}
+}
+
+func (h *halvingSearch) Report(results []*Result) {
+	sortResults(results)
+	results = results[:h.survivors(len(results))]
+	h.next = make([]map[string]string, 0, len(results))
+	for _, res := range results {
+		h.next = append(h.next, res.Params)
+	}
+	h.rung++
+}
+
+//firstRungSize returns the largest number of parameter sets in the first rung which total analysis fits trial budget
+func (h *halvingSearch) firstRungSize() int {
+	size := h.trials
+	for size > 1 && h.total(size) > h.trials {
+		size--
+	}
+	return size
+}
+
+//total returns number of analyzed parameter sets in all rungs
+func (h *halvingSearch) total(size int) int {
+	total := size
+	for size > h.eta {
+		size = h.survivors(size)
+		total += size
+	}
+	return total
+}
+
+func (h *halvingSearch) survivors(size int) int {
+	return (size + h.eta - 1) / h.eta
+}
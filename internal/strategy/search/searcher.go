@@ -0,0 +1,170 @@
+package search
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+//Mode defines how parameter sets are chosen from ranges of varied parameters
+type Mode string
+
+const (
+	GridMode    Mode = "grid"    //All combinations of parameter ranges
+	RandomMode  Mode = "random"  //Random combinations up to trial budget
+	HalvingMode Mode = "halving" //Successive halving - random combinations are analyzed on growing part of history, only the best of them proceed
+	GeneticMode Mode = "genetic" //Generations of combinations made by crossover and mutation of the best analyzed ones
+)
+
+//Defaults of search configuration
+const (
+	DefaultTrials      = 100
+	DefaultEta         = 3
+	DefaultPopulation  = 10
+	DefaultConcurrency = 18
+)
+
+//maxAttempts limits random draws per requested parameter set, draws fail when set is filtered out or already analyzed
+const maxAttempts = 20
+
+//Conf is a search configuration
+type Conf struct {
+	Mode        Mode
+	Trials      int //Budget of analyzed parameter sets, zero means unlimited grid
+	Seed        int64
+	Eta         int
+	Population  int
+	Concurrency int
+}
+
+//FromDto creates search configuration from request populating defaults, grid search is used when request not set
+func FromDto(req *dto.SearchConf) (Conf, error) {
+	conf := Conf{Mode: GridMode, Eta: DefaultEta, Population: DefaultPopulation, Concurrency: DefaultConcurrency,
+		Seed: time.Now().UnixNano()}
+	if req == nil {
+		return conf, nil
+	}
+	if req.Mode != "" {
+		conf.Mode = Mode(req.Mode)
+	}
+	switch conf.Mode {
+	case GridMode, RandomMode, HalvingMode, GeneticMode:
+	default:
+		return conf, errors.NewValidationErr(fmt.Sprintf("Search mode must be one of %s, %s, %s, %s; got '%s'",
+			GridMode, RandomMode, HalvingMode, GeneticMode, req.Mode))
+	}
+	if req.Trials < 0 || req.Eta == 1 || req.Eta < 0 || req.Population == 1 || req.Population < 0 || req.Concurrency < 0 {
+		return conf, errors.NewValidationErr("Search trials and concurrency must be positive, eta and population must be at least 2")
+	}
+	conf.Trials = req.Trials
+	if conf.Trials == 0 && conf.Mode != GridMode {
+		conf.Trials = DefaultTrials
+	}
+	if req.Seed != 0 {
+		conf.Seed = req.Seed
+	}
+	if req.Eta != 0 {
+		conf.Eta = req.Eta
+	}
+	if req.Population != 0 {
+		conf.Population = req.Population
+	}
+	if req.Concurrency != 0 {
+		conf.Concurrency = req.Concurrency
+	}
+	return conf, nil
+}
+
+//Batch is a group of parameter sets analyzed on the same part of history
+type Batch struct {
+	Candidates []map[string]string
+	Fidelity   float64 //Part of history from its start used for analysis, 1 is whole history
+	Stage      int     //Halving rung or genetic generation
+}
+
+//Result is an objective score of analyzed parameter set, not valid result doesn't satisfy objective constraints
+type Result struct {
+	Params map[string]string
+	Score  decimal.Decimal
+	Valid  bool
+}
+
+//Searcher proposes parameter sets in batches and adapts next batches by results of analyzed ones.
+//Searcher is not thread safe, batches are requested and reported sequentially
+type Searcher interface {
+	//Next returns next batch to analyze, nil means search completed
+	Next() *Batch
+	//Report passes results of the last batch, parameter sets failed to analyze are omitted
+	Report(results []*Result)
+}
+
+//New creates searcher of configured mode; grid is a splitter result, it is used by grid mode only
+func New(conf Conf, space *Space, grid []map[string]string) Searcher {
+	rnd := rand.New(rand.NewSource(conf.Seed))
+	switch conf.Mode {
+	case RandomMode:
+		return &randomSearch{space: space, rnd: rnd, trials: conf.Trials}
+	case HalvingMode:
+		return &halvingSearch{space: space, rnd: rnd, trials: conf.Trials, eta: conf.Eta}
+	case GeneticMode:
+		return &geneticSearch{space: space, rnd: rnd, trials: conf.Trials, population: conf.Population,
+			seen: make(map[string]bool)}
+	default:
+		if conf.Trials > 0 && len(grid) > conf.Trials {
+			grid = grid[:conf.Trials]
+		}
+		return &gridSearch{grid: grid}
+	}
+}
+
+//gridSearch analyzes all parameter sets in a single batch
+type gridSearch struct {
+	grid []map[string]string
+	done bool
+}
+
+func (g *gridSearch) Next() *Batch {
+	if g.done || len(g.grid) == 0 {
+		return nil
+	}
+	g.done = true
+	return &Batch{Candidates: g.grid, Fidelity: 1}
+}
+
+func (g *gridSearch) Report([]*Result) {}
+
+//randomSearch analyzes random parameter sets in a single batch
+type randomSearch struct {
+	space  *Space
+	rnd    *rand.Rand
+	trials int
+	done   bool
+}
+
+func (r *randomSearch) Next() *Batch {
+	if r.done {
+		return nil
+	}
+	r.done = true
+	candidates := r.space.sampleUnique(r.rnd, r.trials, make(map[string]bool))
+	if len(candidates) == 0 {
+		return nil
+	}
+	return &Batch{Candidates: candidates, Fidelity: 1}
+}
+
+func (r *randomSearch) Report([]*Result) {}
+
+//sortResults orders results from the best one, results not satisfying objective are placed last
+func sortResults(results []*Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Valid != results[j].Valid {
+			return results[i].Valid
+		}
+		return results[i].Score.GreaterThan(results[j].Score)
+	})
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testSpecs = []stmodel.ParamSpec{
+	{Name: "short", Type: stmodel.IntParam, Min: stmodel.DecLimit(1)},
+	{Name: "long", Type: stmodel.IntParam},
+	{Name: "stop_loss", Type: stmodel.DecimalParam},
+	{Name: "session_start", Type: stmodel.StringParam},
+}
+
+func testSpace(t *testing.T) *Space {
+	space, err := NewSpace(map[string]string{"short": "0:10:90", "long": "10:10:100", "stop_loss": "0.5:0.5:2",
+		"session_start": "10:00", "child.window": "5:2:9"}, testSpecs, func(params map[string]string) bool {
+		short, _ := decimal.NewFromString(params["short"])
+		long, _ := decimal.NewFromString(params["long"])
+		return short.LessThan(long)
+	})
+	assert.Nil(t, err)
+	return space
+}
+
+//analyze scores parameter set by distance to optimum short = 30, long = 60, stop_loss = 1
+func analyze(batch *Batch) []*Result {
+	res := make([]*Result, 0, len(batch.Candidates))
+	for _, params := range batch.Candidates {
+		score := decimal.Zero
+		for name, opt := range map[string]int64{"short": 30, "long": 60, "stop_loss": 1} {
+			val, _ := decimal.NewFromString(params[name])
+			score = score.Sub(val.Sub(decimal.NewFromInt(opt)).Abs())
+		}
+		res = append(res, &Result{Params: params, Score: score, Valid: true})
+	}
+	return res
+}
+
+func run(searcher Searcher) (int, []*Batch) {
+	trials := 0
+	batches := make([]*Batch, 0)
+	for batch := searcher.Next(); batch != nil; batch = searcher.Next() {
+		trials += len(batch.Candidates)
+		batches = append(batches, batch)
+		searcher.Report(analyze(batch))
+	}
+	return trials, batches
+}
+
+func TestNewSpace(t *testing.T) {
+	space := testSpace(t)
+	assert.Equal(t, 4, len(space.Dims), "numeric and not described ranges are varied")
+	assert.Equal(t, "child.window", space.Dims[0].Name)
+	assert.Equal(t, 9, len(space.Dims[2].Values), "values not satisfying spec are excluded")
+	assert.Equal(t, "10:00", space.Fixed["session_start"])
+
+	expanded := space.Expand([]map[string]string{{"short": "10", "long": "20", "stop_loss": "0.5:0.5:2"}})
+	assert.Equal(t, 4, len(expanded))
+	assert.Equal(t, "1.5", expanded[2]["stop_loss"])
+
+	_, err := FromDto(&dto.SearchConf{Mode: "bayes"})
+	assert.NotNil(t, err)
+}
+
+func TestSearchers_budget(t *testing.T) {
+	for _, mode := range []Mode{RandomMode, HalvingMode, GeneticMode} {
+		conf, err := FromDto(&dto.SearchConf{Mode: string(mode), Trials: 60, Seed: 7})
+		assert.Nil(t, err)
+		trials, batches := run(New(conf, testSpace(t), nil))
+		assert.True(t, trials <= 60, "mode %s exceeds budget: %d", mode, trials)
+		assert.True(t, trials >= 30, "mode %s uses budget: %d", mode, trials)
+		assert.Equal(t, 1.0, batches[len(batches)-1].Fidelity)
+		for _, batch := range batches {
+			for _, params := range batch.Candidates {
+				assert.Equal(t, "10:00", params["session_start"])
+				assert.NotContains(t, params["short"], ":")
+			}
+		}
+	}
+
+	conf, _ := FromDto(&dto.SearchConf{Mode: string(HalvingMode), Trials: 60, Seed: 7})
+	_, batches := run(New(conf, testSpace(t), nil))
+	sizes := make([]int, 0, len(batches))
+	for _, batch := range batches {
+		sizes = append(sizes, len(batch.Candidates))
+	}
+	assert.Equal(t, []int{39, 13, 5, 2}, sizes)
+	assert.InDelta(t, 1.0/27, batches[0].Fidelity, 1e-9)
+
+	//The best parameter sets of genetic search are better than random ones on average
+	conf, _ = FromDto(&dto.SearchConf{Mode: string(GeneticMode), Trials: 100, Population: 10, Seed: 7})
+	_, batches = run(New(conf, testSpace(t), nil))
+	avg := func(batch *Batch) decimal.Decimal {
+		sum := decimal.Zero
+		for _, res := range analyze(batch) {
+			sum = sum.Add(res.Score)
+		}
+		return sum.Div(decimal.NewFromInt(int64(len(batch.Candidates))))
+	}
+	assert.True(t, avg(batches[len(batches)-1]).GreaterThan(avg(batches[0])))
+}
@@ -0,0 +1,149 @@
+package search
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stbase"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+//Dimension is a varied numeric parameter with its possible values
+type Dimension struct {
+	Name   string
+	Values []decimal.Decimal
+}
+
+//Space describes parameters of search: numeric parameters set as range are varied, others are fixed
+type Space struct {
+	Fixed map[string]string
+	Dims  []*Dimension
+	Valid func(params map[string]string) bool //Optional filter of parameter sets, e.g. constraints of strategy splitter
+}
+
+//NewSpace creates search space from algorithm parameters. Parameter is varied if it is set as range and it is described
+//by strategy as numeric or not described at all (e.g. parameters of composite strategy children).
+//Range values not satisfying parameter spec are excluded
+func NewSpace(params map[string]string, specs []stmodel.ParamSpec, valid func(params map[string]string) bool) (*Space, error) {
+	specByName := make(map[string]*stmodel.ParamSpec, len(specs))
+	for i := range specs {
+		specByName[specs[i].Name] = &specs[i]
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	space := &Space{Fixed: make(map[string]string), Dims: make([]*Dimension, 0), Valid: valid}
+	for _, name := range names {
+		value := params[name]
+		spec, described := specByName[name]
+		numeric := described && (spec.Type == stmodel.IntParam || spec.Type == stmodel.DecimalParam)
+		if !strings.Contains(value, stbase.RangeSeparator) || (described && !numeric) {
+			space.Fixed[name] = value
+			continue
+		}
+		values, err := stbase.ParseRange(value)
+		if err != nil && numeric {
+			return nil, err
+		}
+		if err != nil || len(values) < 2 {
+			space.Fixed[name] = value
+			continue
+		}
+		dim := &Dimension{Name: name, Values: make([]decimal.Decimal, 0, len(values))}
+		for _, val := range values {
+			if !described || spec.Validate(val.String()) == nil {
+				dim.Values = append(dim.Values, val)
+			}
+		}
+		if len(dim.Values) == 0 {
+			return nil, errors.NewValidationErr(fmt.Sprintf("Range of parameter '%s' has no valid values", name))
+		}
+		space.Dims = append(space.Dims, dim)
+	}
+	return space, nil
+}
+
+//Expand replaces varied parameters still set as range in parameter sets by all their values.
+//It allows grid search by parameters not varied by strategy splitter
+func (s *Space) Expand(split []map[string]string) []map[string]string {
+	res := make([]map[string]string, 0, len(split))
+	for _, params := range split {
+		expanded := []map[string]string{params}
+		for _, dim := range s.Dims {
+			if !strings.Contains(params[dim.Name], stbase.RangeSeparator) {
+				continue
+			}
+			next := make([]map[string]string, 0, len(expanded)*len(dim.Values))
+			for _, base := range expanded {
+				for _, val := range dim.Values {
+					currParam := copyParams(base)
+					currParam[dim.Name] = val.String()
+					next = append(next, currParam)
+				}
+			}
+			expanded = next
+		}
+		res = append(res, expanded...)
+	}
+	return res
+}
+
+//sample returns parameter set with random values of varied parameters
+func (s *Space) sample(rnd *rand.Rand) map[string]string {
+	params := copyParams(s.Fixed)
+	for _, dim := range s.Dims {
+		params[dim.Name] = dim.Values[rnd.Intn(len(dim.Values))].String()
+	}
+	return params
+}
+
+//sampleUnique returns up to n valid random parameter sets not contained in seen ones, returned sets are added to seen
+func (s *Space) sampleUnique(rnd *rand.Rand, n int, seen map[string]bool) []map[string]string {
+	res := make([]map[string]string, 0, n)
+	for attempt := 0; attempt < n*maxAttempts && len(res) < n; attempt++ {
+		params := s.sample(rnd)
+		if s.accept(params, seen) {
+			res = append(res, params)
+		}
+	}
+	return res
+}
+
+//accept checks parameter set is valid and not seen before, accepted set is added to seen
+func (s *Space) accept(params map[string]string, seen map[string]bool) bool {
+	key := paramsKey(params)
+	if seen[key] || (s.Valid != nil && !s.Valid(params)) {
+		return false
+	}
+	seen[key] = true
+	return true
+}
+
+func paramsKey(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(key)
+		sb.WriteString("=")
+		sb.WriteString(params[key])
+		sb.WriteString(";")
+	}
+	return sb.String()
+}
+
+func copyParams(params map[string]string) map[string]string {
+	res := make(map[string]string, len(params))
+	for key, val := range params {
+		res[key] = val
+	}
+	return res
+}
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"strconv"
+	"time"
+)
+
+//Mode defines trading sessions when algorithm is allowed to trade
+type Mode string
+
+const (
+	Any         Mode = "any"          //No session gating - algorithm trades whenever exchange accepts orders
+	Main        Mode = "main"         //Main session only
+	MainEvening Mode = "main_evening" //Main and evening sessions
+)
+
+//Session parameters common for algorithms
+const (
+	SessionMode    string = "session"
+	NoEntryMinutes string = "session_no_entry_min"
+	CloseMinutes   string = "session_close_min"
+)
+
+//ParamSpecs describes session parameters processed by Gate
+var ParamSpecs = []stmodel.ParamSpec{
+	{Name: SessionMode, Type: stmodel.StringParam, Description: "Sessions allowed for trading: any (no gating), main or main_evening",
+		Default: string(Any)},
+	{Name: NoEntryMinutes, Type: stmodel.IntParam, Description: "Minutes before end of allowed session without opening new positions",
+		Default: "0", Min: stmodel.DecLimit(0)},
+	{Name: CloseMinutes, Type: stmodel.IntParam, Description: "Minutes before end of allowed session to close positions by market; positions are kept when 0",
+		Default: "0", Min: stmodel.DecLimit(0)},
+}
+
+//Gate decides when algorithm may trade by exchange schedule and session parameters:
+//orders are suppressed outside allowed sessions, new positions are not opened right before session end
+//and positions are closed at session end when configured
+type Gate struct {
+	mode     Mode
+	noEntry  time.Duration //No new positions during this period before session end
+	close    time.Duration //Positions are closed during this period before session end, zero when positions are kept
+	schedule Schedule
+}
+
+//window returns allowed session containing time, false if time is outside allowed sessions
+func (g *Gate) window(figi string, t time.Time) (Window, bool) {
+	day := g.schedule.GetDay(figi, t)
+	if !day.Trading {
+		return Window{}, false
+	}
+	if day.Main.Contains(t) {
+		return day.Main, true
+	}
+	if g.mode == MainEvening && day.Evening.Contains(t) {
+		return day.Evening, true
+	}
+	return Window{}, false
+}
+
+//CanTrade checks is time inside allowed sessions
+func (g *Gate) CanTrade(figi string, t time.Time) bool {
+	if g.mode == Any {
+		return true
+	}
+	_, ok := g.window(figi, t)
+	return ok
+}
+
+//CanOpen checks is opening new position allowed at the time
+func (g *Gate) CanOpen(figi string, t time.Time) bool {
+	if g.mode == Any {
+		return true
+	}
+	w, ok := g.window(figi, t)
+	if !ok {
+		return false
+	}
+	noEntry := g.noEntry
+	if g.close > noEntry {
+		noEntry = g.close
+	}
+	return t.Before(w.End.Add(-noEntry))
+}
+
+//MustClose checks must positions be closed at the time as allowed session is ending
+func (g *Gate) MustClose(figi string, t time.Time) bool {
+	if g.mode == Any || g.close == 0 {
+		return false
+	}
+	w, ok := g.window(figi, t)
+	return ok && !t.Before(w.End.Add(-g.close))
+}
+
+//NewGate creates gate by algorithm parameters, returns validation error on unknown session mode
+func NewGate(paramMap map[string]string, schedule Schedule) (*Gate, error) {
+	mode := Any
+	if val, ok := paramMap[SessionMode]; ok && val != "" {
+		mode = Mode(val)
+	}
+	if mode != Any && mode != Main && mode != MainEvening {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be one of %s, %s, %s; got '%s'",
+			SessionMode, Any, Main, MainEvening, mode))
+	}
+	return &Gate{
+		mode:     mode,
+		noEntry:  time.Duration(minutes(paramMap, NoEntryMinutes)) * time.Minute,
+		close:    time.Duration(minutes(paramMap, CloseMinutes)) * time.Minute,
+		schedule: schedule,
+	}, nil
+}
+
+func minutes(paramMap map[string]string, param string) int {
+	res, err := strconv.Atoi(paramMap[param])
+	if err != nil || res < 0 {
+		return 0
+	}
+	return res
+}
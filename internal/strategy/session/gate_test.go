@@ -0,0 +1,72 @@
+package session
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	mock_service "github.com/ldmi3i/tinkoff-invest-bot/internal/mocks/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func mskTime(day int, hour int, min int) time.Time {
+	//October 2022 - 3rd is Monday, 8th is Saturday
+	return time.Date(2022, time.October, day, hour, min, 0, 0, msk)
+}
+
+func TestGate_mainSession(t *testing.T) {
+	gate, err := NewGate(map[string]string{SessionMode: "main", NoEntryMinutes: "15", CloseMinutes: "5"}, StaticSchedule{})
+	assert.Nil(t, err)
+
+	assert.False(t, gate.CanTrade("figi", mskTime(3, 9, 59)))
+	assert.True(t, gate.CanOpen("figi", mskTime(3, 10, 0)))
+	assert.True(t, gate.CanOpen("figi", mskTime(3, 18, 24)))
+	//Last 15 minutes - no entries, but exits allowed
+	assert.False(t, gate.CanOpen("figi", mskTime(3, 18, 25)))
+	assert.True(t, gate.CanTrade("figi", mskTime(3, 18, 25)))
+	assert.False(t, gate.MustClose("figi", mskTime(3, 18, 34)))
+	assert.True(t, gate.MustClose("figi", mskTime(3, 18, 35)))
+	//Evening session is not allowed
+	assert.False(t, gate.CanTrade("figi", mskTime(3, 20, 0)))
+	//Weekend
+	assert.False(t, gate.CanTrade("figi", mskTime(8, 12, 0)))
+}
+
+func TestGate_anyAndEvening(t *testing.T) {
+	gate, err := NewGate(map[string]string{CloseMinutes: "5"}, StaticSchedule{})
+	assert.Nil(t, err)
+	assert.True(t, gate.CanOpen("figi", mskTime(8, 3, 0)))
+	assert.False(t, gate.MustClose("figi", mskTime(3, 18, 39)))
+
+	gate, err = NewGate(map[string]string{SessionMode: "main_evening"}, StaticSchedule{})
+	assert.Nil(t, err)
+	assert.True(t, gate.CanOpen("figi", mskTime(3, 20, 0)))
+	assert.False(t, gate.CanTrade("figi", mskTime(3, 18, 50)))
+
+	_, err = NewGate(map[string]string{SessionMode: "night"}, StaticSchedule{})
+	assert.NotNil(t, err)
+}
+
+func TestApiSchedule_GetDay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	infoSrv := mock_service.NewMockInfoSrv(ctrl)
+	infoSrv.EXPECT().GetInstrumentInfoByFigi("figi", gomock.Any()).Return(&dtotapi.InstrumentResponse{Exchange: "MOEX"}, nil).Times(1)
+	utc := func(day int, hour int) time.Time {
+		return time.Date(2022, time.October, day, hour, 0, 0, 0, time.UTC)
+	}
+	infoSrv.EXPECT().GetTradingSchedules(gomock.Any(), gomock.Any()).Return(&dtotapi.TradingSchedulesResponse{
+		Exchanges: []*dtotapi.TradingSchedule{{Exchange: "MOEX", Days: []*dtotapi.TradingDay{
+			{Date: utc(3, 0), IsTradingDay: true, StartTime: utc(3, 7), EndTime: utc(3, 15)},
+			{Date: utc(4, 0), IsTradingDay: false},
+		}}},
+	}, nil).Times(1)
+	schedule := NewApiSchedule(infoSrv, zap.NewNop().Sugar())
+
+	day := schedule.GetDay("figi", mskTime(3, 12, 0))
+	assert.True(t, day.Trading)
+	assert.True(t, day.Main.End.Equal(mskTime(3, 18, 0)))
+	assert.True(t, day.Evening.Start.IsZero())
+	//Cached days are used without new requests
+	assert.False(t, schedule.GetDay("figi", mskTime(4, 12, 0)).Trading)
+}
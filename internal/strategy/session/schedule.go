@@ -0,0 +1,156 @@
+package session
+
+import (
+	"context"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	dateLayout    = "2006-01-02"
+	scheduleDays  = 7           //Days of exchange schedule requested at once
+	retryInterval = time.Minute //Schedule API is not requested after error during this interval, default schedule is used instead
+)
+
+//Moscow time zone of default schedule - fixed offset to not depend on time zone database of the host
+var msk = time.FixedZone("MSK", 3*60*60)
+
+//Window is a time interval of trading session
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+//Contains checks is time inside window
+func (w Window) Contains(t time.Time) bool {
+	return !w.Start.IsZero() && !t.Before(w.Start) && t.Before(w.End)
+}
+
+//Day contains sessions of exchange trading day
+type Day struct {
+	Trading bool   //Is exchange trading at this day
+	Main    Window //Main session
+	Evening Window //Evening session, zero when day has no evening session
+}
+
+//Schedule provides trading sessions of instrument exchange
+type Schedule interface {
+	//GetDay returns sessions of instrument exchange at the day of provided time
+	GetDay(figi string, t time.Time) Day
+}
+
+//StaticSchedule is a default Moscow exchange schedule: main session 10:00-18:40 and evening session 19:05-23:50
+//by Moscow time on weekdays. Used in history analysis and when schedule API is not available
+type StaticSchedule struct{}
+
+func (s StaticSchedule) GetDay(_ string, t time.Time) Day {
+	mt := t.In(msk)
+	if mt.Weekday() == time.Saturday || mt.Weekday() == time.Sunday {
+		return Day{}
+	}
+	at := func(hour int, min int) time.Time {
+		return time.Date(mt.Year(), mt.Month(), mt.Day(), hour, min, 0, 0, msk)
+	}
+	return Day{
+		Trading: true,
+		Main:    Window{Start: at(10, 0), End: at(18, 40)},
+		Evening: Window{Start: at(19, 5), End: at(23, 50)},
+	}
+}
+
+//ApiSchedule provides sessions loaded from InstrumentsService.TradingSchedules by instrument exchange.
+//Loaded days and instrument exchanges are cached, static schedule is used when API request fails
+type ApiSchedule struct {
+	infoSrv   service.InfoSrv
+	exchanges collections.SyncMap[string, string] //Exchange of instrument by figi
+	days      map[string]Day                      //Exchange days by exchange and date
+	retryAt   time.Time                           //API is not requested till this time after error
+	fallback  Schedule
+	mu        sync.Mutex
+	logger    *zap.SugaredLogger
+}
+
+func (s *ApiSchedule) GetDay(figi string, t time.Time) Day {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Now().Before(s.retryAt) {
+		return s.fallback.GetDay(figi, t)
+	}
+	exchange, err := s.exchange(figi)
+	if err != nil {
+		s.logger.Errorf("Error while requesting exchange of instrument %s, default schedule used: %s", figi, err)
+		s.retryAt = time.Now().Add(retryInterval)
+		return s.fallback.GetDay(figi, t)
+	}
+	date := t.In(msk).Format(dateLayout)
+	if day, ok := s.days[exchange+date]; ok {
+		return day
+	}
+	if err = s.load(exchange, t); err != nil {
+		s.logger.Errorf("Error while requesting %s trading schedule, default schedule used: %s", exchange, err)
+		s.retryAt = time.Now().Add(retryInterval)
+		return s.fallback.GetDay(figi, t)
+	}
+	day, ok := s.days[exchange+date]
+	if !ok {
+		s.logger.Warnf("Day %s not found in %s trading schedule, default schedule used", date, exchange)
+		day = s.fallback.GetDay(figi, t)
+		s.days[exchange+date] = day
+	}
+	return day
+}
+
+//exchange returns cached exchange of instrument, requests it on the first call
+func (s *ApiSchedule) exchange(figi string) (string, error) {
+	if exchange, ok := s.exchanges.Get(figi); ok {
+		return exchange, nil
+	}
+	instr, err := s.infoSrv.GetInstrumentInfoByFigi(figi, context.Background())
+	if err != nil {
+		return "", err
+	}
+	s.exchanges.Put(figi, instr.Exchange)
+	return instr.Exchange, nil
+}
+
+//load requests exchange schedule for several days starting from the day of time and caches it
+func (s *ApiSchedule) load(exchange string, t time.Time) error {
+	mt := t.In(msk)
+	from := time.Date(mt.Year(), mt.Month(), mt.Day(), 0, 0, 0, 0, msk)
+	req := dtotapi.TradingSchedulesRequest{Exchange: exchange, From: from, To: from.AddDate(0, 0, scheduleDays)}
+	resp, err := s.infoSrv.GetTradingSchedules(&req, context.Background())
+	if err != nil {
+		return err
+	}
+	sched := resp.GetSchedule(exchange)
+	if sched == nil {
+		return nil
+	}
+	for _, tDay := range sched.Days {
+		day := Day{
+			Trading: tDay.IsTradingDay,
+			Main:    Window{Start: tDay.StartTime, End: tDay.EndTime},
+		}
+		if !tDay.EveningStartTime.IsZero() && tDay.EveningEndTime.After(tDay.EveningStartTime) {
+			day.Evening = Window{Start: tDay.EveningStartTime, End: tDay.EveningEndTime}
+		}
+		//Schedule dates are midnights of UTC
+		s.days[exchange+tDay.Date.UTC().Format(dateLayout)] = day
+	}
+	return nil
+}
+
+//NewApiSchedule creates schedule loading exchange sessions by info service
+func NewApiSchedule(infoSrv service.InfoSrv, logger *zap.SugaredLogger) Schedule {
+	return &ApiSchedule{
+		infoSrv:   infoSrv,
+		exchanges: collections.NewSyncMap[string, string](),
+		days:      make(map[string]Day),
+		fallback:  StaticSchedule{},
+		logger:    logger,
+	}
+}
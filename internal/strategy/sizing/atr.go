@@ -0,0 +1,31 @@
+package sizing
+
+import "github.com/shopspring/decimal"
+
+//Bar is a price range of single candle
+type Bar struct {
+	High  decimal.Decimal
+	Low   decimal.Decimal
+	Close decimal.Decimal
+}
+
+//Atr returns simple average of true ranges of the last period bars, bars must be sorted by time.
+//Returns zero when there are not enough bars
+func Atr(bars []Bar, period int) decimal.Decimal {
+	if period < 1 || len(bars) < period+1 {
+		return decimal.Zero
+	}
+	sum := decimal.Zero
+	for i := len(bars) - period; i < len(bars); i++ {
+		prevClose := bars[i-1].Close
+		tr := bars[i].High.Sub(bars[i].Low)
+		if hc := bars[i].High.Sub(prevClose).Abs(); hc.GreaterThan(tr) {
+			tr = hc
+		}
+		if lc := bars[i].Low.Sub(prevClose).Abs(); lc.GreaterThan(tr) {
+			tr = lc
+		}
+		sum = sum.Add(tr)
+	}
+	return sum.Div(decimal.NewFromInt(int64(period)))
+}
@@ -0,0 +1,119 @@
+package sizing
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+	"strconv"
+)
+
+//Type defines how trader calculates amount of lots to buy by algorithm limit
+type Type string
+
+const (
+	FullLimit    Type = "limit"   //Whole currency limit is spent on each buy
+	FixedLots    Type = "lots"    //Fixed number of lots
+	FixedMoney   Type = "money"   //Fixed amount of money
+	LimitPercent Type = "percent" //Percent of currency limit
+	EqualWeight  Type = "equal"   //Currency limit split equally between algorithm instruments
+	Volatility   Type = "atr"     //Percent of limit is risked on the stop distance measured in average true ranges
+)
+
+//Sizing parameters common for all strategies, processed by traders; parameter specs are added to strategies by registry
+const (
+	SizingType    string = "sizing"
+	SizingValue   string = "sizing_value"
+	SizingAtr     string = "sizing_atr_period"
+	SizingAtrMult string = "sizing_atr_mult"
+)
+
+//Policy calculates amount of lots to buy. Amount is always limited by currency limit of the algorithm
+type Policy struct {
+	Type      Type
+	Value     decimal.Decimal
+	AtrPeriod int             //Candles to calculate average true range
+	AtrMult   decimal.Decimal //Stop distance in average true ranges
+	FigiNum   int             //Number of algorithm instruments for equal weight split
+}
+
+//Order contains values required to size single buy order
+type Order struct {
+	Lim      decimal.Decimal //Currency limit of algorithm
+	PosPrice decimal.Decimal //Price of single instrument
+	PosInLot int64           //Instruments in lot
+	Atr      decimal.Decimal //Average true range of single instrument, used by atr sizing only
+}
+
+//NeedsAtr checks is average true range required to size order
+func (p *Policy) NeedsAtr() bool {
+	return p != nil && p.Type == Volatility
+}
+
+//Lots returns amount of lots to buy, zero when policy amount is lower than one lot or exceeds limit for one lot.
+//Nil policy spends whole limit
+func (p *Policy) Lots(order *Order) int64 {
+	lotPrice := order.PosPrice.Mul(decimal.NewFromInt(order.PosInLot))
+	if !lotPrice.IsPositive() {
+		return 0
+	}
+	maxLots := order.Lim.Div(lotPrice).Floor().IntPart()
+	if p == nil {
+		return maxLots
+	}
+	var lots int64
+	switch p.Type {
+	case FixedLots:
+		lots = p.Value.Floor().IntPart()
+	case FixedMoney:
+		lots = p.Value.Div(lotPrice).Floor().IntPart()
+	case LimitPercent:
+		lots = order.Lim.Mul(p.Value).Div(decimal.NewFromInt(100)).Div(lotPrice).Floor().IntPart()
+	case EqualWeight:
+		figiNum := p.FigiNum
+		if figiNum < 1 {
+			figiNum = 1
+		}
+		lots = order.Lim.Div(decimal.NewFromInt(int64(figiNum))).Div(lotPrice).Floor().IntPart()
+	case Volatility:
+		//Money lost when price drops by stop distance must not exceed risked part of limit
+		lotRisk := order.Atr.Mul(p.AtrMult).Mul(decimal.NewFromInt(order.PosInLot))
+		if !lotRisk.IsPositive() {
+			return 0
+		}
+		lots = order.Lim.Mul(p.Value).Div(decimal.NewFromInt(100)).Div(lotRisk).Floor().IntPart()
+	default:
+		lots = maxLots
+	}
+	if lots > maxLots {
+		return maxLots
+	}
+	return lots
+}
+
+//FromParams creates sizing policy from algorithm parameters, returns validation error on unknown sizing type
+//or missing sizing value
+func FromParams(paramMap map[string]string, figiNum int) (*Policy, error) {
+	policy := Policy{Type: FullLimit, AtrPeriod: 14, AtrMult: decimal.NewFromInt(2), FigiNum: figiNum}
+	if val, ok := paramMap[SizingType]; ok && val != "" {
+		policy.Type = Type(val)
+	}
+	switch policy.Type {
+	case FullLimit, EqualWeight:
+	case FixedLots, FixedMoney, LimitPercent, Volatility:
+		value, err := decimal.NewFromString(paramMap[SizingValue])
+		if err != nil || !value.IsPositive() {
+			return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be positive for '%s' sizing", SizingValue, policy.Type))
+		}
+		policy.Value = value
+	default:
+		return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be one of %s, %s, %s, %s, %s, %s; got '%s'",
+			SizingType, FullLimit, FixedLots, FixedMoney, LimitPercent, EqualWeight, Volatility, policy.Type))
+	}
+	if period, err := strconv.Atoi(paramMap[SizingAtr]); err == nil && period > 0 {
+		policy.AtrPeriod = period
+	}
+	if mult, err := decimal.NewFromString(paramMap[SizingAtrMult]); err == nil && mult.IsPositive() {
+		policy.AtrMult = mult
+	}
+	return &policy, nil
+}
@@ -0,0 +1,41 @@
+package sizing
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPolicy_Lots(t *testing.T) {
+	//Lot of 10 instruments by 10 costs 100, limit allows 10 lots
+	order := &Order{Lim: decimal.NewFromInt(1000), PosPrice: decimal.NewFromInt(10), PosInLot: 10, Atr: decimal.NewFromFloat(0.5)}
+	lots := func(params map[string]string) int64 {
+		policy, err := FromParams(params, 4)
+		assert.Nil(t, err)
+		return policy.Lots(order)
+	}
+	assert.Equal(t, int64(10), (*Policy)(nil).Lots(order))
+	assert.Equal(t, int64(10), lots(map[string]string{}))
+	assert.Equal(t, int64(3), lots(map[string]string{SizingType: "lots", SizingValue: "3"}))
+	assert.Equal(t, int64(10), lots(map[string]string{SizingType: "lots", SizingValue: "30"}))
+	assert.Equal(t, int64(2), lots(map[string]string{SizingType: "money", SizingValue: "250"}))
+	assert.Equal(t, int64(5), lots(map[string]string{SizingType: "percent", SizingValue: "50"}))
+	assert.Equal(t, int64(2), lots(map[string]string{SizingType: "equal"}))
+	//1% of limit risked on 2 ATR stop: 10 / (0.5 * 2 * 10) = 1 lot
+	assert.Equal(t, int64(1), lots(map[string]string{SizingType: "atr", SizingValue: "1"}))
+
+	_, err := FromParams(map[string]string{SizingType: "money"}, 1)
+	assert.NotNil(t, err)
+	_, err = FromParams(map[string]string{SizingType: "kelly", SizingValue: "1"}, 1)
+	assert.NotNil(t, err)
+}
+
+func TestAtr(t *testing.T) {
+	bar := func(high int64, low int64, cls int64) Bar {
+		return Bar{High: decimal.NewFromInt(high), Low: decimal.NewFromInt(low), Close: decimal.NewFromInt(cls)}
+	}
+	bars := []Bar{bar(10, 8, 9), bar(12, 10, 11), bar(11, 7, 8)}
+	//True ranges: max(2, 3, 1) = 3 and max(4, 0, 4) = 4
+	assert.True(t, Atr(bars, 2).Equal(decimal.NewFromFloat(3.5)))
+	assert.True(t, Atr(bars, 3).IsZero())
+}
@@ -0,0 +1,463 @@
+package stbase
+
+import (
+	"context"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/risk"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"github.com/tevino/abool/v2"
+	"go.uber.org/zap"
+	"time"
+)
+
+//SignalAlgorithm is algorithm which emits orders by signals of SignalGen.
+//Data processor provides candles, signal generator makes buy/sell decisions on them
+//and algorithm turns decisions to order requests the same way as avr algorithm does:
+//buys only when there is no previous buy, sells not cheaper than buy price plus 2x commissions
+//and sells by market when stop loss is enabled and price drops below it or trailing stop is triggered.
+//When short selling is enabled, sell signal without holdings opens short position, which is covered
+//by buy signal not higher than sell price minus 2x commissions or by market on stop loss.
+//Orders are requested only inside trading sessions allowed by session gate, positions are closed by market at session end if configured.
+//This implementation supports one subscription and communication with one trader
+type SignalAlgorithm struct {
+	id          uint                       //Algorithm id extracted for more convenience
+	strategy    string                     //Strategy name used for logging
+	isActive    *abool.AtomicBool          //Atomic bool indicating is algorithm active
+	dataProc    candle.DataProc            //Data processor - provides candles as the channel for algorithm
+	signalGen   SignalGen                  //Signal generator - makes buy/sell decisions
+	accountId   string                     //Account id extracted for more convenience
+	figis       []string                   //List of figis to monitor and use in algorithm
+	limits      []*entity.MoneyLimit       //Limits of money available for algorithm
+	algorithm   *entity.Algorithm          //Link to original object which algorithm based
+	param       map[string]string          //Map of different algorithm configuration parameters
+	conf        Config                     //Order parameters - expiration, commission, stop loss
+	aChan       chan *stmodel.ActionReq    //Channel to send order requests to trader
+	arChan      chan *stmodel.ActionResp   //Channel to receive responses from trader about action result
+	buyPrice    map[string]decimal.Decimal //Cache of position prices - buy price of long position or sell price of short one
+	instrAmount map[string]int64           //Amount of instruments available
+	trailing    *risk.TrailingStop         //Trailing stop of long positions, its state is persisted with algorithm context
+	gate        *session.Gate              //Trading session gate - suppresses orders outside allowed sessions
+	algRep      repository.AlgoRepository  //Repository to persist algorithm state, nil when state must not be saved (history)
+	clock       *SimClock                  //Simulation clock of history trader, disabled in production
+	ctx         context.Context
+	cancelF     context.CancelFunc
+
+	logger *zap.SugaredLogger
+}
+
+type algoStatus int
+
+const (
+	process algoStatus = iota
+	waitRes
+)
+
+func (a *SignalAlgorithm) Subscribe() (*stmodel.Subscription, error) {
+	if a.aChan != nil || a.arChan != nil {
+		return nil, errors.NewDoubleSubErr(a.strategy + " algorithm multiple subscription not implemented")
+	}
+	//Each instrument may have order in progress, buffer must fit all of them to not block algorithm
+	a.aChan = make(chan *stmodel.ActionReq, len(a.figis)+1)
+	a.arChan = make(chan *stmodel.ActionResp, 1) //must not block trader, so size = 1
+	sub := &stmodel.Subscription{AlgoID: a.id, AChan: a.aChan, RChan: a.arChan}
+	a.clock.Subscribe(sub)
+	return sub, nil
+}
+
+func (a *SignalAlgorithm) IsActive() bool {
+	return a.isActive.IsSet()
+}
+
+func (a *SignalAlgorithm) Go(parCtx context.Context) error {
+	a.ctx, a.cancelF = context.WithCancel(parCtx)
+	ch, err := a.dataProc.GetDataStream()
+	if err != nil {
+		return err
+	}
+	go a.procBg(ch)
+	err = a.dataProc.Go(a.ctx)
+	if err != nil {
+		a.logger.Error("Error while starting data processor: ", err)
+		a.stopInternal()
+		return errors.NewUnexpectedError("Error while starting data processor " + err.Error())
+	}
+	a.isActive.Set()
+	return nil
+}
+
+func (a *SignalAlgorithm) procBg(datCh <-chan candle.Candle) {
+	defer func() {
+		a.isActive.UnSet()
+		close(a.aChan)
+		a.clock.Close(a.arChan)
+		a.logger.Infof("Stopping algorithm background; ID: %d", a.id)
+	}()
+	statusMap := make(map[string]algoStatus)
+	for _, figi := range a.figis {
+		statusMap[figi] = process
+	}
+	//Restored algorithm may have posted orders - wait for their results before new requests
+	for _, action := range a.algorithm.Actions {
+		if action.Status == entity.Posted {
+			statusMap[action.InstrFigi] = waitRes
+		}
+	}
+	//Positions held before trailing stop was enabled are tracked from the buy price
+	for figi, amount := range a.instrAmount {
+		if amount > 0 {
+			a.trailing.Open(figi, a.buyPrice[figi], time.Time{})
+		}
+	}
+	a.logger.Infof("Starting background algorithm processing; id: %d , strategy: %s , limits: %+v",
+		a.id, a.strategy, a.limits)
+	processResp := func(resp *stmodel.ActionResp) {
+		a.processTraderResp(statusMap, resp)
+	}
+	for {
+		select {
+		case resp, ok := <-a.arChan:
+			if !ok {
+				a.logger.Warn("Trader closed response channel, stopping algorithm...")
+				return
+			}
+			processResp(resp)
+		case cDat, ok := <-datCh:
+			if !ok {
+				a.logger.Infof("Closed data processor stream, stopping algorithm...")
+				return
+			}
+			a.processCandle(statusMap, &cDat)
+			if !a.clock.Send(a.ctx, cDat.RetrievedAt, a.arChan, processResp) {
+				return
+			}
+		case <-a.ctx.Done():
+			a.logger.Info("Context canceled, stopping...")
+			return
+		}
+	}
+}
+
+//process response from trade.Trader after requested passed trading stages
+func (a *SignalAlgorithm) processTraderResp(statusMap map[string]algoStatus, resp *stmodel.ActionResp) {
+	action := resp.Action
+	a.logger.Debug("Processing trader response: ", *action)
+	//Holdings are updated by really executed lots - canceled order may be partially filled
+	if iAmount := action.ExecutedAmount(); iAmount != 0 {
+		if action.Status != entity.Success {
+			a.logger.Infof("Order partially executed: %s", action.Info)
+		}
+		before := a.instrAmount[action.InstrFigi]
+		a.logger.Infof("Incrementing instrument: %s with amount %d", action.InstrFigi, iAmount)
+		a.instrAmount[action.InstrFigi] = before + iAmount
+		UpdatePositionPrice(a.buyPrice, before, a.instrAmount[action.InstrFigi], action)
+		if a.instrAmount[action.InstrFigi] > 0 {
+			a.trailing.Open(action.InstrFigi, action.PositionPrice, action.RetrievedAt)
+		} else {
+			a.trailing.Close(action.InstrFigi)
+		}
+	} else {
+		a.logger.Infof("Operation failed %+v", resp)
+	}
+	statusMap[action.InstrFigi] = process
+	a.updateState()
+	PersistState(a.algRep, a.algorithm, action, a.logger)
+}
+
+//processCandle passes candle to signal generator and requests order if signal and holdings allow it
+func (a *SignalAlgorithm) processCandle(statusMap map[string]algoStatus, cDat *candle.Candle) {
+	//Signal generator must see every candle to keep indicators actual, even while order is processing
+	signal := a.signalGen.Process(cDat)
+	if cDat.Prefetched {
+		return
+	}
+	//Trailing stop must see every candle as well to keep the highest price actual
+	stopPrice, stopped := a.trailing.Check(cDat)
+	if a.trailing.IsChanged() && a.algRep != nil {
+		a.updateState()
+		PersistState(a.algRep, a.algorithm, nil, a.logger)
+	}
+	if status, ok := statusMap[cDat.Figi]; ok && status != process {
+		a.logger.Debugf("Waiting in status: %d", status)
+		return
+	}
+	statusMap[cDat.Figi] = process
+	if !a.gate.CanTrade(cDat.Figi, cDat.RetrievedAt) {
+		return
+	}
+	if a.gate.MustClose(cDat.Figi, cDat.RetrievedAt) {
+		a.closeBySession(statusMap, cDat)
+		return
+	}
+	if a.instrAmount[cDat.Figi] < 0 {
+		a.processShort(statusMap, cDat, signal)
+		return
+	}
+	buyPrice, bought := a.buyPrice[cDat.Figi]
+	if bought && a.conf.StopLossEnabled && cDat.Close.LessThanOrEqual(buyPrice.Mul(a.conf.StopLossRel)) {
+		//If current price lower than stop loss - sell using market order
+		a.logger.Infof("Stop loss reached; Current price: %s, stop loss price: %s", cDat.Close, buyPrice.Mul(a.conf.StopLossRel))
+		a.doSell(statusMap, cDat, entity.Market, decimal.Zero)
+		return
+	}
+	if stopped && a.instrAmount[cDat.Figi] > 0 {
+		a.logger.Infof("Trailing stop reached; Current price: %s, stop execution price: %s", cDat.Close, stopPrice)
+		a.doSell(statusMap, cDat, entity.Market, stopPrice)
+		return
+	}
+	switch signal.Type {
+	case BuySignal:
+		if bought {
+			a.logger.Info("Previous buy operation not finished with price: ", buyPrice, "; waiting for sell operation...")
+			return
+		}
+		if !a.gate.CanOpen(cDat.Figi, cDat.RetrievedAt) {
+			a.logger.Infof("Buy signal suppressed - session is ending: %s", signal.Info)
+			return
+		}
+		a.logger.Infof("Buy signal: %s", signal.Info)
+		a.doBuy(statusMap, cDat, signal.LimitPart)
+	case SellSignal:
+		if a.instrAmount[cDat.Figi] == 0 {
+			if a.conf.ShortEnabled && a.gate.CanOpen(cDat.Figi, cDat.RetrievedAt) {
+				a.logger.Infof("Sell signal without holdings, opening short position: %s", signal.Info)
+				a.doShort(statusMap, cDat, signal.LimitPart)
+			}
+			return
+		}
+		if bought {
+			buyPriceComm := buyPrice.Mul(decimal.NewFromInt(1).Add(a.conf.Commission.Mul(decimal.NewFromInt(2))))
+			if buyPriceComm.GreaterThanOrEqual(cDat.Close) {
+				a.logger.Infof("Buy price %s is greater than current %s plus 2x commissions - not good enough, waiting better...",
+					buyPriceComm, cDat.Close)
+				return
+			}
+		}
+		a.logger.Infof("Sell signal: %s", signal.Info)
+		a.doSell(statusMap, cDat, entity.Limited, decimal.Zero)
+	}
+}
+
+//processShort covers short position by buy signal when price is lower than sell price minus 2x commissions
+//or by market when stop loss is enabled and price rises above it
+func (a *SignalAlgorithm) processShort(statusMap map[string]algoStatus, cDat *candle.Candle, signal Signal) {
+	sellPrice, sold := a.buyPrice[cDat.Figi]
+	if sold && a.conf.StopLossEnabled && cDat.Close.GreaterThanOrEqual(sellPrice.Mul(a.conf.StopLossUpRel)) {
+		a.logger.Infof("Short stop loss reached; Current price: %s, stop loss price: %s", cDat.Close, sellPrice.Mul(a.conf.StopLossUpRel))
+		a.doCover(statusMap, cDat, entity.Market)
+		return
+	}
+	if signal.Type != BuySignal {
+		return
+	}
+	if sold {
+		sellPriceComm := sellPrice.Mul(decimal.NewFromInt(1).Sub(a.conf.Commission.Mul(decimal.NewFromInt(2))))
+		if sellPriceComm.LessThanOrEqual(cDat.Close) {
+			a.logger.Infof("Sell price %s minus 2x commissions is lower than current %s - not good enough, waiting better...",
+				sellPriceComm, cDat.Close)
+			return
+		}
+	}
+	a.logger.Infof("Buy signal, covering short position: %s", signal.Info)
+	a.doCover(statusMap, cDat, entity.Limited)
+}
+
+//closeBySession closes long or short position by market as allowed trading session is ending
+func (a *SignalAlgorithm) closeBySession(statusMap map[string]algoStatus, cDat *candle.Candle) {
+	switch amount := a.instrAmount[cDat.Figi]; {
+	case amount > 0:
+		a.logger.Infof("Session is ending, closing position of %s by market", cDat.Figi)
+		a.doSell(statusMap, cDat, entity.Market, decimal.Zero)
+	case amount < 0:
+		a.logger.Infof("Session is ending, covering short position of %s by market", cDat.Figi)
+		a.doCover(statusMap, cDat, entity.Market)
+	}
+}
+
+func (a *SignalAlgorithm) doBuy(statusMap map[string]algoStatus, cDat *candle.Candle, limitPart decimal.Decimal) {
+	action := entity.Action{
+		AlgorithmID:    a.id,
+		Direction:      entity.Buy,
+		InstrFigi:      cDat.Figi,
+		ReqPrice:       cDat.Close,
+		ExpirationTime: time.Now().Add(a.conf.OrderExp),
+		Status:         entity.Created,
+		OrderType:      entity.Limited,
+		RetrievedAt:    cDat.RetrievedAt,
+		AccountID:      a.accountId,
+		StopLoss:       a.conf.BrokerStopLoss,
+		TakeProfit:     a.conf.TakeProfit,
+	}
+	a.logger.Infof("Conditions for Buy, requesting action: %+v with limit part: %s", action, limitPart)
+	req := a.makeReq(&action)
+	req.LimitPart = limitPart
+	a.aChan <- req
+	statusMap[cDat.Figi] = waitRes
+}
+
+//doSell requests sell of all held instrument amount; trigger price is set for market sell triggered by stop
+func (a *SignalAlgorithm) doSell(statusMap map[string]algoStatus, cDat *candle.Candle, orderType entity.OrderType, triggerPrice decimal.Decimal) {
+	amount := a.instrAmount[cDat.Figi]
+	if amount == 0 {
+		return
+	}
+	action := entity.Action{
+		AlgorithmID:    a.id,
+		Direction:      entity.Sell,
+		InstrFigi:      cDat.Figi,
+		LotAmount:      amount,
+		ReqPrice:       cDat.Close,
+		TriggerPrice:   triggerPrice,
+		ExpirationTime: time.Now().Add(a.conf.OrderExp),
+		Status:         entity.Created,
+		OrderType:      orderType,
+		RetrievedAt:    cDat.RetrievedAt,
+		AccountID:      a.accountId,
+	}
+	a.logger.Infof("Conditions for Sell, requesting action: %+v", action)
+	a.aChan <- a.makeReq(&action)
+	statusMap[cDat.Figi] = waitRes
+}
+
+//doShort requests sell of not held instrument, amount of short position is defined by trader using money limit
+func (a *SignalAlgorithm) doShort(statusMap map[string]algoStatus, cDat *candle.Candle, limitPart decimal.Decimal) {
+	action := entity.Action{
+		AlgorithmID:    a.id,
+		Direction:      entity.Sell,
+		InstrFigi:      cDat.Figi,
+		Short:          true,
+		ReqPrice:       cDat.Close,
+		ExpirationTime: time.Now().Add(a.conf.OrderExp),
+		Status:         entity.Created,
+		OrderType:      entity.Limited,
+		RetrievedAt:    cDat.RetrievedAt,
+		AccountID:      a.accountId,
+		StopLoss:       a.conf.BrokerStopLoss,
+		TakeProfit:     a.conf.TakeProfit,
+	}
+	a.logger.Infof("Conditions for short Sell, requesting action: %+v with limit part: %s", action, limitPart)
+	req := a.makeReq(&action)
+	req.LimitPart = limitPart
+	a.aChan <- req
+	statusMap[cDat.Figi] = waitRes
+}
+
+//doCover requests buy of whole short position amount
+func (a *SignalAlgorithm) doCover(statusMap map[string]algoStatus, cDat *candle.Candle, orderType entity.OrderType) {
+	action := entity.Action{
+		AlgorithmID:    a.id,
+		Direction:      entity.Buy,
+		InstrFigi:      cDat.Figi,
+		LotAmount:      -a.instrAmount[cDat.Figi],
+		ReqPrice:       cDat.Close,
+		ExpirationTime: time.Now().Add(a.conf.OrderExp),
+		Status:         entity.Created,
+		OrderType:      orderType,
+		RetrievedAt:    cDat.RetrievedAt,
+		AccountID:      a.accountId,
+	}
+	a.logger.Infof("Conditions for cover Buy, requesting action: %+v", action)
+	a.aChan <- a.makeReq(&action)
+	statusMap[cDat.Figi] = waitRes
+}
+
+//updateState updates algorithm context parameters from current state
+func (a *SignalAlgorithm) updateState() {
+	if err := SetInstrumentsState(a.algorithm, a.instrAmount, a.buyPrice); err != nil {
+		a.logger.Errorf("Error while marshalling state of algorithm %d: %s", a.id, err)
+	}
+	if err := a.trailing.SetState(a.algorithm); err != nil {
+		a.logger.Errorf("Error while marshalling trailing stop of algorithm %d: %s", a.id, err)
+	}
+}
+
+func (a *SignalAlgorithm) makeReq(action *entity.Action) *stmodel.ActionReq {
+	return &stmodel.ActionReq{
+		Action: action,
+		Limits: a.limits,
+	}
+}
+
+func (a *SignalAlgorithm) Stop() error {
+	if a.isActive.IsNotSet() {
+		a.logger.Info("Algorithm already stopped, do nothing...")
+		return nil
+	}
+	a.stopInternal()
+	return nil
+}
+
+func (a *SignalAlgorithm) stopInternal() {
+	a.cancelF()
+	a.logger.Infof("Algorithm %d successfully stopped", a.id)
+}
+
+func (a *SignalAlgorithm) Configure(ctx []*entity.CtxParam) error {
+	if ctx == nil {
+		a.logger.Infof("Algorithm %d configuration not set, skipping", a.id)
+		return nil
+	}
+	confCtx := entity.ContextToMap(ctx)
+	if err := configure(confCtx, &algoState{
+		InitAmount: a.instrAmount,
+		BuyPrice:   a.buyPrice,
+	}, a.logger); err != nil {
+		return err
+	}
+	return a.trailing.Restore(confCtx)
+}
+
+func (a *SignalAlgorithm) GetParam() map[string]string {
+	return a.param
+}
+
+func (a *SignalAlgorithm) GetAlgorithm() *entity.Algorithm {
+	return a.algorithm
+}
+
+//NewSignalAlgorithm constructs algorithm emitting orders by signals of generator on data processor candles.
+//Repository is used to persist algorithm state and may be nil for history algorithms,
+//schedule provides trading sessions for session gating, simulation clock must be enabled for history algorithms
+func NewSignalAlgorithm(strategy string, algo *entity.Algorithm, algRep repository.AlgoRepository, proc candle.DataProc,
+	gen SignalGen, schedule session.Schedule, simClock bool, logger *zap.SugaredLogger) (stmodel.Algorithm, error) {
+	paramMap := entity.ParamsToMap(algo.Params)
+	conf := ConfigFromParams(paramMap)
+	gate, err := session.NewGate(paramMap, schedule)
+	if err != nil {
+		return nil, err
+	}
+	algorithm := &SignalAlgorithm{
+		id:          algo.ID,
+		strategy:    strategy,
+		isActive:    abool.NewBool(true),
+		dataProc:    proc,
+		signalGen:   gen,
+		accountId:   algo.AccountId,
+		figis:       algo.Figis,
+		limits:      algo.MoneyLimits,
+		algorithm:   algo,
+		param:       paramMap,
+		conf:        conf,
+		buyPrice:    make(map[string]decimal.Decimal),
+		instrAmount: make(map[string]int64),
+		trailing:    risk.NewTrailingStop(conf.TrailingStop),
+		gate:        gate,
+		algRep:      algRep,
+		clock:       NewSimClock(simClock, logger),
+		logger:      logger,
+	}
+	if err := algorithm.Configure(algo.CtxParams); err != nil {
+		logger.Errorf("Failed configure algorithm %d with configuration %+v", algo.ID, algo.CtxParams)
+		return nil, err
+	}
+	return algorithm, nil
+}
+
+//HistLogger returns logger with increased level to suppress not necessary logging of history analysis
+func HistLogger(rootLogger *zap.SugaredLogger) *zap.SugaredLogger {
+	return zap.New(rootLogger.Desugar().Core(), zap.IncreaseLevel(zap.WarnLevel)).Sugar()
+}
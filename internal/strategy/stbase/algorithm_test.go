@@ -0,0 +1,44 @@
+package stbase
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+//buyGen returns buy signal on every candle and counts processed candles
+type buyGen struct {
+	processed int
+}
+
+func (g *buyGen) Process(*candle.Candle) Signal {
+	g.processed++
+	return Signal{Type: BuySignal}
+}
+
+func TestProcessCandle_prefetched(t *testing.T) {
+	gen := &buyGen{}
+	alg, err := NewSignalAlgorithm("test", &entity.Algorithm{Figis: []string{"figi"}}, nil, nil, gen,
+		session.StaticSchedule{}, false, zap.NewNop().Sugar())
+	assert.Nil(t, err)
+	a := alg.(*SignalAlgorithm)
+	_, err = a.Subscribe()
+	assert.Nil(t, err)
+	statusMap := map[string]algoStatus{"figi": process}
+
+	//Prefetched candle only updates signal generator
+	a.processCandle(statusMap, &candle.Candle{Figi: "figi", Close: decimal.NewFromInt(100), RetrievedAt: time.Now(), Prefetched: true})
+	assert.Equal(t, 1, gen.processed)
+	assert.Equal(t, 0, len(a.aChan))
+
+	a.processCandle(statusMap, &candle.Candle{Figi: "figi", Close: decimal.NewFromInt(100), RetrievedAt: time.Now()})
+	assert.Equal(t, 2, gen.processed)
+	assert.Equal(t, 1, len(a.aChan))
+	req := <-a.aChan
+	assert.Equal(t, entity.Buy, req.Action.Direction)
+}
@@ -0,0 +1,63 @@
+package stbase
+
+import (
+	"context"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"go.uber.org/zap"
+	"time"
+)
+
+//SimClock sends time of processed candles to history trader, so trader fills orders only by candles already seen by algorithm.
+//Disabled clock (production) does nothing
+type SimClock struct {
+	enabled bool
+	tChan   chan time.Time //Channel to send time of processed candles to trader, nil when clock disabled
+	logger  *zap.SugaredLogger
+}
+
+//Subscribe adds clock channel to subscription when clock is enabled
+func (c *SimClock) Subscribe(sub *stmodel.Subscription) {
+	if !c.enabled {
+		return
+	}
+	c.tChan = make(chan time.Time)
+	sub.TChan = c.tChan
+}
+
+//Send sends candle time to trader, processing trader responses while trader is busy.
+//Returns false if algorithm must be stopped
+func (c *SimClock) Send(ctx context.Context, tm time.Time, arChan <-chan *stmodel.ActionResp, processResp func(*stmodel.ActionResp)) bool {
+	if c.tChan == nil {
+		return true
+	}
+	for {
+		select {
+		case c.tChan <- tm:
+			return true
+		case resp, ok := <-arChan:
+			if !ok {
+				c.logger.Warn("Trader closed response channel, stopping algorithm...")
+				return false
+			}
+			processResp(resp)
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+//Close stops the clock when algorithm stops
+func (c *SimClock) Close(arChan <-chan *stmodel.ActionResp) {
+	if c.tChan == nil {
+		return
+	}
+	close(c.tChan)
+	//History trader may still send results of resting orders - read them until trader finishes
+	for range arChan {
+	}
+}
+
+//NewSimClock creates simulation clock, it must be enabled only for history algorithms
+func NewSimClock(enabled bool, logger *zap.SugaredLogger) *SimClock {
+	return &SimClock{enabled: enabled, logger: logger}
+}
@@ -0,0 +1,28 @@
+package stbase
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+//algoState is state of algorithm restored from algorithm context
+type algoState struct {
+	InitAmount map[string]int64           //Initial lot amount of instrument
+	BuyPrice   map[string]decimal.Decimal //Buy price - sell no cheaper then it
+}
+
+func configure(confCtx map[string]string, state *algoState, logger *zap.SugaredLogger) error {
+	instrAmount, buyPrice, err := GetInstrumentsState(confCtx)
+	if err != nil {
+		logger.Warnf("Unable unmarshal '%s' to Instruments", confCtx[dto.InstrAmountField])
+		return err
+	}
+	for figi, amount := range instrAmount {
+		state.InitAmount[figi] = amount
+	}
+	for figi, price := range buyPrice {
+		state.BuyPrice[figi] = price
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package stbase
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/session"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"time"
+)
+
+//Order parameters common for all signal algorithms
+const (
+	OrderExpiration string = "order_expiration"
+	Commission      string = "order_commission"
+	StopLoss        string = "stop_loss"
+	ShortEnabled    string = "short_enabled"
+	BrokerStopLoss  string = "broker_stop_loss"
+	TakeProfit      string = "take_profit"
+	TrailingStop    string = "trailing_stop"
+)
+
+//CommonParamSpecs describes order parameters processed by SignalAlgorithm - strategies add them to own parameter specs
+var CommonParamSpecs = []stmodel.ParamSpec{
+	{Name: OrderExpiration, Type: stmodel.IntParam, Description: "Limited order expiration time in seconds",
+		Default: "300", Min: stmodel.DecLimit(1)},
+	{Name: Commission, Type: stmodel.DecimalParam, Description: "Commission of single order in percents",
+		Default: "0.04", Min: stmodel.DecLimit(0)},
+	{Name: StopLoss, Type: stmodel.DecimalParam, Description: "Price drop in percents from buy price to sell by market; disabled when not set",
+		Min: stmodel.DecLimit(0), Max: stmodel.DecLimit(100)},
+	{Name: ShortEnabled, Type: stmodel.BoolParam, Description: "Allows to sell instruments not held by algorithm (short position) if instrument and account support it",
+		Default: "false"},
+	{Name: BrokerStopLoss, Type: stmodel.DecimalParam, Description: "Price drop in percents from buy fill price (growth from short sell fill price) to place broker stop loss order; disabled when not set",
+		Min: stmodel.DecLimit(0), Max: stmodel.DecLimit(100)},
+	{Name: TakeProfit, Type: stmodel.DecimalParam, Description: "Price growth in percents from buy fill price (drop from short sell fill price) to place broker take profit order; disabled when not set",
+		Min: stmodel.DecLimit(0)},
+	{Name: TrailingStop, Type: stmodel.DecimalParam, Description: "Price drop in percents from the highest price after buy to sell by market; disabled when not set",
+		Min: stmodel.DecLimit(0), Max: stmodel.DecLimit(100)},
+}
+
+//Config keeps order parameters of signal algorithm
+type Config struct {
+	OrderExp        time.Duration   //Expiration duration of posted limited orders
+	Commission      decimal.Decimal //Commission of single order as a fraction (not percents)
+	StopLossEnabled bool            //Is stop loss enabled
+	StopLossRel     decimal.Decimal //Relative to buy price limit, when crossed - process market sell
+	StopLossUpRel   decimal.Decimal //Relative to short sell price limit, when crossed - process market buy to cover
+	ShortEnabled    bool            //Is algorithm allowed to open short positions
+	BrokerStopLoss  decimal.Decimal //Percents of broker stop loss order requested with buy, zero when disabled
+	TakeProfit      decimal.Decimal //Percents of broker take profit order requested with buy, zero when disabled
+	TrailingStop    decimal.Decimal //Percents of trailing stop, zero when disabled
+}
+
+//ConfigFromParams extracts common order parameters from algorithm parameters
+func ConfigFromParams(paramMap map[string]string) Config {
+	stopLossPercent, stopLossEnabled := GetDecimal(paramMap, StopLoss)
+	return Config{
+		OrderExp:        time.Duration(GetOrDefaultInt(paramMap, OrderExpiration, 300)) * time.Second,
+		Commission:      GetOrDefaultDecimal(paramMap, Commission, decimal.NewFromFloat(0.04)).Div(decimal.NewFromInt(100)),
+		StopLossEnabled: stopLossEnabled,
+		StopLossRel:     decimal.NewFromInt(1).Sub(stopLossPercent.Div(decimal.NewFromInt(100))),
+		StopLossUpRel:   decimal.NewFromInt(1).Add(stopLossPercent.Div(decimal.NewFromInt(100))),
+		ShortEnabled:    GetOrDefaultBool(paramMap, ShortEnabled, false),
+		BrokerStopLoss:  GetOrDefaultDecimal(paramMap, BrokerStopLoss, decimal.Zero),
+		TakeProfit:      GetOrDefaultDecimal(paramMap, TakeProfit, decimal.Zero),
+		TrailingStop:    GetOrDefaultDecimal(paramMap, TrailingStop, decimal.Zero),
+	}
+}
+
+//WithParamSpecs returns strategy specific parameter specs extended with common order and session parameter specs
+func WithParamSpecs(specs ...stmodel.ParamSpec) []stmodel.ParamSpec {
+	res := make([]stmodel.ParamSpec, 0, len(specs)+len(CommonParamSpecs)+len(session.ParamSpecs))
+	res = append(res, specs...)
+	res = append(res, CommonParamSpecs...)
+	return append(res, session.ParamSpecs...)
+}
+
+func GetOrDefaultDecimal(paramMap map[string]string, param string, def decimal.Decimal) decimal.Decimal {
+	res, ok := GetDecimal(paramMap, param)
+	if !ok {
+		return def
+	}
+	return res
+}
+
+func GetOrDefaultInt(paramMap map[string]string, param string, def int) int {
+	res, ok := paramMap[param]
+	if !ok {
+		return def
+	}
+	resInt, err := strconv.Atoi(res)
+	if err != nil {
+		return def
+	}
+	return resInt
+}
+
+func GetOrDefaultBool(paramMap map[string]string, param string, def bool) bool {
+	res, ok := paramMap[param]
+	if !ok {
+		return def
+	}
+	resBool, err := strconv.ParseBool(res)
+	if err != nil {
+		return def
+	}
+	return resBool
+}
+
+func GetDecimal(paramMap map[string]string, param string) (decimal.Decimal, bool) {
+	res, ok := paramMap[param]
+	if !ok {
+		return decimal.Zero, false
+	}
+	resDec, err := decimal.NewFromString(res)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return resDec, true
+}
@@ -0,0 +1,33 @@
+package stbase
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/candle"
+	"github.com/shopspring/decimal"
+)
+
+type SignalType int
+
+const (
+	Hold SignalType = iota //No action required
+	BuySignal
+	SellSignal
+)
+
+//Signal represents signal generator decision made on the candle
+type Signal struct {
+	Type      SignalType
+	LimitPart decimal.Decimal //Optional part of money limit to spend on buy in (0, 1] range; zero means whole limit
+	Info      string          //Reason of the signal, used for logging
+}
+
+//SignalGen generates trading signals from candles.
+//Signal generator knows nothing about orders and holdings - it's a responsibility of SignalAlgorithm
+type SignalGen interface {
+	//Process consumes next candle and returns signal by the candle figi
+	Process(c *candle.Candle) Signal
+}
+
+//HoldSignal is auxiliary to return no action signal
+func HoldSignal() Signal {
+	return Signal{Type: Hold}
+}
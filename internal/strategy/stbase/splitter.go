@@ -0,0 +1,133 @@
+package stbase
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"regexp"
+)
+
+//RangeSeparator separates start, step and end of parameter range - 'start:step:end' or 'start:end' with step 1
+const RangeSeparator string = ":"
+
+var sepRgx = regexp.MustCompile("\\s*" + RangeSeparator + "\\s*")
+
+//RangeFilter decides if combination of ranged parameter values is valid and must be analyzed
+type RangeFilter func(values map[string]decimal.Decimal) bool
+
+//RangeSplitter splits ranges of numeric parameters into all their combinations.
+//Ranged parameter not set in request takes default value from parameter spec, other parameters are copied as is
+type RangeSplitter struct {
+	specs  []stmodel.ParamSpec //Strategy parameter specs used to find defaults of ranged parameters
+	ranged []string            //Names of parameters which may be set as range
+	filter RangeFilter         //Optional filter of parameter combinations
+	logger *zap.SugaredLogger
+}
+
+func (r *RangeSplitter) ParseAndSplit(param map[string]string) ([]map[string]string, error) {
+	constParam := make(map[string]string)
+	for key, val := range param {
+		constParam[key] = val
+	}
+	names := make([]string, 0, len(r.ranged))
+	ranges := make([][]decimal.Decimal, 0, len(r.ranged))
+	for _, name := range r.ranged {
+		value, ok := param[name]
+		if !ok {
+			value, ok = r.getDefault(name)
+			if !ok {
+				r.logger.Errorf("Range of parameter '%s' must be defined", name)
+				return nil, errors.NewValidationErr(fmt.Sprintf("Range of parameter '%s' not defined", name))
+			}
+		}
+		values, err := ParseRange(value)
+		if err != nil {
+			r.logger.Errorf("Error while converting '%s' expression to range: %s", name, value)
+			return nil, err
+		}
+		delete(constParam, name)
+		names = append(names, name)
+		ranges = append(ranges, values)
+	}
+	res := make([]map[string]string, 0)
+	curr := make(map[string]decimal.Decimal, len(names))
+	var combine func(idx int)
+	combine = func(idx int) {
+		if idx == len(names) {
+			if r.filter != nil && !r.filter(curr) {
+				return
+			}
+			currParam := make(map[string]string, len(constParam)+len(curr))
+			for key, val := range constParam {
+				currParam[key] = val
+			}
+			for key, val := range curr {
+				currParam[key] = val.String()
+			}
+			res = append(res, currParam)
+			return
+		}
+		for _, value := range ranges[idx] {
+			curr[names[idx]] = value
+			combine(idx + 1)
+		}
+	}
+	combine(0)
+	return res, nil
+}
+
+func (r *RangeSplitter) getDefault(name string) (string, bool) {
+	for _, spec := range r.specs {
+		if spec.Name == name && spec.Default != "" {
+			return spec.Default, true
+		}
+	}
+	return "", false
+}
+
+//ParseRange converts range expression 'start:step:end', 'start:end' (step 1) or single value to values slice
+func ParseRange(expr string) ([]decimal.Decimal, error) {
+	limits := sepRgx.Split(expr, -1)
+	ln := len(limits)
+	if ln > 3 {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Range '%s' has wrong format", expr))
+	}
+	start, err := decimal.NewFromString(limits[0])
+	if err != nil {
+		return nil, err
+	}
+	if ln == 1 {
+		return []decimal.Decimal{start}, nil
+	}
+	end, err := decimal.NewFromString(limits[ln-1])
+	if err != nil {
+		return nil, err
+	}
+	step := decimal.NewFromInt(1)
+	if ln == 3 {
+		step, err = decimal.NewFromString(limits[1])
+		if err != nil {
+			return nil, err
+		}
+		if !step.IsPositive() {
+			return nil, errors.NewValidationErr(fmt.Sprintf("Range '%s' step must be positive", expr))
+		}
+	}
+	values := make([]decimal.Decimal, 0)
+	for curr := start; curr.LessThanOrEqual(end); curr = curr.Add(step) {
+		values = append(values, curr)
+	}
+	return values, nil
+}
+
+//NewRangeSplitter creates splitter of ranged parameters; filter is optional and may be nil
+func NewRangeSplitter(specs []stmodel.ParamSpec, ranged []string, filter RangeFilter, logger *zap.SugaredLogger) stmodel.ParamSplitter {
+	return &RangeSplitter{
+		specs:  specs,
+		ranged: ranged,
+		filter: filter,
+		logger: logger,
+	}
+}
@@ -0,0 +1,39 @@
+package stbase
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	values, err := ParseRange("1 : 0.5 : 2")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(values))
+	assert.True(t, values[1].Equal(decimal.NewFromFloat(1.5)))
+
+	values, err = ParseRange("3:5")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(values))
+
+	_, err = ParseRange("1:2:3:4")
+	assert.NotNil(t, err)
+	_, err = ParseRange("1:0:3")
+	assert.NotNil(t, err)
+}
+
+func TestRangeSplitter_defaultsAndRequired(t *testing.T) {
+	specs := []stmodel.ParamSpec{
+		{Name: "a", Type: stmodel.IntParam, Required: true},
+		{Name: "b", Type: stmodel.IntParam, Default: "5"},
+	}
+	splitter := NewRangeSplitter(specs, []string{"a", "b"}, nil, zap.NewExample().Sugar())
+	split, err := splitter.ParseAndSplit(map[string]string{"a": "1:2"})
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]string{{"a": "1", "b": "5"}, {"a": "2", "b": "5"}}, split)
+
+	_, err = splitter.ParseAndSplit(map[string]string{"b": "1:2"})
+	assert.NotNil(t, err)
+}
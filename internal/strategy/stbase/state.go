@@ -0,0 +1,91 @@
+package stbase
+
+import (
+	"encoding/json"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+//SetInstrumentsState serializes instrument amounts and buy prices to algorithm context parameter
+func SetInstrumentsState(algo *entity.Algorithm, instrAmount map[string]int64, buyPrice map[string]decimal.Decimal) error {
+	instruments := make([]*dto.InstrumentInfo, 0, len(instrAmount))
+	for figi, amount := range instrAmount {
+		info := dto.InstrumentInfo{
+			Figi:   figi,
+			Amount: amount,
+		}
+		if price, ok := buyPrice[figi]; ok {
+			info.BuyPosPrice = price
+		}
+		instruments = append(instruments, &info)
+	}
+	res, err := json.Marshal(dto.InstrumentsInfo{Instruments: instruments})
+	if err != nil {
+		return err
+	}
+	algo.SetCtxParam(dto.InstrAmountField, string(res))
+	return nil
+}
+
+//GetInstrumentsState deserializes instrument amounts and buy prices from algorithm context,
+//returns empty maps if context has no instruments state
+func GetInstrumentsState(confCtx map[string]string) (map[string]int64, map[string]decimal.Decimal, error) {
+	instrAmount := make(map[string]int64)
+	buyPrice := make(map[string]decimal.Decimal)
+	instrData, ok := confCtx[dto.InstrAmountField]
+	if !ok {
+		return instrAmount, buyPrice, nil
+	}
+	var instruments dto.InstrumentsInfo
+	if err := json.Unmarshal([]byte(instrData), &instruments); err != nil {
+		return nil, nil, err
+	}
+	for _, instr := range instruments.Instruments {
+		instrAmount[instr.Figi] = instr.Amount
+		if !instr.BuyPosPrice.IsZero() {
+			buyPrice[instr.Figi] = instr.BuyPosPrice
+		}
+	}
+	return instrAmount, buyPrice, nil
+}
+
+//UpdatePositionPrice updates price of position by completed action and amounts of instrument held before and after it.
+//Keeps the least profitable price of consecutive orders: maximum buy price of long position and minimum sell price of short one.
+//Price is kept when position is partially closed, dropped when position is closed and replaced when position is reversed
+func UpdatePositionPrice(posPrice map[string]decimal.Decimal, before int64, after int64, action *entity.Action) {
+	figi := action.InstrFigi
+	price, ok := posPrice[figi]
+	switch {
+	case after == 0:
+		//Position closed - drops price because the deal has already been completed
+		delete(posPrice, figi)
+	case before > 0 && after < 0, before < 0 && after > 0:
+		//Position reversed - the rest of order opened new position
+		posPrice[figi] = action.PositionPrice
+	case before > 0 && after < before, before < 0 && after > before:
+		//Position partially closed - remaining lots keep their price
+	case !ok:
+		posPrice[figi] = action.PositionPrice
+	case action.Direction == entity.Buy:
+		posPrice[figi] = decimal.Max(price, action.PositionPrice)
+	default:
+		posPrice[figi] = decimal.Min(price, action.PositionPrice)
+	}
+}
+
+//PersistState saves algorithm context parameters with the action which changed algorithm state.
+//Does nothing when repository is nil (history algorithms)
+func PersistState(algRep repository.AlgoRepository, algo *entity.Algorithm, action *entity.Action, logger *zap.SugaredLogger) {
+	if algRep == nil {
+		return
+	}
+	for _, param := range algo.CtxParams {
+		param.AlgorithmID = algo.ID
+	}
+	if err := algRep.SaveState(action, algo.CtxParams); err != nil {
+		logger.Errorf("Error while saving state of algorithm %d: %s", algo.ID, err)
+	}
+}
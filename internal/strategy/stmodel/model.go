@@ -3,7 +3,9 @@ package stmodel
 import (
 	"context"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/sizing"
 	"github.com/shopspring/decimal"
+	"time"
 )
 
 //Algorithm is a general interface of any trading logic
@@ -14,7 +16,7 @@ import (
 //Action for sending from algorithm must be populated only partially
 //some parameters populated and persisted by Trader (see domain.Action documentation)
 type Algorithm interface {
-	//Configure is to configure Algorithm after restoring it state and data from db etc. (Currently not implemented)
+	//Configure is to configure Algorithm after restoring it state and data from db etc.
 	Configure(ctx []*entity.CtxParam) error
 	//Subscribe to algorithm and retrieve subscription to interact with algorithm
 	Subscribe() (*Subscription, error)
@@ -46,14 +48,19 @@ type ActionResp struct {
 
 //ActionReq represents common algorithm request model
 type ActionReq struct {
-	Action *entity.Action
-	Limits []*entity.MoneyLimit
+	Action    *entity.Action
+	Limits    []*entity.MoneyLimit
+	LimitPart decimal.Decimal //Optional part of currency limit to spend on buy in (0, 1] range; zero means whole limit
 }
 
 //GetCurrLimit simplifies retrieving limit by required currency from limit slice
+//If limit part is set - returns only this part of the limit
 func (req ActionReq) GetCurrLimit(currency string) decimal.Decimal {
 	for _, limit := range req.Limits {
 		if currency == limit.Currency {
+			if req.LimitPart.IsPositive() && req.LimitPart.LessThan(decimal.NewFromInt(1)) {
+				return limit.Amount.Mul(req.LimitPart)
+			}
 			return limit.Amount
 		}
 	}
@@ -62,7 +69,23 @@ func (req ActionReq) GetCurrLimit(currency string) decimal.Decimal {
 
 //Subscription represents common subscription object representing Algorithm/trade.Trader interaction mechanisms
 type Subscription struct {
-	AlgoID uint               //Subscribing algorithm identity
-	AChan  <-chan *ActionReq  //Algorithm -> trade.Trader channel - to create order requests
-	RChan  chan<- *ActionResp //trade.Trader -> Algorithm channel - to retrieve order result responses
+	AlgoID uint                 //Subscribing algorithm identity
+	AChan  <-chan *ActionReq    //Algorithm -> trade.Trader channel - to create order requests
+	RChan  chan<- *ActionResp   //trade.Trader -> Algorithm channel - to retrieve order result responses
+	TChan  <-chan time.Time     //Optional Algorithm -> trade.Trader channel with time of processed data - history trader uses it as simulation clock
+	Sizing *sizing.Policy       //Optional buy order sizing policy of algorithm, whole limit is spent on buy when not set
+	Limits []*entity.MoneyLimit //Optional configured money limits of algorithm - trader tracks algorithm budget across orders by them
+}
+
+//GetBudgetLimit returns configured money limit of algorithm by currency and false if budget is not tracked
+func (sub *Subscription) GetBudgetLimit(currency string) (decimal.Decimal, bool) {
+	if sub.Limits == nil {
+		return decimal.Zero, false
+	}
+	for _, limit := range sub.Limits {
+		if currency == limit.Currency {
+			return limit.Amount, true
+		}
+	}
+	return decimal.Zero, true
 }
@@ -0,0 +1,106 @@
+package stmodel
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+	"strconv"
+)
+
+type ParamType string
+
+const (
+	IntParam     ParamType = "int"
+	DecimalParam ParamType = "decimal"
+	StringParam  ParamType = "string"
+	BoolParam    ParamType = "bool"
+)
+
+//ParamSpec describes single algorithm parameter - its type, default value and limits
+type ParamSpec struct {
+	Name        string           //Parameter key in algorithm params map
+	Type        ParamType        //Type of parameter value
+	Description string           //Human-readable description of parameter
+	Default     string           //Default value, used when parameter not set; empty means no default
+	Required    bool             //If true - parameter must be set or have default value
+	Min         *decimal.Decimal //Optional minimal value for numeric parameters
+	Max         *decimal.Decimal //Optional maximal value for numeric parameters
+}
+
+//Validate checks that value has required type and fits limits
+func (ps *ParamSpec) Validate(value string) error {
+	var num decimal.Decimal
+	switch ps.Type {
+	case IntParam:
+		intVal, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be integer, got '%s'", ps.Name, value))
+		}
+		num = decimal.NewFromInt(intVal)
+	case DecimalParam:
+		decVal, err := decimal.NewFromString(value)
+		if err != nil {
+			return errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be decimal, got '%s'", ps.Name, value))
+		}
+		num = decVal
+	case BoolParam:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be boolean, got '%s'", ps.Name, value))
+		}
+		return nil
+	default:
+		return nil
+	}
+	if ps.Min != nil && num.LessThan(*ps.Min) {
+		return errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be not less than %s, got %s", ps.Name, ps.Min, value))
+	}
+	if ps.Max != nil && num.GreaterThan(*ps.Max) {
+		return errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be not greater than %s, got %s", ps.Name, ps.Max, value))
+	}
+	return nil
+}
+
+func (ps *ParamSpec) ToDto() *dto.ParamSpecResponse {
+	res := dto.ParamSpecResponse{
+		Name:        ps.Name,
+		Type:        string(ps.Type),
+		Description: ps.Description,
+		Default:     ps.Default,
+		Required:    ps.Required,
+	}
+	if ps.Min != nil {
+		res.Min = ps.Min.String()
+	}
+	if ps.Max != nil {
+		res.Max = ps.Max.String()
+	}
+	return &res
+}
+
+//ApplyParamSpecs populates missing parameters with default values and validates all described parameters.
+//Parameters not described in specs are kept as is
+func ApplyParamSpecs(specs []ParamSpec, params map[string]string) error {
+	for i := range specs {
+		spec := &specs[i]
+		value, ok := params[spec.Name]
+		if !ok {
+			if spec.Default != "" {
+				params[spec.Name] = spec.Default
+			} else if spec.Required {
+				return errors.NewValidationErr(fmt.Sprintf("Required parameter '%s' not set", spec.Name))
+			}
+			continue
+		}
+		if err := spec.Validate(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//DecLimit is auxiliary to define parameter limits in specs
+func DecLimit(value int64) *decimal.Decimal {
+	res := decimal.NewFromInt(value)
+	return &res
+}
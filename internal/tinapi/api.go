@@ -21,6 +21,7 @@ type Api interface {
 	GetAllShares(ctx context.Context) (*dtotapi.SharesResponse, error)
 	GetInstrumentInfo(req *dtotapi.InstrumentRequest, ctx context.Context) (*dtotapi.InstrumentResponse, error)
 	GetLastPrices(req *dtotapi.LastPricesRequest, ctx context.Context) (*dtotapi.LastPricesResponse, error)
+	GetTradingSchedules(req *dtotapi.TradingSchedulesRequest, ctx context.Context) (*dtotapi.TradingSchedulesResponse, error)
 	GetOrderStream(accounts []string, ctx context.Context) (investapi.OrdersStreamService_TradesStreamClient, error)
 
 	PostSandboxOrder(req *dtotapi.PostOrderRequest, ctx context.Context) (*dtotapi.PostOrderResponse, error)
@@ -35,8 +36,17 @@ type Api interface {
 	GetSandboxPositions(req *dtotapi.PositionsRequest, ctx context.Context) (*dtotapi.PositionsResponse, error)
 	GetProdPositions(req *dtotapi.PositionsRequest, ctx context.Context) (*dtotapi.PositionsResponse, error)
 
+	GetSandboxOperations(req *dtotapi.OperationsRequest, ctx context.Context) (*dtotapi.OperationsResponse, error)
+	GetProdOperations(req *dtotapi.OperationsRequest, ctx context.Context) (*dtotapi.OperationsResponse, error)
+
 	GetSandboxAccounts(ctx context.Context) (*dtotapi.AccountsResponse, error)
 	GetProdAccounts(ctx context.Context) (*dtotapi.AccountsResponse, error)
+
+	GetProdMarginAttributes(req *dtotapi.MarginAttributesRequest, ctx context.Context) (*dtotapi.MarginAttributesResponse, error)
+
+	PostProdStopOrder(req *dtotapi.PostStopOrderRequest, ctx context.Context) (*dtotapi.PostStopOrderResponse, error)
+	GetProdStopOrders(req *dtotapi.StopOrdersRequest, ctx context.Context) (*dtotapi.StopOrdersResponse, error)
+	CancelProdStopOrder(req *dtotapi.CancelStopOrderRequest, ctx context.Context) (*dtotapi.CancelStopOrderResponse, error)
 }
 
 type DefaultTinApi struct {
@@ -48,6 +58,7 @@ type DefaultTinApi struct {
 	operationsCl  investapi.OperationsServiceClient
 	orderStCl     investapi.OrdersStreamServiceClient
 	usersCl       investapi.UsersServiceClient
+	stopOrderCl   investapi.StopOrdersServiceClient
 	logger        *zap.SugaredLogger
 }
 
@@ -61,6 +72,7 @@ func NewTinApi(logger *zap.SugaredLogger) Api {
 		investapi.NewOperationsServiceClient(grpc.GetClient()),
 		investapi.NewOrdersStreamServiceClient(grpc.GetClient()),
 		investapi.NewUsersServiceClient(grpc.GetClient()),
+		investapi.NewStopOrdersServiceClient(grpc.GetClient()),
 		logger,
 	}
 }
@@ -134,6 +146,15 @@ func (t *DefaultTinApi) GetLastPrices(req *dtotapi.LastPricesRequest, ctx contex
 	return dtotapi.LastPricesResponseToDto(prices), nil
 }
 
+func (t *DefaultTinApi) GetTradingSchedules(req *dtotapi.TradingSchedulesRequest, ctx context.Context) (*dtotapi.TradingSchedulesResponse, error) {
+	ctxA := contextWithAuth(ctx)
+	schedules, err := t.instrCl.TradingSchedules(ctxA, req.ToTinApi())
+	if err != nil {
+		return nil, err
+	}
+	return dtotapi.TradingSchedulesResponseToDto(schedules), nil
+}
+
 func (t *DefaultTinApi) PostSandboxOrder(req *dtotapi.PostOrderRequest, ctx context.Context) (*dtotapi.PostOrderResponse, error) {
 	ctxA := contextWithAuth(ctx)
 	log.Println("Post order request:", req.ToTinApi())
@@ -208,6 +229,24 @@ func (t *DefaultTinApi) GetProdPositions(req *dtotapi.PositionsRequest, ctx cont
 	return dtotapi.PositionsResponseToDto(positions), nil
 }
 
+func (t *DefaultTinApi) GetSandboxOperations(req *dtotapi.OperationsRequest, ctx context.Context) (*dtotapi.OperationsResponse, error) {
+	ctxA := contextWithAuth(ctx)
+	operations, err := t.sandboxCl.GetSandboxOperations(ctxA, req.ToTinApi())
+	if err != nil {
+		return nil, err
+	}
+	return dtotapi.OperationsResponseToDto(operations), nil
+}
+
+func (t *DefaultTinApi) GetProdOperations(req *dtotapi.OperationsRequest, ctx context.Context) (*dtotapi.OperationsResponse, error) {
+	ctxA := contextWithAuth(ctx)
+	operations, err := t.operationsCl.GetOperations(ctxA, req.ToTinApi())
+	if err != nil {
+		return nil, err
+	}
+	return dtotapi.OperationsResponseToDto(operations), nil
+}
+
 func (t *DefaultTinApi) GetOrderStream(accounts []string, ctx context.Context) (investapi.OrdersStreamService_TradesStreamClient, error) {
 	ctxA := contextWithAuth(ctx)
 	req := investapi.TradesStreamRequest{Accounts: accounts}
@@ -231,3 +270,40 @@ func (t *DefaultTinApi) GetProdAccounts(ctx context.Context) (*dtotapi.AccountsR
 	}
 	return dtotapi.AccountsResponseToDto(accounts), nil
 }
+
+func (t *DefaultTinApi) GetProdMarginAttributes(req *dtotapi.MarginAttributesRequest, ctx context.Context) (*dtotapi.MarginAttributesResponse, error) {
+	ctxA := contextWithAuth(ctx)
+	attrs, err := t.usersCl.GetMarginAttributes(ctxA, req.ToTinApi())
+	if err != nil {
+		return nil, err
+	}
+	return dtotapi.MarginAttributesResponseToDto(attrs), nil
+}
+
+func (t *DefaultTinApi) PostProdStopOrder(req *dtotapi.PostStopOrderRequest, ctx context.Context) (*dtotapi.PostStopOrderResponse, error) {
+	ctxA := contextWithAuth(ctx)
+	t.logger.Infof("Posting prod stop order %+v", req.ToTinApi())
+	order, err := t.stopOrderCl.PostStopOrder(ctxA, req.ToTinApi())
+	if err != nil {
+		return nil, err
+	}
+	return dtotapi.PostStopOrderResponseToDto(order), nil
+}
+
+func (t *DefaultTinApi) GetProdStopOrders(req *dtotapi.StopOrdersRequest, ctx context.Context) (*dtotapi.StopOrdersResponse, error) {
+	ctxA := contextWithAuth(ctx)
+	orders, err := t.stopOrderCl.GetStopOrders(ctxA, req.ToTinApi())
+	if err != nil {
+		return nil, err
+	}
+	return dtotapi.StopOrdersResponseToDto(orders), nil
+}
+
+func (t *DefaultTinApi) CancelProdStopOrder(req *dtotapi.CancelStopOrderRequest, ctx context.Context) (*dtotapi.CancelStopOrderResponse, error) {
+	ctxA := contextWithAuth(ctx)
+	resp, err := t.stopOrderCl.CancelStopOrder(ctxA, req.ToTinApi())
+	if err != nil {
+		return nil, err
+	}
+	return dtotapi.CancelStopOrderResponseToDto(resp), nil
+}
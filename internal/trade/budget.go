@@ -0,0 +1,132 @@
+package trade
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"sort"
+	"sync"
+)
+
+//BudgetManager tracks money of algorithms across orders: buy orders reserve money on post,
+//reservation is released when order completes and executed amount is spent, sells return money to algorithm
+type BudgetManager interface {
+	//Available returns money of algorithm available for new buy orders. Budget is created by configured limit on first use,
+	//when configured limit is changed budget cash is changed by the difference
+	Available(algoId uint, currency string, limit decimal.Decimal) decimal.Decimal
+	//Reserve reserves money of posted buy order
+	Reserve(algoId uint, currency string, amount decimal.Decimal)
+	//Settle releases money reserved by completed order and applies its executed amount to algorithm cash
+	Settle(action *entity.Action, reserved decimal.Decimal)
+	//GetBudgets returns budgets of algorithm in all currencies
+	GetBudgets(algoId uint) []*entity.AlgoBudget
+}
+
+type DefaultBudgetManager struct {
+	budgetRep repository.BudgetRepository
+	budgets   map[uint]map[string]*entity.AlgoBudget //Cache of algorithm budgets by currency
+	mu        sync.Mutex
+	logger    *zap.SugaredLogger
+}
+
+func (bm *DefaultBudgetManager) Available(algoId uint, currency string, limit decimal.Decimal) decimal.Decimal {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	budget := bm.getBudget(algoId, currency)
+	if !budget.Limit.Equal(limit) {
+		bm.logger.Infof("Limit of algorithm %d in %s changed from %s to %s, updating budget", algoId, currency, budget.Limit, limit)
+		budget.Cash = budget.Cash.Add(limit.Sub(budget.Limit))
+		budget.Limit = limit
+		bm.save(budget)
+	}
+	return budget.Available()
+}
+
+func (bm *DefaultBudgetManager) Reserve(algoId uint, currency string, amount decimal.Decimal) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	budget := bm.getBudget(algoId, currency)
+	budget.Reserved = budget.Reserved.Add(amount)
+	bm.save(budget)
+}
+
+func (bm *DefaultBudgetManager) Settle(action *entity.Action, reserved decimal.Decimal) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	budget := bm.getBudget(action.AlgorithmID, action.Currency)
+	executed := decimal.Zero
+	if action.LotsExecuted > 0 || action.Status == entity.Success {
+		executed = action.TotalPrice
+	}
+	if action.Direction == entity.Buy {
+		budget.Reserved = budget.Reserved.Sub(reserved)
+		if budget.Reserved.IsNegative() {
+			budget.Reserved = decimal.Zero
+		}
+		budget.Cash = budget.Cash.Sub(executed)
+	} else {
+		budget.Cash = budget.Cash.Add(executed)
+	}
+	bm.logger.Infof("Budget of algorithm %d in %s updated by action %d: cash %s, reserved %s",
+		action.AlgorithmID, action.Currency, action.ID, budget.Cash, budget.Reserved)
+	bm.save(budget)
+}
+
+func (bm *DefaultBudgetManager) GetBudgets(algoId uint) []*entity.AlgoBudget {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	res := make([]*entity.AlgoBudget, 0)
+	for _, budget := range bm.loadBudgets(algoId) {
+		budgetCopy := *budget
+		res = append(res, &budgetCopy)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Currency < res[j].Currency
+	})
+	return res
+}
+
+//getBudget returns cached budget of algorithm by currency, creates empty one if not exists. Must be called under lock
+func (bm *DefaultBudgetManager) getBudget(algoId uint, currency string) *entity.AlgoBudget {
+	budgets := bm.loadBudgets(algoId)
+	budget, ok := budgets[currency]
+	if !ok {
+		budget = &entity.AlgoBudget{AlgorithmID: algoId, Currency: currency}
+		budgets[currency] = budget
+	}
+	return budget
+}
+
+//loadBudgets returns cached budgets of algorithm, loads them from db on first access. Must be called under lock
+func (bm *DefaultBudgetManager) loadBudgets(algoId uint) map[string]*entity.AlgoBudget {
+	if budgets, ok := bm.budgets[algoId]; ok {
+		return budgets
+	}
+	budgets := make(map[string]*entity.AlgoBudget)
+	stored, err := bm.budgetRep.FindByAlgorithm(algoId)
+	if err != nil {
+		//Not cached - loading will be retried on the next access
+		bm.logger.Errorf("Error loading budgets of algorithm %d: %s", algoId, err)
+		return budgets
+	}
+	for _, budget := range stored {
+		budgets[budget.Currency] = budget
+	}
+	bm.budgets[algoId] = budgets
+	return budgets
+}
+
+func (bm *DefaultBudgetManager) save(budget *entity.AlgoBudget) {
+	if err := bm.budgetRep.Save(budget); err != nil {
+		bm.logger.Errorf("Error saving budget %+v: %s", budget, err)
+	}
+}
+
+func NewBudgetManager(budgetRep repository.BudgetRepository, logger *zap.SugaredLogger) BudgetManager {
+	return &DefaultBudgetManager{
+		budgetRep: budgetRep,
+		budgets:   make(map[uint]map[string]*entity.AlgoBudget),
+		logger:    logger,
+	}
+}
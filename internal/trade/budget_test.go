@@ -0,0 +1,57 @@
+package trade
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+//budgetRepStub keeps saved budgets in memory
+type budgetRepStub struct {
+	saved map[string]entity.AlgoBudget
+}
+
+func (r *budgetRepStub) Save(budget *entity.AlgoBudget) error {
+	r.saved[budget.Currency] = *budget
+	return nil
+}
+
+func (r *budgetRepStub) FindByAlgorithm(algoId uint) ([]*entity.AlgoBudget, error) {
+	res := make([]*entity.AlgoBudget, 0)
+	for _, budget := range r.saved {
+		budgetCopy := budget
+		res = append(res, &budgetCopy)
+	}
+	return res, nil
+}
+
+func TestBudgetManager_ordersLifecycle(t *testing.T) {
+	rep := &budgetRepStub{saved: make(map[string]entity.AlgoBudget)}
+	bm := NewBudgetManager(rep, zap.NewNop().Sugar())
+	limit := decimal.NewFromInt(1000)
+
+	assert.True(t, bm.Available(1, "rub", limit).Equal(limit))
+	//Two buys posted, the first is filled by 390 with commission, the second is canceled without execution
+	bm.Reserve(1, "rub", decimal.NewFromInt(400))
+	bm.Reserve(1, "rub", decimal.NewFromInt(500))
+	assert.True(t, bm.Available(1, "rub", limit).Equal(decimal.NewFromInt(100)))
+	bm.Settle(&entity.Action{AlgorithmID: 1, Currency: "rub", Direction: entity.Buy, Status: entity.Success,
+		LotsExecuted: 4, TotalPrice: decimal.NewFromInt(390)}, decimal.NewFromInt(400))
+	bm.Settle(&entity.Action{AlgorithmID: 1, Currency: "rub", Direction: entity.Buy, Status: entity.Canceled,
+		TotalPrice: decimal.NewFromInt(500)}, decimal.NewFromInt(500))
+	assert.True(t, bm.Available(1, "rub", limit).Equal(decimal.NewFromInt(610)))
+	//Sell fill returns money to algorithm
+	bm.Settle(&entity.Action{AlgorithmID: 1, Currency: "rub", Direction: entity.Sell, Status: entity.Success,
+		LotsExecuted: 4, TotalPrice: decimal.NewFromInt(450)}, decimal.Zero)
+	assert.True(t, bm.Available(1, "rub", limit).Equal(decimal.NewFromInt(1060)))
+	//Increased limit adds difference to cash
+	assert.True(t, bm.Available(1, "rub", decimal.NewFromInt(1500)).Equal(decimal.NewFromInt(1560)))
+
+	//Budget is restored from db by new manager
+	restored := NewBudgetManager(rep, zap.NewNop().Sugar()).GetBudgets(1)
+	assert.Equal(t, 1, len(restored))
+	assert.True(t, restored[0].Cash.Equal(decimal.NewFromInt(1560)))
+	assert.True(t, restored[0].Reserved.IsZero())
+}
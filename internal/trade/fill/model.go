@@ -0,0 +1,103 @@
+package fill
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+)
+
+//Type defines how history trader fills orders
+type Type string
+
+const (
+	InstantFill Type = "instant" //Orders are filled at once by interpolated close price, without commission
+	CandleFill  Type = "candle"  //Orders are filled by following candles: market by open with slippage, limited when price range crosses requested price
+)
+
+//Fill parameters common for all strategies, processed by history trader; parameter specs are added to strategies by registry
+const (
+	FillType     string = "fill_model"
+	FillSlippage string = "fill_slippage"
+	Commission   string = "order_commission" //The same parameter is used by strategies to take commission into account
+)
+
+//DefaultCommission is a commission of single order in percents used when algorithm doesn't set it
+var DefaultCommission = decimal.NewFromFloat(0.04)
+
+var hundred = decimal.NewFromInt(100)
+
+//Model simulates order execution by history candles
+type Model struct {
+	Type       Type
+	Slippage   decimal.Decimal //Part of price lost by market order execution
+	Commission decimal.Decimal //Part of order amount paid to broker
+}
+
+//IsCandle checks is order filled by following candles, nil model fills orders at once
+func (m *Model) IsCandle() bool {
+	return m != nil && m.Type == CandleFill
+}
+
+//MarketPrice returns execution price of market order by price of the candle, price is worsened by slippage
+func (m *Model) MarketPrice(price decimal.Decimal, buy bool) decimal.Decimal {
+	if !m.IsCandle() {
+		return price
+	}
+	if buy {
+		return price.Mul(decimal.NewFromInt(1).Add(m.Slippage))
+	}
+	return price.Mul(decimal.NewFromInt(1).Sub(m.Slippage))
+}
+
+//LimitPrice returns execution price of limited order by candle and false if candle range doesn't reach requested price.
+//Candle opened by better price than requested fills order by open price
+func (m *Model) LimitPrice(reqPrice decimal.Decimal, buy bool, open decimal.Decimal, high decimal.Decimal, low decimal.Decimal) (decimal.Decimal, bool) {
+	if buy {
+		if low.GreaterThan(reqPrice) {
+			return decimal.Zero, false
+		}
+		if open.IsPositive() && open.LessThan(reqPrice) {
+			return open, true
+		}
+		return reqPrice, true
+	}
+	if high.LessThan(reqPrice) {
+		return decimal.Zero, false
+	}
+	if open.GreaterThan(reqPrice) {
+		return open, true
+	}
+	return reqPrice, true
+}
+
+//CommissionOf returns commission of order with money amount, zero for instant fill
+func (m *Model) CommissionOf(amount decimal.Decimal) decimal.Decimal {
+	if !m.IsCandle() {
+		return decimal.Zero
+	}
+	return amount.Mul(m.Commission)
+}
+
+//FromParams creates fill model from algorithm parameters, returns validation error on unknown fill type or negative values
+func FromParams(paramMap map[string]string) (*Model, error) {
+	model := Model{Type: InstantFill, Commission: DefaultCommission.Div(hundred)}
+	if val, ok := paramMap[FillType]; ok && val != "" {
+		model.Type = Type(val)
+	}
+	if model.Type != InstantFill && model.Type != CandleFill {
+		return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be one of %s, %s; got '%s'",
+			FillType, InstantFill, CandleFill, model.Type))
+	}
+	for key, target := range map[string]*decimal.Decimal{FillSlippage: &model.Slippage, Commission: &model.Commission} {
+		val, ok := paramMap[key]
+		if !ok || val == "" {
+			continue
+		}
+		dec, err := decimal.NewFromString(val)
+		if err != nil || dec.IsNegative() {
+			return nil, errors.NewValidationErr(fmt.Sprintf("Parameter '%s' must be non-negative number of percents", key))
+		}
+		*target = dec.Div(hundred)
+	}
+	return &model, nil
+}
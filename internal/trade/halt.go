@@ -0,0 +1,183 @@
+package trade
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/shopspring/decimal"
+	"strings"
+	"time"
+)
+
+//CancelOrders cancels posted orders of algorithm and stops their monitoring, algorithm is not notified.
+//Returns ids of canceled orders and error listing orders failed to cancel
+func (t *BaseTrader) CancelOrders(algoId uint) ([]string, error) {
+	canceled := make([]string, 0)
+	failed := make([]string, 0)
+	for _, entry := range t.orders.GetSlice() {
+		if entry.Value.AlgorithmID != algoId {
+			continue
+		}
+		ok, err := t.cancelOrder(entry.Key)
+		if err != nil {
+			t.logger.Errorf("Error while canceling order %s of algorithm %d: %s", entry.Key, algoId, err)
+			failed = append(failed, entry.Key)
+			continue
+		}
+		if ok {
+			canceled = append(canceled, entry.Key)
+		}
+	}
+	if len(failed) > 0 {
+		return canceled, errors.NewUnexpectedError("failed to cancel orders: " + strings.Join(failed, ", "))
+	}
+	return canceled, nil
+}
+
+//cancelOrder cancels monitored order and saves its final state, returns false if order is already completed
+func (t *BaseTrader) cancelOrder(orderId string) (bool, error) {
+	t.ordMu.Lock()
+	defer t.ordMu.Unlock()
+	action, ok := t.orders.Get(orderId)
+	if !ok {
+		return false, nil
+	}
+	req := dtotapi.CancelOrderRequest{AccountId: action.AccountID, OrderId: orderId}
+	if _, err := t.tradeSrv.CancelOrder(&req, t.ctx); err != nil {
+		return false, err
+	}
+	t.orders.Delete(orderId)
+	reserved := t.reservedBy(action)
+	action.Status = entity.Canceled
+	action.Info = "Order was canceled by trading halt"
+	stateReq := dtotapi.OrderStateRequest{AccountId: action.AccountID, OrderId: orderId}
+	if state, err := t.infoSrv.GetOrderState(&stateReq, t.ctx); err == nil {
+		t.applyExecution(action, state)
+	} else {
+		t.logger.Errorf("Error checking state of canceled order %s: %s", orderId, err)
+	}
+	if err := t.actionRep.Save(action); err != nil {
+		t.logger.Errorf("Error while updating action %+v: %s", action, err)
+	}
+	t.settleBudget(action, reserved)
+	return true, nil
+}
+
+//ClosePositions closes positions opened by algorithm orders with market orders. Closed amount is limited by account holdings,
+//so positions which are already closed outside the bot are skipped. Returns posted closing actions
+func (t *BaseTrader) ClosePositions(algoId uint, accountId string) ([]*entity.Action, error) {
+	lots, err := t.actionRep.FindExecutedLots(algoId)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := t.infoSrv.GetPositions(&dtotapi.PositionsRequest{AccountId: accountId}, t.ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*entity.Action, 0)
+	failed := make([]string, 0)
+	for figi, amount := range lots {
+		security := positions.GetInstrument(figi)
+		if amount == 0 || security == nil {
+			continue
+		}
+		instrInfo, err := t.infoSrv.GetInstrumentInfoByFigi(figi, t.ctx)
+		if err != nil {
+			t.logger.Errorf("Error while requesting instrument %s info: %s", figi, err)
+			failed = append(failed, figi)
+			continue
+		}
+		held := security.Balance / instrInfo.Lot
+		action := &entity.Action{
+			AlgorithmID: algoId,
+			AccountID:   accountId,
+			InstrFigi:   figi,
+			OrderType:   entity.Market,
+			Currency:    instrInfo.Currency,
+			Status:      entity.Created,
+			RetrievedAt: time.Now(),
+		}
+		switch {
+		case amount > 0 && held > 0:
+			action.Direction = entity.Sell
+			action.LotAmount = minLots(amount, held)
+			t.cancelStopOrders(algoId, figi)
+		case amount < 0 && held < 0:
+			action.Direction = entity.Buy
+			action.LotAmount = minLots(-amount, -held)
+			t.cancelStopOrders(algoId, figi)
+		default:
+			continue
+		}
+		if err = t.postClosingOrder(action); err != nil {
+			t.logger.Errorf("Error while closing position %s of algorithm %d: %s", figi, algoId, err)
+			failed = append(failed, figi)
+		}
+		res = append(res, action)
+	}
+	if len(failed) > 0 {
+		return res, errors.NewUnexpectedError("failed to close positions: " + strings.Join(failed, ", "))
+	}
+	return res, nil
+}
+
+//postClosingOrder posts market order of closing action and saves its state right after posting
+func (t *BaseTrader) postClosingOrder(action *entity.Action) error {
+	direction := dtotapi.OrderDirectionSell
+	if action.Direction == entity.Buy {
+		direction = dtotapi.OrderDirectionBuy
+	}
+	req := dtotapi.PostOrderRequest{
+		Figi:      action.InstrFigi,
+		PosNum:    action.LotAmount,
+		Direction: direction,
+		AccountId: action.AccountID,
+		OrderId:   uuid.New().String(),
+		OrderType: dtotapi.OrderTypeMarket,
+	}
+	order, err := t.tradeSrv.PostOrder(&req, t.ctx)
+	if err != nil {
+		t.saveActionWithStatus(action, entity.Failed, "Error while posting closing order")
+		return err
+	}
+	action.OrderId = order.OrderId
+	action.Status = entity.Posted
+	action.Info = "Position closing order posted by trading halt"
+	//Market order is usually filled at once - final state is saved without further monitoring
+	state, err := t.infoSrv.GetOrderState(&dtotapi.OrderStateRequest{AccountId: action.AccountID, OrderId: order.OrderId}, t.ctx)
+	if err == nil {
+		switch state.ExecStatus {
+		case dtotapi.ExecutionReportStatusFill:
+			action.Status = entity.Success
+			action.Info = "Position closed by trading halt"
+			action.TotalPrice = state.TotalPrice.Value
+			action.PositionPrice = state.AvrPrice.Value
+			action.LotsExecuted = state.LotsExec
+		case dtotapi.ExecutionReportStatusRejected:
+			action.Status = entity.Failed
+			action.Info = "Position closing order was rejected"
+		default:
+			t.applyExecution(action, state)
+		}
+	} else {
+		t.logger.Errorf("Error checking state of closing order %s: %s", order.OrderId, err)
+	}
+	if err = t.actionRep.Save(action); err != nil {
+		t.logger.Errorf("Error while saving action %+v: %s", action, err)
+	}
+	//Closing order is not monitored and reserves nothing - executed amount is applied at once
+	t.settleBudget(action, decimal.Zero)
+	if action.Status == entity.Failed {
+		return errors.NewUnexpectedError(fmt.Sprintf("closing order %s rejected", order.OrderId))
+	}
+	return nil
+}
+
+func minLots(a int64, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
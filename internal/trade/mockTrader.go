@@ -7,6 +7,7 @@ import (
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/fill"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/trmodel"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
@@ -19,7 +20,10 @@ type MockTrader struct {
 	statCh       chan dto.HistStatResponse
 	lots         map[string]int64        //number of positions per buy
 	figiCurrency map[string]string       //currency to instrument figi relation
+	shortEnabled map[string]bool         //instruments available for short selling
 	figiHist     map[string][]histRecord //history of each figi - to convenience interpolation
+	fill         *fill.Model             //Order fill simulation, orders are filled at once by close price when not set
+	fullEquity   bool                    //Keep equity by every history record in report
 	logger       *zap.SugaredLogger
 	ctx          context.Context
 }
@@ -28,6 +32,9 @@ type histRecord struct {
 	Time  time.Time
 	Figi  string
 	Price decimal.Decimal
+	Open  decimal.Decimal
+	High  decimal.Decimal
+	Low   decimal.Decimal
 }
 
 //auxiliary to keep trader data information
@@ -35,8 +42,32 @@ type mockTraderData struct {
 	LastTime  time.Time
 	ResAmount map[string]decimal.Decimal
 	ResInstr  map[string]int64
+	Borrowed  map[string]int64 //Lots borrowed by short sells and not covered yet
 	BuyOper   uint
 	SellOper  uint
+	Expired   uint            //Orders expired unfilled
+	Resting   []*restingOrder //Limit orders waiting for price to reach requested one
+	Fills     []*fillRecord   //Executed orders to build report
+}
+
+//addFill records executed order to build report
+func (trDat *mockTraderData) addFill(action *entity.Action, opInfo trmodel.OpInfo, tm time.Time) {
+	lots := action.LotsExecuted
+	money := action.TotalPrice
+	if action.Direction == entity.Buy {
+		money = money.Neg()
+	} else {
+		lots = -lots
+	}
+	trDat.Fills = append(trDat.Fills, &fillRecord{Time: tm, Figi: action.InstrFigi, Currency: opInfo.Currency, Lots: lots,
+		Price: opInfo.PosPrice, Money: money})
+}
+
+//restingOrder is limit order posted not by current price - it is filled when price reaches requested one
+type restingOrder struct {
+	action  *entity.Action
+	opInfo  trmodel.OpInfo
+	checked time.Time //Candles up to this time are already checked by candle fill
 }
 
 func (t *MockTrader) Go(ctx context.Context) {
@@ -60,9 +91,14 @@ func (t *MockTrader) procBg() {
 		LastTime:  time.Time{},
 		ResAmount: make(map[string]decimal.Decimal),
 		ResInstr:  make(map[string]int64),
+		Borrowed:  make(map[string]int64),
 		BuyOper:   0,
 		SellOper:  0,
+		Resting:   make([]*restingOrder, 0),
+		Fills:     make([]*fillRecord, 0),
 	}
+	//Simulation clock is optional - without it all orders are filled immediately
+	tChan := t.sub.TChan
 
 OUT:
 	for {
@@ -70,6 +106,12 @@ OUT:
 		case <-t.ctx.Done():
 			t.logger.Info("Mock trader cancel request received...")
 			break OUT
+		case tm, ok := <-tChan:
+			if !ok {
+				tChan = nil
+				continue
+			}
+			t.procResting(tm, &trDat)
 		case act, ok := <-t.sub.AChan:
 			if !ok {
 				t.logger.Info("Incoming stream closed, stopping")
@@ -94,73 +136,324 @@ OUT:
 				t.sub.RChan <- t.getRespWithStatus(action, entity.Failed)
 				continue
 			}
+			//Market order triggered by stop is executed by the price crossed stop, not by the price of candle it was detected
+			if action.OrderType == entity.Market && action.TriggerPrice.IsPositive() {
+				opInfo.PosPrice = action.TriggerPrice
+			}
 			if opInfo.Lim.IsZero() || opInfo.PosInLot == 0 || opInfo.PosPrice.IsZero() {
 				t.logger.Warnf("Limit or lot price is zero; figi: %s; limit: %s; pos in lot: %d;lot price: %s",
 					action.InstrFigi, opInfo.Lim, opInfo.PosInLot, opInfo.PosPrice)
 				t.sub.RChan <- t.getRespWithStatus(action, entity.Failed)
 				continue
 			}
+			if t.fill.IsCandle() {
+				t.procCandleFill(action, opInfo, &trDat)
+				continue
+			}
+			if t.isResting(action, opInfo.PosPrice) {
+				t.logger.Debugf("Limit order waits for price %s, current price: %s", action.ReqPrice, opInfo.PosPrice)
+				trDat.Resting = append(trDat.Resting, &restingOrder{action: action, opInfo: opInfo})
+				continue
+			}
 			if action.Direction == entity.Buy {
-				t.procBuy(opInfo, action, &trDat)
+				t.procBuy(opInfo, action, action.RetrievedAt, &trDat)
 			} else {
-				t.procSell(opInfo, action, &trDat)
+				t.procSell(opInfo, action, action.RetrievedAt, &trDat)
 			}
 		}
 	}
 
 	t.logger.Info("Action channel closed, stopping mock trader...")
+	report := t.buildReport(trDat.Fills)
 	stat := t.calcMoneyStat(&trDat)
+	stat.Report = report
 	stat.SellOpNum = trDat.SellOper
 	stat.BuyOpNum = trDat.BuyOper
+	stat.ExpiredOpNum = trDat.Expired
 	t.statCh <- stat
 	close(t.sub.RChan)
 }
 
-func (t *MockTrader) procBuy(opInfo trmodel.OpInfo, action *entity.Action, trDat *mockTraderData) {
+func (t *MockTrader) procBuy(opInfo trmodel.OpInfo, action *entity.Action, tm time.Time, trDat *mockTraderData) {
 	lotPrice := decimal.NewFromInt(opInfo.PosInLot).Mul(opInfo.PosPrice)
-	if lotPrice.GreaterThan(opInfo.Lim) {
-		t.logger.Infof("Not enough money for figi %s; limit: %s; lot price: %s; one price: %s",
-			action.InstrFigi, opInfo.Lim, opInfo.PosPrice, lotPrice)
-		t.sub.RChan <- t.getRespWithStatus(action, entity.Failed)
-		return
+	var moneyAmount decimal.Decimal
+	var instrAmount int64
+	if action.LotAmount > 0 {
+		//Buy to cover short position is not restricted by the limit
+		instrAmount = action.LotAmount
+		moneyAmount = lotPrice.Mul(decimal.NewFromInt(instrAmount))
+	} else {
+		//The same budget restriction as in production trader - money spent by previous buys is not available
+		if budgetLim, tracked := t.sub.GetBudgetLimit(opInfo.Currency); tracked {
+			budget := budgetLim.Add(trDat.ResAmount[opInfo.Currency])
+			opInfo.Budget = &budget
+		}
+		if lotPrice.GreaterThan(opInfo.Lim) {
+			t.logger.Infof("Not enough money for figi %s; limit: %s; lot price: %s; one price: %s",
+				action.InstrFigi, opInfo.Lim, opInfo.PosPrice, lotPrice)
+			t.sub.RChan <- t.getRespWithStatus(action, entity.Failed)
+			return
+		}
+		//The same sizing policy as in production trader
+		instrAmount = capByBudget(t.sizeLots(opInfo, action.InstrFigi, action.RetrievedAt), lotPrice, opInfo.Budget)
+		if instrAmount == 0 {
+			t.logger.Infof("Sizing policy %+v gives no lots for figi %s; limit: %s; lot price: %s",
+				t.sub.Sizing, action.InstrFigi, opInfo.Lim, lotPrice)
+			t.sub.RChan <- t.getRespWithStatus(action, entity.Failed)
+			return
+		}
+		moneyAmount = lotPrice.Mul(decimal.NewFromInt(instrAmount))
 	}
-	lotNum := opInfo.Lim.Div(lotPrice).Floor()
-	moneyAmount := lotNum.Mul(lotPrice)
-	instrAmount := lotNum.IntPart()
 	trDat.ResInstr[action.InstrFigi] = trDat.ResInstr[action.InstrFigi] + instrAmount
+	//Bought lots return borrowed ones first
+	if trDat.ResInstr[action.InstrFigi] >= 0 {
+		delete(trDat.Borrowed, action.InstrFigi)
+	} else {
+		trDat.Borrowed[action.InstrFigi] = -trDat.ResInstr[action.InstrFigi]
+	}
+	moneyAmount = moneyAmount.Add(t.fill.CommissionOf(moneyAmount))
 	trDat.ResAmount[opInfo.Currency] = trDat.ResAmount[opInfo.Currency].Sub(moneyAmount)
 	action.TotalPrice = moneyAmount
 	action.LotAmount = instrAmount
 	action.PositionPrice = opInfo.PosPrice
 	action.LotsExecuted = instrAmount
+	trDat.addFill(action, opInfo, tm)
 	trDat.BuyOper += 1
 	t.sub.RChan <- t.getRespWithStatus(action, entity.Success)
 }
 
-func (t *MockTrader) procSell(opInfo trmodel.OpInfo, action *entity.Action, trDat *mockTraderData) {
-	if action.LotAmount == 0 {
-		t.logger.Info("LotAmount is 0 - nothing to sell")
+func (t *MockTrader) procSell(opInfo trmodel.OpInfo, action *entity.Action, tm time.Time, trDat *mockTraderData) {
+	if action.Short && !t.prepareShort(opInfo, action) {
 		t.sub.RChan <- t.getRespWithStatus(action, entity.Failed)
 		return
 	}
-	price, err := t.calcPrice(action.InstrFigi, action.RetrievedAt)
-	if err != nil {
-		t.logger.Error("Can't resolve price by figi, canceling operation...")
+	if action.LotAmount == 0 {
+		t.logger.Info("LotAmount is 0 - nothing to sell")
 		t.sub.RChan <- t.getRespWithStatus(action, entity.Failed)
 		return
 	}
-	moneyAmount := price.Mul(decimal.NewFromInt(action.LotAmount * opInfo.PosInLot)) //Money amount is a price multiplied by num of positions
+	moneyAmount := opInfo.PosPrice.Mul(decimal.NewFromInt(action.LotAmount * opInfo.PosInLot)) //Money amount is a price multiplied by num of positions
+	moneyAmount = moneyAmount.Sub(t.fill.CommissionOf(moneyAmount))
 	trDat.ResAmount[opInfo.Currency] = trDat.ResAmount[opInfo.Currency].Add(moneyAmount)
 	trDat.ResInstr[action.InstrFigi] = trDat.ResInstr[action.InstrFigi] - action.LotAmount
-	//Negative amount of instrument not allowed, means initial amount of instrument existed
-	if trDat.ResInstr[action.InstrFigi] < 0 {
-		trDat.ResInstr[action.InstrFigi] = 0
+	if action.Short {
+		//Sold lots not held are borrowed - negative amount is a debt to return by the end of simulation
+		if trDat.ResInstr[action.InstrFigi] < -trDat.Borrowed[action.InstrFigi] {
+			trDat.Borrowed[action.InstrFigi] = -trDat.ResInstr[action.InstrFigi]
+		}
+	} else if trDat.ResInstr[action.InstrFigi] < -trDat.Borrowed[action.InstrFigi] {
+		//Negative amount of instrument beyond borrowed not allowed, means initial amount of instrument existed
+		trDat.ResInstr[action.InstrFigi] = -trDat.Borrowed[action.InstrFigi]
 	}
 	action.TotalPrice = moneyAmount
+	action.LotsExecuted = action.LotAmount
+	action.PositionPrice = opInfo.PosPrice
+	trDat.addFill(action, opInfo, tm)
 	trDat.SellOper += 1
 	t.sub.RChan <- t.getRespWithStatus(action, entity.Success)
 }
 
+//prepareShort checks that instrument is available for short selling and calculates amount of short sell by limit if it's not requested
+func (t *MockTrader) prepareShort(opInfo trmodel.OpInfo, action *entity.Action) bool {
+	if !t.shortEnabled[action.InstrFigi] {
+		t.logger.Infof("Short selling of figi %s not available", action.InstrFigi)
+		return false
+	}
+	if action.LotAmount > 0 {
+		return true
+	}
+	lotPrice := decimal.NewFromInt(opInfo.PosInLot).Mul(opInfo.PosPrice)
+	if lotPrice.GreaterThan(opInfo.Lim) {
+		t.logger.Infof("Limit lower than short sell price for figi %s; limit: %s; lot price: %s", action.InstrFigi, opInfo.Lim, lotPrice)
+		return false
+	}
+	action.LotAmount = opInfo.Lim.Div(lotPrice).Floor().IntPart()
+	return true
+}
+
+//isResting checks if limit order can't be filled by current price and must wait for the price.
+//Orders are filled immediately when simulation clock is not provided by subscription
+func (t *MockTrader) isResting(action *entity.Action, price decimal.Decimal) bool {
+	if t.sub.TChan == nil || action.OrderType != entity.Limited || action.ReqPrice.IsZero() {
+		return false
+	}
+	if action.Direction == entity.Buy {
+		return action.ReqPrice.LessThan(price)
+	}
+	return action.ReqPrice.GreaterThan(price)
+}
+
+//procResting fills resting orders which requested price was reached by the time and cancels expired ones
+func (t *MockTrader) procResting(tm time.Time, trDat *mockTraderData) {
+	if tm.After(trDat.LastTime) {
+		trDat.LastTime = tm
+	}
+	remaining := make([]*restingOrder, 0, len(trDat.Resting))
+	for _, order := range trDat.Resting {
+		action := order.action
+		if t.fill.IsCandle() {
+			if !t.procRestingCandle(order, tm, trDat) {
+				remaining = append(remaining, order)
+			}
+			continue
+		}
+		if !action.ExpirationTime.IsZero() && tm.After(action.ExpirationTime) {
+			t.logger.Debugf("Resting order expired: %+v", action)
+			t.expire(action, trDat)
+			continue
+		}
+		price, err := t.calcPrice(action.InstrFigi, tm)
+		if err != nil {
+			t.logger.Errorf("Error while calculating figi price: %s", err)
+			remaining = append(remaining, order)
+			continue
+		}
+		if action.Direction == entity.Buy && price.LessThanOrEqual(action.ReqPrice) {
+			order.opInfo.PosPrice = action.ReqPrice
+			t.procBuy(order.opInfo, action, tm, trDat)
+		} else if action.Direction == entity.Sell && price.GreaterThanOrEqual(action.ReqPrice) {
+			order.opInfo.PosPrice = action.ReqPrice
+			t.procSell(order.opInfo, action, tm, trDat)
+		} else {
+			remaining = append(remaining, order)
+		}
+	}
+	trDat.Resting = remaining
+}
+
+//procCandleFill executes order by following candles: market order by open of the next candle with slippage,
+//limited order when candle price range reaches requested price before expiration.
+//With simulation clock orders are filled only when algorithm processes following candles, so algorithm doesn't know results in advance
+func (t *MockTrader) procCandleFill(action *entity.Action, opInfo trmodel.OpInfo, trDat *mockTraderData) {
+	buy := action.Direction == entity.Buy
+	fillTm := action.RetrievedAt
+	switch {
+	case t.sub.TChan != nil && !action.TriggerPrice.IsPositive():
+		trDat.Resting = append(trDat.Resting, &restingOrder{action: action, opInfo: opInfo, checked: action.RetrievedAt})
+		return
+	case isLimitFill(action):
+		price, candleTm, ok := t.findLimitFill(action, action.RetrievedAt, action.ExpirationTime)
+		if !ok {
+			t.logger.Debugf("Limit order not filled before expiration: %+v", action)
+			t.expire(action, trDat)
+			return
+		}
+		opInfo.PosPrice = price
+		fillTm = candleTm
+	case action.TriggerPrice.IsPositive():
+		//Stop is executed by the price crossed it
+		opInfo.PosPrice = t.fill.MarketPrice(action.TriggerPrice, buy)
+	default:
+		rec, ok := t.nextCandle(action.InstrFigi, action.RetrievedAt)
+		if !ok {
+			t.logger.Debugf("No candles after market order, order not filled: %+v", action)
+			t.expire(action, trDat)
+			return
+		}
+		opInfo.PosPrice = t.fill.MarketPrice(rec.Open, buy)
+		fillTm = rec.Time
+	}
+	if buy {
+		t.procBuy(opInfo, action, fillTm, trDat)
+	} else {
+		t.procSell(opInfo, action, fillTm, trDat)
+	}
+}
+
+//procRestingCandle checks candles of resting order up to the time, returns true if order is filled or expired
+func (t *MockTrader) procRestingCandle(order *restingOrder, tm time.Time, trDat *mockTraderData) bool {
+	action := order.action
+	if !isLimitFill(action) {
+		return t.procRestingMarket(order, tm, trDat)
+	}
+	to := tm
+	expired := !action.ExpirationTime.IsZero() && tm.After(action.ExpirationTime)
+	if expired {
+		to = action.ExpirationTime
+	}
+	price, candleTm, ok := t.findLimitFill(action, order.checked, to)
+	order.checked = to
+	if ok {
+		order.opInfo.PosPrice = price
+		if action.Direction == entity.Buy {
+			t.procBuy(order.opInfo, action, candleTm, trDat)
+		} else {
+			t.procSell(order.opInfo, action, candleTm, trDat)
+		}
+		return true
+	}
+	if expired {
+		t.logger.Debugf("Resting order expired: %+v", action)
+		t.expire(action, trDat)
+	}
+	return expired
+}
+
+//procRestingMarket fills market order by open of the next candle when simulation clock reaches the candle,
+//returns true if order is filled or there are no candles to fill it
+func (t *MockTrader) procRestingMarket(order *restingOrder, tm time.Time, trDat *mockTraderData) bool {
+	action := order.action
+	rec, ok := t.nextCandle(action.InstrFigi, order.checked)
+	if !ok {
+		t.logger.Debugf("No candles after market order, order not filled: %+v", action)
+		t.expire(action, trDat)
+		return true
+	}
+	if rec.Time.After(tm) {
+		return false
+	}
+	buy := action.Direction == entity.Buy
+	order.opInfo.PosPrice = t.fill.MarketPrice(rec.Open, buy)
+	if buy {
+		t.procBuy(order.opInfo, action, rec.Time, trDat)
+	} else {
+		t.procSell(order.opInfo, action, rec.Time, trDat)
+	}
+	return true
+}
+
+//isLimitFill checks if order is filled by requested price, otherwise it's filled by market
+func isLimitFill(action *entity.Action) bool {
+	return action.OrderType == entity.Limited && !action.ReqPrice.IsZero()
+}
+
+//findLimitFill searches the first candle after from time and not after to time (zero - without bound)
+//which price range reaches requested price, returns execution price and time of the candle
+func (t *MockTrader) findLimitFill(action *entity.Action, from time.Time, to time.Time) (decimal.Decimal, time.Time, bool) {
+	buy := action.Direction == entity.Buy
+	for _, rec := range t.figiHist[action.InstrFigi] {
+		if !rec.Time.After(from) {
+			continue
+		}
+		if !to.IsZero() && rec.Time.After(to) {
+			break
+		}
+		if price, ok := t.fill.LimitPrice(action.ReqPrice, buy, rec.Open, rec.High, rec.Low); ok {
+			return price, rec.Time, true
+		}
+	}
+	return decimal.Zero, time.Time{}, false
+}
+
+//nextCandle returns the first candle of figi after the time
+func (t *MockTrader) nextCandle(figi string, tm time.Time) (histRecord, bool) {
+	for _, rec := range t.figiHist[figi] {
+		if rec.Time.After(tm) {
+			if rec.Open.IsZero() {
+				rec.Open = rec.Price
+			}
+			return rec, true
+		}
+	}
+	return histRecord{}, false
+}
+
+//expire cancels order not filled by its expiration time
+func (t *MockTrader) expire(action *entity.Action, trDat *mockTraderData) {
+	trDat.Expired += 1
+	t.sub.RChan <- t.getRespWithStatus(action, entity.Canceled)
+}
+
 func (t MockTrader) getRespWithStatus(action *entity.Action, status entity.ActionStatus) *stmodel.ActionResp {
 	action.Status = status
 	return &stmodel.ActionResp{Action: action}
@@ -206,6 +499,9 @@ func (t *MockTrader) populateHistory() error {
 			Time:  hRec.Time,
 			Figi:  hRec.Figi,
 			Price: hRec.Close,
+			Open:  hRec.Open,
+			High:  hRec.High,
+			Low:   hRec.Low,
 		}
 		t.figiHist[hRec.Figi] = append(t.figiHist[hRec.Figi], rec)
 	}
@@ -245,11 +541,24 @@ func (t *MockTrader) RemoveSubscription(id uint) error {
 	return errors.NewNotImplemented()
 }
 
+//SetFillModel sets order fill simulation, orders are filled at once by close price when model is not set
+func (t *MockTrader) SetFillModel(model *fill.Model) {
+	t.fill = model
+}
+
+//KeepFullEquity keeps equity by every history record in report in addition to sampled one, it is required to merge reports
+func (t *MockTrader) KeepFullEquity() {
+	t.fullEquity = true
+}
+
 func (t MockTrader) GetStatCh() chan dto.HistStatResponse {
 	return t.statCh
 }
 
-func NewMockTrader(hRep repository.HistoryRepository, lots map[string]int64, figiCurrency map[string]string, logger *zap.SugaredLogger) MockTrader {
-	logger.Debugf("Initializing mock trader with currencies: %+v , lot nums: %+v", lots, figiCurrency)
-	return MockTrader{statCh: make(chan dto.HistStatResponse), hRep: hRep, lots: lots, figiCurrency: figiCurrency, logger: logger}
+//NewMockTrader creates history trader; shortEnabled contains instruments available for short selling and may be nil
+func NewMockTrader(hRep repository.HistoryRepository, lots map[string]int64, figiCurrency map[string]string,
+	shortEnabled map[string]bool, logger *zap.SugaredLogger) MockTrader {
+	logger.Debugf("Initializing mock trader with currencies: %+v , lot nums: %+v, short enabled: %+v", lots, figiCurrency, shortEnabled)
+	return MockTrader{statCh: make(chan dto.HistStatResponse), hRep: hRep, lots: lots, figiCurrency: figiCurrency,
+		shortEnabled: shortEnabled, logger: logger}
 }
@@ -0,0 +1,127 @@
+package trade
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	mock_repository "github.com/ldmi3i/tinkoff-invest-bot/internal/mocks/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/sizing"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/fill"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestMockTrader_candleFill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	hist := []entity.History{
+		{Figi: "a", Open: decimal.NewFromInt(100), High: decimal.NewFromInt(100), Low: decimal.NewFromInt(100), Close: decimal.NewFromInt(100), Time: start},
+		{Figi: "a", Open: decimal.NewFromInt(110), High: decimal.NewFromInt(112), Low: decimal.NewFromInt(108), Close: decimal.NewFromInt(110), Time: start.Add(time.Minute)},
+		{Figi: "a", Open: decimal.NewFromInt(110), High: decimal.NewFromInt(118), Low: decimal.NewFromInt(109), Close: decimal.NewFromInt(115), Time: start.Add(2 * time.Minute)},
+	}
+	hRep := mock_repository.NewMockHistoryRepository(ctrl)
+	hRep.EXPECT().FindAllByFigis(gomock.Any()).Return(hist, nil).AnyTimes()
+	aChan := make(chan *stmodel.ActionReq)
+	rChan := make(chan *stmodel.ActionResp, 1)
+	trader := NewMockTrader(hRep, map[string]int64{"a": 1}, map[string]string{"a": "rub"}, nil, zap.NewNop().Sugar())
+	trader.SetFillModel(&fill.Model{Type: fill.CandleFill, Slippage: decimal.NewFromFloat(0.01), Commission: decimal.NewFromFloat(0.001)})
+	assert.Nil(t, trader.AddSubscription(&stmodel.Subscription{AChan: aChan, RChan: rChan}))
+	trader.Go(context.Background())
+	limits := []*entity.MoneyLimit{{Currency: "rub", Amount: decimal.NewFromInt(250)}}
+
+	//Market buy filled by open of the next candle with slippage: 2 lots by 111.1 and commission
+	aChan <- &stmodel.ActionReq{Limits: limits, Action: &entity.Action{InstrFigi: "a", Direction: entity.Buy,
+		OrderType: entity.Market, RetrievedAt: start}}
+	resp := <-rChan
+	assert.Equal(t, entity.Success, resp.Action.Status)
+	assert.True(t, resp.Action.PositionPrice.Equal(decimal.NewFromFloat(111.1)), "got %s", resp.Action.PositionPrice)
+	assert.True(t, resp.Action.TotalPrice.Equal(decimal.NewFromFloat(222.4222)), "got %s", resp.Action.TotalPrice)
+
+	//Limited sell by 120 is never reached by candles high
+	aChan <- &stmodel.ActionReq{Limits: limits, Action: &entity.Action{InstrFigi: "a", Direction: entity.Sell, LotAmount: 2,
+		OrderType: entity.Limited, ReqPrice: decimal.NewFromInt(120), RetrievedAt: start.Add(time.Minute), ExpirationTime: start.Add(2 * time.Minute)}}
+	resp = <-rChan
+	assert.Equal(t, entity.Canceled, resp.Action.Status)
+
+	//Limited sell by 117 is filled by the last candle high
+	aChan <- &stmodel.ActionReq{Limits: limits, Action: &entity.Action{InstrFigi: "a", Direction: entity.Sell, LotAmount: 2,
+		OrderType: entity.Limited, ReqPrice: decimal.NewFromInt(117), RetrievedAt: start.Add(time.Minute), ExpirationTime: start.Add(2 * time.Minute)}}
+	resp = <-rChan
+	assert.Equal(t, entity.Success, resp.Action.Status)
+	assert.True(t, resp.Action.TotalPrice.Equal(decimal.NewFromFloat(233.766)), "got %s", resp.Action.TotalPrice)
+
+	close(aChan)
+	stat := <-trader.GetStatCh()
+	assert.Equal(t, uint(1), stat.ExpiredOpNum)
+	assert.True(t, stat.CurBalance["rub"].Equal(decimal.NewFromFloat(11.3438)), "got %s", stat.CurBalance["rub"])
+}
+
+func TestMockTrader_candleFillClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	hist := []entity.History{
+		{Figi: "a", Open: decimal.NewFromInt(100), High: decimal.NewFromInt(100), Low: decimal.NewFromInt(100), Close: decimal.NewFromInt(100), Time: start},
+		{Figi: "a", Open: decimal.NewFromInt(110), High: decimal.NewFromInt(112), Low: decimal.NewFromInt(108), Close: decimal.NewFromInt(110), Time: start.Add(time.Minute)},
+	}
+	hRep := mock_repository.NewMockHistoryRepository(ctrl)
+	hRep.EXPECT().FindAllByFigis(gomock.Any()).Return(hist, nil).AnyTimes()
+	aChan := make(chan *stmodel.ActionReq)
+	rChan := make(chan *stmodel.ActionResp, 1)
+	tChan := make(chan time.Time)
+	trader := NewMockTrader(hRep, map[string]int64{"a": 1}, map[string]string{"a": "rub"}, nil, zap.NewNop().Sugar())
+	trader.SetFillModel(&fill.Model{Type: fill.CandleFill})
+	assert.Nil(t, trader.AddSubscription(&stmodel.Subscription{AChan: aChan, RChan: rChan, TChan: tChan}))
+	trader.Go(context.Background())
+	limits := []*entity.MoneyLimit{{Currency: "rub", Amount: decimal.NewFromInt(250)}}
+
+	//Market buy is not filled until algorithm processes the next candle
+	aChan <- &stmodel.ActionReq{Limits: limits, Action: &entity.Action{InstrFigi: "a", Direction: entity.Buy,
+		OrderType: entity.Market, RetrievedAt: start}}
+	tChan <- start
+	tChan <- start //Received only after the first time processed
+	select {
+	case resp := <-rChan:
+		t.Fatalf("Order filled before the next candle processed: %+v", resp.Action)
+	default:
+	}
+	tChan <- start.Add(time.Minute)
+	resp := <-rChan
+	assert.Equal(t, entity.Success, resp.Action.Status)
+	assert.True(t, resp.Action.PositionPrice.Equal(decimal.NewFromInt(110)), "got %s", resp.Action.PositionPrice)
+	close(tChan)
+	close(aChan)
+	<-trader.GetStatCh()
+}
+
+func TestMockTrader_budgetSizing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	hist := []entity.History{
+		{Figi: "a", Open: decimal.NewFromInt(100), High: decimal.NewFromInt(100), Low: decimal.NewFromInt(100), Close: decimal.NewFromInt(100), Time: start},
+	}
+	hRep := mock_repository.NewMockHistoryRepository(ctrl)
+	hRep.EXPECT().FindAllByFigis(gomock.Any()).Return(hist, nil).AnyTimes()
+	aChan := make(chan *stmodel.ActionReq)
+	rChan := make(chan *stmodel.ActionResp, 1)
+	limits := []*entity.MoneyLimit{{Currency: "rub", Amount: decimal.NewFromInt(1000)}}
+	trader := NewMockTrader(hRep, map[string]int64{"a": 1}, map[string]string{"a": "rub"}, nil, zap.NewNop().Sugar())
+	trader.SetFillModel(&fill.Model{Type: fill.InstantFill})
+	assert.Nil(t, trader.AddSubscription(&stmodel.Subscription{AChan: aChan, RChan: rChan, Limits: limits,
+		Sizing: &sizing.Policy{Type: sizing.LimitPercent, Value: decimal.NewFromInt(40)}}))
+	trader.Go(context.Background())
+
+	//Percent of configured limit is bought while budget allows it, the last buy is capped by money left
+	for _, lots := range []int64{4, 4, 2} {
+		aChan <- &stmodel.ActionReq{Limits: limits, Action: &entity.Action{InstrFigi: "a", Direction: entity.Buy,
+			OrderType: entity.Market, RetrievedAt: start}}
+		resp := <-rChan
+		assert.Equal(t, entity.Success, resp.Action.Status)
+		assert.Equal(t, lots, resp.Action.LotAmount)
+	}
+	close(aChan)
+	<-trader.GetStatCh()
+}
@@ -16,20 +16,32 @@ type ProdTrader struct {
 
 func (t *ProdTrader) Go(ctx context.Context) {
 	t.ctx = ctx
+	t.startTradesStreams()
+	if t.riskMgr != nil {
+		t.riskMgr.Go(ctx)
+	}
 	go t.checkOrdersBg()
 	go t.actionProcBg()
 }
 
-func NewProdTrader(infoSrv service.InfoSrv, tradeSrv service.TradeService, actionRep repository.ActionRepository, logger *zap.SugaredLogger) Trader {
+func NewProdTrader(infoSrv service.InfoSrv, tradeSrv service.TradeService, actionRep repository.ActionRepository,
+	stopRep repository.StopOrderRepository, riskMgr RiskManager, budget BudgetManager,
+	logger *zap.SugaredLogger) Trader {
 	return &ProdTrader{
 		&BaseTrader{
-			infoSrv:   infoSrv,
-			tradeSrv:  tradeSrv,
-			actionRep: actionRep,
-			subs:      collections.NewSyncMap[uint, *stmodel.Subscription](),
-			orders:    collections.NewSyncMap[string, *entity.Action](),
-			algoCh:    make(chan *stmodel.ActionReq, 1),
-			logger:    logger,
+			infoSrv:    infoSrv,
+			tradeSrv:   tradeSrv,
+			actionRep:  actionRep,
+			stopRep:    stopRep,
+			riskMgr:    riskMgr,
+			budget:     budget,
+			subs:       collections.NewSyncMap[uint, *stmodel.Subscription](),
+			orders:     collections.NewSyncMap[string, *entity.Action](),
+			stopOrders: collections.NewSyncMap[string, *entity.StopOrder](),
+			useStream:  true,
+			streams:    make(map[string]bool),
+			algoCh:     make(chan *stmodel.ActionReq, 1),
+			logger:     logger,
 		},
 	}
 }
@@ -0,0 +1,428 @@
+package trade
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/shopspring/decimal"
+	"math"
+	"sort"
+	"time"
+)
+
+//maxEquityPoints limits size of equity curve returned in report, equity is calculated by every history record anyway
+const maxEquityPoints = 500
+
+//tradingDays is a number of trading days in year to annualize daily ratios
+const tradingDays = 252
+
+var hundred = decimal.NewFromInt(100)
+
+//fillRecord is an order executed by history trader, used to build backtest report
+type fillRecord struct {
+	Time     time.Time
+	Figi     string
+	Currency string
+	Lots     int64           //Signed amount: positive for buy and negative for sell
+	Price    decimal.Decimal //Execution price of single instrument
+	Money    decimal.Decimal //Signed money flow with commission: negative for buy and positive for sell
+}
+
+//openLots is a part of position opened by single fill and not closed yet
+type openLots struct {
+	time        time.Time
+	price       decimal.Decimal
+	lots        int64           //Signed amount: negative for short position
+	moneyPerLot decimal.Decimal //Money paid or received for single lot with commission
+}
+
+//priceEvent is a price of figi at the time from history
+type priceEvent struct {
+	time  time.Time
+	figi  string
+	price decimal.Decimal
+}
+
+//buildReport calculates trades, equity curve and performance metrics by executed orders and history prices
+func (t *MockTrader) buildReport(fills []*fillRecord) *dto.BacktestReport {
+	sort.SliceStable(fills, func(i, j int) bool {
+		return fills[i].Time.Before(fills[j].Time)
+	})
+	capital := make(map[string]decimal.Decimal)
+	for _, cur := range t.figiCurrency {
+		capital[cur] = decimal.Zero
+	}
+	if t.sub != nil {
+		for _, limit := range t.sub.Limits {
+			capital[limit.Currency] = limit.Amount
+		}
+	}
+	report := &dto.BacktestReport{Capital: capital, Metrics: make(map[string]*dto.ReportMetrics)}
+	events := t.priceEvents()
+	if len(events) > 0 {
+		report.Start = events[0].time
+		report.End = events[len(events)-1].time
+	}
+	points, exposed := t.calcEquity(events, fills, capital)
+	report.Trades = t.matchTrades(fills, events, report.End)
+	for cur, capVal := range capital {
+		report.Metrics[cur] = calcMetrics(cur, capVal, points, report.Trades, exposed[cur], report.End.Sub(report.Start))
+	}
+	report.Benchmarks = t.calcBenchmarks(capital, report.Metrics)
+	report.Equity = samplePoints(points)
+	if t.fullEquity {
+		report.FullEquity = points
+	}
+	return report
+}
+
+//priceEvents returns history prices of all figis sorted by time
+func (t *MockTrader) priceEvents() []priceEvent {
+	events := make([]priceEvent, 0)
+	for figi, hist := range t.figiHist {
+		for _, rec := range hist {
+			events = append(events, priceEvent{time: rec.Time, figi: figi, price: rec.Price})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].time.Before(events[j].time)
+	})
+	return events
+}
+
+//calcEquity sweeps history applying executed orders, returns equity by every history time and durations of open positions by currency
+func (t *MockTrader) calcEquity(events []priceEvent, fills []*fillRecord, capital map[string]decimal.Decimal) ([]*dto.EquityPoint, map[string]time.Duration) {
+	cash := make(map[string]decimal.Decimal)
+	for cur, val := range capital {
+		cash[cur] = val
+	}
+	pos := make(map[string]int64)
+	last := make(map[string]decimal.Decimal)
+	exposed := make(map[string]time.Duration)
+	points := make([]*dto.EquityPoint, 0)
+	fi := 0
+	for i := 0; i < len(events); {
+		tm := events[i].time
+		if len(points) > 0 {
+			//Positions held since the previous time
+			prev := points[len(points)-1].Time
+			for cur := range t.openCurrencies(pos) {
+				exposed[cur] += tm.Sub(prev)
+			}
+		}
+		for ; i < len(events) && events[i].time.Equal(tm); i++ {
+			last[events[i].figi] = events[i].price
+		}
+		//The last point takes into account orders executed after history end
+		for ; fi < len(fills) && (!fills[fi].Time.After(tm) || i == len(events)); fi++ {
+			cash[fills[fi].Currency] = cash[fills[fi].Currency].Add(fills[fi].Money)
+			pos[fills[fi].Figi] += fills[fi].Lots
+		}
+		points = append(points, &dto.EquityPoint{Time: tm, Value: t.valuate(cash, pos, last)})
+	}
+	return points, exposed
+}
+
+//valuate returns value of money and positions by currency
+func (t *MockTrader) valuate(cash map[string]decimal.Decimal, pos map[string]int64, last map[string]decimal.Decimal) map[string]decimal.Decimal {
+	value := make(map[string]decimal.Decimal)
+	for cur, val := range cash {
+		value[cur] = val
+	}
+	for figi, lots := range pos {
+		if lots == 0 {
+			continue
+		}
+		cur := t.figiCurrency[figi]
+		value[cur] = value[cur].Add(last[figi].Mul(decimal.NewFromInt(lots * t.lotSize(figi))))
+	}
+	return value
+}
+
+//openCurrencies returns currencies of open positions
+func (t *MockTrader) openCurrencies(pos map[string]int64) map[string]bool {
+	res := make(map[string]bool)
+	for figi, lots := range pos {
+		if lots != 0 {
+			res[t.figiCurrency[figi]] = true
+		}
+	}
+	return res
+}
+
+func (t *MockTrader) lotSize(figi string) int64 {
+	if lot, ok := t.lots[figi]; ok && lot > 0 {
+		return lot
+	}
+	return 1
+}
+
+//matchTrades matches opening and closing orders of each figi by FIFO into round trip trades.
+//Positions not closed by the end of history are valued by the last price
+func (t *MockTrader) matchTrades(fills []*fillRecord, events []priceEvent, end time.Time) []*dto.TradeReport {
+	trades := make([]*dto.TradeReport, 0)
+	open := make(map[string][]*openLots)
+	for _, f := range fills {
+		if f.Lots == 0 {
+			continue
+		}
+		remaining := f.Lots
+		moneyPerLot := f.Money.Abs().Div(decimal.NewFromInt(abs(f.Lots)))
+		queue := open[f.Figi]
+		for remaining != 0 && len(queue) > 0 && (queue[0].lots > 0) != (remaining > 0) {
+			entry := queue[0]
+			matched := abs(entry.lots)
+			if abs(remaining) < matched {
+				matched = abs(remaining)
+			}
+			trades = append(trades, newTrade(f.Figi, f.Currency, entry, matched, f.Time, f.Price, moneyPerLot, false))
+			entry.lots -= sign(entry.lots) * matched
+			remaining -= sign(remaining) * matched
+			if entry.lots == 0 {
+				queue = queue[1:]
+			}
+		}
+		if remaining != 0 {
+			queue = append(queue, &openLots{time: f.Time, price: f.Price, lots: remaining, moneyPerLot: moneyPerLot})
+		}
+		open[f.Figi] = queue
+	}
+	last := make(map[string]decimal.Decimal)
+	for _, ev := range events {
+		last[ev.figi] = ev.price
+	}
+	for figi, queue := range open {
+		for _, entry := range queue {
+			lastMoney := last[figi].Mul(decimal.NewFromInt(t.lotSize(figi)))
+			trades = append(trades, newTrade(figi, t.figiCurrency[figi], entry, abs(entry.lots), end, last[figi], lastMoney, true))
+		}
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].EntryTime.Before(trades[j].EntryTime)
+	})
+	return trades
+}
+
+func newTrade(figi string, currency string, entry *openLots, lots int64, exitTime time.Time, exitPrice decimal.Decimal,
+	exitMoneyPerLot decimal.Decimal, open bool) *dto.TradeReport {
+	profit := exitMoneyPerLot.Sub(entry.moneyPerLot).Mul(decimal.NewFromInt(lots))
+	if entry.lots < 0 {
+		profit = profit.Neg()
+	}
+	return &dto.TradeReport{Figi: figi, Currency: currency, Short: entry.lots < 0, Lots: lots, EntryTime: entry.time,
+		EntryPrice: entry.price, ExitTime: exitTime, ExitPrice: exitPrice, Profit: profit, Open: open}
+}
+
+//calcMetrics calculates performance metrics of algorithm in the currency
+func calcMetrics(currency string, capital decimal.Decimal, points []*dto.EquityPoint, trades []*dto.TradeReport,
+	exposed time.Duration, period time.Duration) *dto.ReportMetrics {
+	metrics := &dto.ReportMetrics{AvgHolding: time.Duration(0).String()}
+	if len(points) > 0 {
+		metrics.Profit = points[len(points)-1].Value[currency].Sub(capital)
+	}
+	if capital.IsPositive() {
+		metrics.Return = metrics.Profit.Div(capital).Mul(hundred).Round(4)
+	}
+	peak := capital
+	dayValues := make([]decimal.Decimal, 0)
+	lastDay := ""
+	for _, point := range points {
+		value := point.Value[currency]
+		if value.GreaterThan(peak) {
+			peak = value
+		}
+		if dd := peak.Sub(value); dd.GreaterThan(metrics.MaxDrawdown) {
+			metrics.MaxDrawdown = dd
+			if peak.IsPositive() {
+				metrics.MaxDrawdownPct = dd.Div(peak).Mul(hundred).Round(4)
+			}
+		}
+		if day := point.Time.UTC().Format("2006-01-02"); day != lastDay {
+			dayValues = append(dayValues, value)
+			lastDay = day
+		} else {
+			dayValues[len(dayValues)-1] = value
+		}
+	}
+	metrics.Sharpe, metrics.Sortino = calcRatios(dayValues)
+	grossProfit := decimal.Zero
+	grossLoss := decimal.Zero
+	wins := 0
+	var holding time.Duration
+	for _, trade := range trades {
+		if trade.Currency != currency || trade.Open {
+			continue
+		}
+		metrics.TradeNum++
+		holding += trade.ExitTime.Sub(trade.EntryTime)
+		if trade.Profit.IsPositive() {
+			wins++
+			grossProfit = grossProfit.Add(trade.Profit)
+		} else {
+			grossLoss = grossLoss.Add(trade.Profit.Neg())
+		}
+	}
+	if metrics.TradeNum > 0 {
+		metrics.WinRate = decimal.NewFromInt(int64(wins * 100)).Div(decimal.NewFromInt(int64(metrics.TradeNum))).Round(4)
+		metrics.AvgHolding = (holding / time.Duration(metrics.TradeNum)).String()
+	}
+	if grossLoss.IsPositive() {
+		metrics.ProfitFactor = grossProfit.Div(grossLoss).Round(4)
+	}
+	if period > 0 {
+		metrics.ExposurePct = decimal.NewFromFloat(float64(exposed) / float64(period) * 100).Round(4)
+	}
+	return metrics
+}
+
+//calcRatios returns annualized Sharpe and Sortino ratios of daily returns with zero risk-free rate
+func calcRatios(dayValues []decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	returns := make([]float64, 0, len(dayValues))
+	for i := 1; i < len(dayValues); i++ {
+		if dayValues[i-1].IsPositive() {
+			returns = append(returns, dayValues[i].Div(dayValues[i-1]).InexactFloat64()-1)
+		}
+	}
+	if len(returns) < 2 {
+		return decimal.Zero, decimal.Zero
+	}
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	variance := 0.0
+	downside := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	downDev := math.Sqrt(downside / float64(len(returns)))
+	annual := math.Sqrt(tradingDays)
+	sharpe := decimal.Zero
+	sortino := decimal.Zero
+	if std > 0 {
+		sharpe = decimal.NewFromFloat(mean / std * annual).Round(4)
+	}
+	if downDev > 0 {
+		sortino = decimal.NewFromFloat(mean / downDev * annual).Round(4)
+	}
+	return sharpe, sortino
+}
+
+//calcBenchmarks calculates buy-and-hold result of each figi bought by the whole capital of its currency
+func (t *MockTrader) calcBenchmarks(capital map[string]decimal.Decimal, metrics map[string]*dto.ReportMetrics) []*dto.BenchmarkReport {
+	res := make([]*dto.BenchmarkReport, 0, len(t.figiHist))
+	for figi, hist := range t.figiHist {
+		if len(hist) == 0 || !hist[0].Price.IsPositive() {
+			continue
+		}
+		cur := t.figiCurrency[figi]
+		bench := &dto.BenchmarkReport{Figi: figi, Currency: cur, StartPrice: hist[0].Price, EndPrice: hist[len(hist)-1].Price}
+		bench.Return = bench.EndPrice.Div(bench.StartPrice).Sub(decimal.NewFromInt(1)).Mul(hundred).Round(4)
+		lotPrice := bench.StartPrice.Mul(decimal.NewFromInt(t.lotSize(figi)))
+		lots := capital[cur].Div(lotPrice).Floor()
+		bench.Profit = bench.EndPrice.Sub(bench.StartPrice).Mul(decimal.NewFromInt(t.lotSize(figi))).Mul(lots)
+		if m, ok := metrics[cur]; ok {
+			bench.Excess = m.Profit.Sub(bench.Profit)
+		}
+		res = append(res, bench)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Figi < res[j].Figi
+	})
+	return res
+}
+
+//MergeReports stitches reports of consecutive history periods into single report: equity of each period is shifted by
+//profit of previous periods and metrics are recalculated by stitched equity, trades of all periods are kept.
+//Full equity of reports is used when kept, sampled one otherwise. Capital of the first report is used as initial capital
+func MergeReports(reports []*dto.BacktestReport) *dto.BacktestReport {
+	res := &dto.BacktestReport{Trades: make([]*dto.TradeReport, 0), Metrics: make(map[string]*dto.ReportMetrics),
+		Capital: make(map[string]decimal.Decimal)}
+	if len(reports) == 0 {
+		return res
+	}
+	res.Capital = reports[0].Capital
+	res.Start = reports[0].Start
+	res.End = reports[len(reports)-1].End
+	points := make([]*dto.EquityPoint, 0)
+	offset := make(map[string]decimal.Decimal)
+	exposed := make(map[string]time.Duration)
+	var period time.Duration
+	benchmarks := make(map[string]*dto.BenchmarkReport)
+	figis := make([]string, 0)
+	for _, report := range reports {
+		res.Trades = append(res.Trades, report.Trades...)
+		equity := report.FullEquity
+		if equity == nil {
+			equity = report.Equity
+		}
+		for _, point := range equity {
+			value := make(map[string]decimal.Decimal)
+			for cur, val := range point.Value {
+				value[cur] = val.Add(offset[cur])
+			}
+			points = append(points, &dto.EquityPoint{Time: point.Time, Value: value})
+		}
+		reportPeriod := report.End.Sub(report.Start)
+		period += reportPeriod
+		for cur, metrics := range report.Metrics {
+			offset[cur] = offset[cur].Add(metrics.Profit)
+			exposed[cur] += time.Duration(metrics.ExposurePct.Div(hundred).InexactFloat64() * float64(reportPeriod))
+		}
+		for _, bench := range report.Benchmarks {
+			merged, ok := benchmarks[bench.Figi]
+			if !ok {
+				benchCopy := *bench
+				benchmarks[bench.Figi] = &benchCopy
+				figis = append(figis, bench.Figi)
+				continue
+			}
+			merged.EndPrice = bench.EndPrice
+			merged.Profit = merged.Profit.Add(bench.Profit)
+		}
+	}
+	for cur, capVal := range res.Capital {
+		res.Metrics[cur] = calcMetrics(cur, capVal, points, res.Trades, exposed[cur], period)
+	}
+	//Buy-and-hold is repeated in each period
+	res.Benchmarks = make([]*dto.BenchmarkReport, 0, len(figis))
+	for _, figi := range figis {
+		bench := benchmarks[figi]
+		bench.Return = bench.EndPrice.Div(bench.StartPrice).Sub(decimal.NewFromInt(1)).Mul(hundred).Round(4)
+		if metrics, ok := res.Metrics[bench.Currency]; ok {
+			bench.Excess = metrics.Profit.Sub(bench.Profit)
+		}
+		res.Benchmarks = append(res.Benchmarks, bench)
+	}
+	res.Equity = samplePoints(points)
+	return res
+}
+
+//samplePoints returns evenly distributed points of equity curve including the last one
+func samplePoints(points []*dto.EquityPoint) []*dto.EquityPoint {
+	if len(points) <= maxEquityPoints {
+		return points
+	}
+	res := make([]*dto.EquityPoint, 0, maxEquityPoints)
+	for i := 0; i < maxEquityPoints-1; i++ {
+		res = append(res, points[i*(len(points)-1)/(maxEquityPoints-1)])
+	}
+	return append(res, points[len(points)-1])
+}
+
+func abs(val int64) int64 {
+	if val < 0 {
+		return -val
+	}
+	return val
+}
+
+func sign(val int64) int64 {
+	if val < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,81 @@
+package trade
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestMockTrader_buildReport(t *testing.T) {
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time {
+		return start.Add(time.Duration(n) * 24 * time.Hour)
+	}
+	trader := NewMockTrader(nil, map[string]int64{"a": 1}, map[string]string{"a": "rub"}, nil, zap.NewNop().Sugar())
+	trader.sub = &stmodel.Subscription{Limits: []*entity.MoneyLimit{{Currency: "rub", Amount: decimal.NewFromInt(1000)}}}
+	trader.figiHist = map[string][]histRecord{"a": {
+		{Time: day(0), Figi: "a", Price: decimal.NewFromInt(100)},
+		{Time: day(1), Figi: "a", Price: decimal.NewFromInt(110)},
+		{Time: day(2), Figi: "a", Price: decimal.NewFromInt(90)},
+		{Time: day(3), Figi: "a", Price: decimal.NewFromInt(120)},
+	}}
+	//Position bought by 100 and sold by 90, bought again by 90 and held to the end
+	fills := []*fillRecord{
+		{Time: day(0), Figi: "a", Currency: "rub", Lots: 5, Price: decimal.NewFromInt(100), Money: decimal.NewFromInt(-500)},
+		{Time: day(2), Figi: "a", Currency: "rub", Lots: -5, Price: decimal.NewFromInt(90), Money: decimal.NewFromInt(450)},
+		{Time: day(2), Figi: "a", Currency: "rub", Lots: 5, Price: decimal.NewFromInt(90), Money: decimal.NewFromInt(-450)},
+	}
+
+	report := trader.buildReport(fills)
+
+	assert.Equal(t, 2, len(report.Trades))
+	assert.True(t, report.Trades[0].Profit.Equal(decimal.NewFromInt(-50)))
+	assert.False(t, report.Trades[0].Open)
+	assert.True(t, report.Trades[1].Profit.Equal(decimal.NewFromInt(150)))
+	assert.True(t, report.Trades[1].Open)
+	assert.Equal(t, 4, len(report.Equity))
+	assert.True(t, report.Equity[2].Value["rub"].Equal(decimal.NewFromInt(950)))
+
+	metrics := report.Metrics["rub"]
+	assert.True(t, metrics.Profit.Equal(decimal.NewFromInt(100)))
+	assert.True(t, metrics.Return.Equal(decimal.NewFromInt(10)))
+	assert.True(t, metrics.MaxDrawdown.Equal(decimal.NewFromInt(100)))
+	assert.True(t, metrics.MaxDrawdownPct.Equal(decimal.NewFromFloat(9.5238)), "got %s", metrics.MaxDrawdownPct)
+	assert.Equal(t, 1, metrics.TradeNum)
+	assert.True(t, metrics.WinRate.IsZero())
+	assert.Equal(t, "48h0m0s", metrics.AvgHolding)
+	assert.True(t, metrics.ExposurePct.Equal(decimal.NewFromInt(100)))
+	assert.True(t, metrics.Sharpe.IsPositive())
+
+	assert.Equal(t, 1, len(report.Benchmarks))
+	assert.True(t, report.Benchmarks[0].Return.Equal(decimal.NewFromInt(20)))
+	assert.True(t, report.Benchmarks[0].Profit.Equal(decimal.NewFromInt(200)))
+	assert.True(t, report.Benchmarks[0].Excess.Equal(decimal.NewFromInt(-100)))
+}
+
+func TestMergeReports_fullEquity(t *testing.T) {
+	start := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	point := func(hours int, value int64) *dto.EquityPoint {
+		return &dto.EquityPoint{Time: start.Add(time.Duration(hours) * time.Hour), Value: map[string]decimal.Decimal{"rub": decimal.NewFromInt(value)}}
+	}
+	capital := map[string]decimal.Decimal{"rub": decimal.NewFromInt(1000)}
+	//Sampled equity misses the dip kept by full equity
+	first := &dto.BacktestReport{Start: start, End: start.Add(2 * time.Hour), Capital: capital,
+		Equity:     []*dto.EquityPoint{point(0, 1000), point(2, 1050)},
+		FullEquity: []*dto.EquityPoint{point(0, 1000), point(1, 900), point(2, 1050)},
+		Metrics:    map[string]*dto.ReportMetrics{"rub": {Profit: decimal.NewFromInt(50)}}}
+	second := &dto.BacktestReport{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour), Capital: capital,
+		Equity:  []*dto.EquityPoint{point(2, 1000), point(3, 1010)},
+		Metrics: map[string]*dto.ReportMetrics{"rub": {Profit: decimal.NewFromInt(10)}}}
+
+	merged := MergeReports([]*dto.BacktestReport{first, second})
+	assert.Equal(t, 5, len(merged.Equity))
+	assert.True(t, merged.Equity[4].Value["rub"].Equal(decimal.NewFromInt(1060)))
+	assert.True(t, merged.Metrics["rub"].MaxDrawdown.Equal(decimal.NewFromInt(100)), "got %s", merged.Metrics["rub"].MaxDrawdown)
+	assert.True(t, merged.Metrics["rub"].Profit.Equal(decimal.NewFromInt(60)))
+}
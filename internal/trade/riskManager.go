@@ -0,0 +1,325 @@
+package trade
+
+import (
+	"context"
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/repository"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/trmodel"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+//RiskManager enforces account-wide risk rules on orders of all algorithms trading on the same account
+type RiskManager interface {
+	//Check validates order against risk rules of its account, returns error with violated rule when order must be rejected
+	Check(order *trmodel.RiskOrder, ctx context.Context) error
+	//SetHaltHandler sets handler called in background when account trading is halted by daily loss rule
+	SetHaltHandler(handler func(accountId string, reason string))
+	//Go starts background task taking portfolio values of accounts with daily loss rules at the start of every day
+	Go(ctx context.Context)
+}
+
+//dayStartInterval is an interval of checking day start values of daily loss rules
+const dayStartInterval = time.Minute
+
+//portfolio is a valuation of account holdings in single currency
+type portfolio struct {
+	money     decimal.Decimal            //Available and blocked money
+	positions map[string]decimal.Decimal //Signed value of instrument positions by last price
+}
+
+//value returns full portfolio value - money and positions (short positions decrease it)
+func (p *portfolio) value() decimal.Decimal {
+	res := p.money
+	for _, val := range p.positions {
+		res = res.Add(val)
+	}
+	return res
+}
+
+//exposure returns gross value of long and short positions
+func (p *portfolio) exposure() decimal.Decimal {
+	res := decimal.Zero
+	for _, val := range p.positions {
+		res = res.Add(val.Abs())
+	}
+	return res
+}
+
+type DefaultRiskManager struct {
+	env         entity.Environment //Environment of accounts checked by manager
+	infoSrv     service.InfoSrv
+	ruleRep     repository.RiskRuleRepository
+	instruments collections.SyncMap[string, *dtotapi.InstrumentResponse] //Cache of instruments currency and lot
+	orders      map[string][]time.Time                                   //Post time of account orders during the last minute
+	onHalt      func(accountId string, reason string)
+	mu          sync.Mutex
+	logger      *zap.SugaredLogger
+}
+
+func (rm *DefaultRiskManager) SetHaltHandler(handler func(accountId string, reason string)) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.onHalt = handler
+}
+
+func (rm *DefaultRiskManager) Go(ctx context.Context) {
+	go rm.dayStartBg(ctx)
+}
+
+//dayStartBg periodically takes day start values of daily loss rules which don't have them for the current day yet:
+//at the start of the day, after creation of rule or failed valuation
+func (rm *DefaultRiskManager) dayStartBg(ctx context.Context) {
+	rm.logger.Info("Starting background day start valuation of daily loss rules...")
+	ticker := time.NewTicker(dayStartInterval)
+	defer ticker.Stop()
+	for {
+		rm.takeDayStart(ctx)
+		select {
+		case <-ctx.Done():
+			rm.logger.Info("Stopping background day start valuation of daily loss rules")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+//takeDayStart valuates accounts with daily loss rules without day start value of the current day and persists it
+func (rm *DefaultRiskManager) takeDayStart(ctx context.Context) {
+	rules, err := rm.ruleRep.FindByType(rm.env, entity.MaxDailyLoss)
+	if err != nil {
+		rm.logger.Errorf("Error loading daily loss rules: %s", err)
+		return
+	}
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	day := time.Now().Format("2006-01-02")
+	portfolios := make(map[string]map[string]*portfolio)
+	for _, rule := range rules {
+		if rule.DayStart == day {
+			continue
+		}
+		pfs, ok := portfolios[rule.AccountID]
+		if !ok {
+			if pfs, err = rm.valuate(rule.AccountID, ctx); err != nil {
+				rm.logger.Errorf("Error valuating portfolio of account %s at day start: %s", rule.AccountID, err)
+				continue
+			}
+			portfolios[rule.AccountID] = pfs
+		}
+		rm.setDayStart(rule, getPortfolio(pfs, rule.Currency).value(), day)
+	}
+}
+
+func (rm *DefaultRiskManager) Check(order *trmodel.RiskOrder, ctx context.Context) error {
+	//Rules are loaded under lock as day state of daily loss rule may be updated by background task
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rules, err := rm.ruleRep.FindByAccount(order.AccountId)
+	if err != nil {
+		rm.logger.Errorf("Error loading risk rules of account %s: %s", order.AccountId, err)
+		return errors.NewUnexpectedError("error loading risk rules")
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	now := time.Now()
+	day := now.Format("2006-01-02")
+	//Halted account may only reduce positions
+	for _, rule := range rules {
+		if order.Opening && rule.Type == entity.MaxDailyLoss && rule.HaltedDay == day {
+			return errors.NewValidationErr("trading on account halted by daily loss limit till the end of the day")
+		}
+	}
+	recent := rm.recentOrders(order.AccountId, now)
+	var portfolios map[string]*portfolio
+	for _, rule := range rules {
+		if rule.Type != entity.MaxOrdersPerMin && portfolios == nil {
+			if portfolios, err = rm.valuate(order.AccountId, ctx); err != nil {
+				rm.logger.Errorf("Error valuating portfolio of account %s: %s", order.AccountId, err)
+				return errors.NewUnexpectedError("error valuating account portfolio")
+			}
+		}
+		if err = rm.checkRule(rule, order, recent, portfolios, day); err != nil {
+			return err
+		}
+	}
+	rm.orders[order.AccountId] = append(recent, now)
+	return nil
+}
+
+//checkRule returns error if order violates the rule
+func (rm *DefaultRiskManager) checkRule(rule *entity.RiskRule, order *trmodel.RiskOrder, recent []time.Time,
+	portfolios map[string]*portfolio, day string) error {
+	switch rule.Type {
+	case entity.MaxOrdersPerMin:
+		if decimal.NewFromInt(int64(len(recent))).GreaterThanOrEqual(rule.Value) {
+			return errors.NewValidationErr(fmt.Sprintf("limit of %s orders per minute reached", rule.Value))
+		}
+	case entity.MaxDailyLoss:
+		pf := getPortfolio(portfolios, rule.Currency)
+		loss := rm.dailyLoss(rule, pf.value(), day)
+		if loss.LessThan(rule.Value) {
+			return nil
+		}
+		reason := fmt.Sprintf("daily loss %s %s reached limit %s", loss, rule.Currency, rule.Value)
+		if rule.HaltedDay != day {
+			rm.halt(rule, day, reason)
+		}
+		//Closing order reduces position, so it's allowed even when loss limit is reached
+		if order.Opening {
+			return errors.NewValidationErr(reason)
+		}
+	case entity.MaxExposure:
+		if !order.Opening || rule.Currency != order.Currency {
+			return nil
+		}
+		exposure := getPortfolio(portfolios, rule.Currency).exposure().Add(order.Amount)
+		if exposure.GreaterThan(rule.Value) {
+			return errors.NewValidationErr(fmt.Sprintf("gross exposure %s %s exceeds limit %s", exposure, rule.Currency, rule.Value))
+		}
+	case entity.MaxFigiShare:
+		if !order.Opening {
+			return nil
+		}
+		pf := getPortfolio(portfolios, order.Currency)
+		total := pf.value()
+		if !total.IsPositive() {
+			return errors.NewValidationErr(fmt.Sprintf("portfolio value in %s is not positive", order.Currency))
+		}
+		share := pf.positions[order.Figi].Abs().Add(order.Amount).Mul(decimal.NewFromInt(100)).Div(total)
+		if share.GreaterThan(rule.Value) {
+			return errors.NewValidationErr(fmt.Sprintf("share of %s in portfolio %s%% exceeds limit %s%%",
+				order.Figi, share.Round(2), rule.Value))
+		}
+	default:
+		rm.logger.Warnf("Unknown risk rule type %s of rule %d, skipping...", rule.Type, rule.ID)
+	}
+	return nil
+}
+
+//recentOrders returns post time of account orders during the last minute
+func (rm *DefaultRiskManager) recentOrders(accountId string, now time.Time) []time.Time {
+	from := now.Add(-time.Minute)
+	res := make([]time.Time, 0)
+	for _, tm := range rm.orders[accountId] {
+		if tm.After(from) {
+			res = append(res, tm)
+		}
+	}
+	return res
+}
+
+//dailyLoss returns drop of portfolio value since the start of the day.
+//If day start value is not taken by background task yet, current value is used as day start one
+func (rm *DefaultRiskManager) dailyLoss(rule *entity.RiskRule, value decimal.Decimal, day string) decimal.Decimal {
+	if rule.DayStart != day {
+		rm.setDayStart(rule, value, day)
+	}
+	return rule.DayStartValue.Sub(value)
+}
+
+//setDayStart sets day start value to daily loss rule and persists it. Must be called under lock
+func (rm *DefaultRiskManager) setDayStart(rule *entity.RiskRule, value decimal.Decimal, day string) {
+	rm.logger.Infof("Day start value of account %s in %s: %s", rule.AccountID, rule.Currency, value)
+	rule.DayStart = day
+	rule.DayStartValue = value
+	if err := rm.ruleRep.UpdateDayState(rule); err != nil {
+		rm.logger.Errorf("Error saving day start value of rule %d: %s", rule.ID, err)
+	}
+}
+
+//halt stops trading on account till the end of the day, persists halt in rule and notifies halt handler
+func (rm *DefaultRiskManager) halt(rule *entity.RiskRule, day string, reason string) {
+	rm.logger.Warnf("Trading on account %s halted: %s", rule.AccountID, reason)
+	rule.HaltedDay = day
+	if err := rm.ruleRep.UpdateDayState(rule); err != nil {
+		rm.logger.Errorf("Error saving halt of rule %d: %s", rule.ID, err)
+	}
+	if rm.onHalt != nil {
+		go rm.onHalt(rule.AccountID, reason)
+	}
+}
+
+//valuate returns account portfolios by currency with positions valued by last prices
+func (rm *DefaultRiskManager) valuate(accountId string, ctx context.Context) (map[string]*portfolio, error) {
+	positions, err := rm.infoSrv.GetPositions(&dtotapi.PositionsRequest{AccountId: accountId}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]*portfolio)
+	for _, money := range positions.Money {
+		getPortfolio(res, money.Currency).money = money.Value
+	}
+	for _, money := range positions.Blocked {
+		pf := getPortfolio(res, money.Currency)
+		pf.money = pf.money.Add(money.Value)
+	}
+	if len(positions.Securities) == 0 {
+		return res, nil
+	}
+	figis := make([]string, 0, len(positions.Securities))
+	for _, security := range positions.Securities {
+		figis = append(figis, security.Figi)
+	}
+	prices, err := rm.infoSrv.GetLastPrices(figis, ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, security := range positions.Securities {
+		instr, err := rm.instrument(security.Figi, ctx)
+		if err != nil {
+			return nil, err
+		}
+		price := prices.GetByFigi(security.Figi)
+		if price == nil {
+			return nil, errors.NewNotFound("last price of " + security.Figi + " not found")
+		}
+		amount := decimal.NewFromInt(security.Balance + security.Blocked)
+		getPortfolio(res, instr.Currency).positions[security.Figi] = price.Price.Mul(amount)
+	}
+	return res, nil
+}
+
+//instrument returns cached instrument info, requests it on the first call
+func (rm *DefaultRiskManager) instrument(figi string, ctx context.Context) (*dtotapi.InstrumentResponse, error) {
+	if instr, ok := rm.instruments.Get(figi); ok {
+		return instr, nil
+	}
+	instr, err := rm.infoSrv.GetInstrumentInfoByFigi(figi, ctx)
+	if err != nil {
+		return nil, err
+	}
+	rm.instruments.Put(figi, instr)
+	return instr, nil
+}
+
+//getPortfolio returns portfolio in currency, empty one is created if account has no holdings in it
+func getPortfolio(portfolios map[string]*portfolio, currency string) *portfolio {
+	pf, ok := portfolios[currency]
+	if !ok {
+		pf = &portfolio{money: decimal.Zero, positions: make(map[string]decimal.Decimal)}
+		portfolios[currency] = pf
+	}
+	return pf
+}
+
+//NewRiskManager creates risk manager of accounts of the environment, info service must be of the same environment
+func NewRiskManager(env entity.Environment, infoSrv service.InfoSrv, ruleRep repository.RiskRuleRepository,
+	logger *zap.SugaredLogger) RiskManager {
+	return &DefaultRiskManager{
+		env:         env,
+		infoSrv:     infoSrv,
+		ruleRep:     ruleRep,
+		instruments: collections.NewSyncMap[string, *dtotapi.InstrumentResponse](),
+		orders:      make(map[string][]time.Time),
+		logger:      logger,
+	}
+}
@@ -0,0 +1,148 @@
+package trade
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	mock_service "github.com/ldmi3i/tinkoff-invest-bot/internal/mocks/service"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/trmodel"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+//ruleRepStub keeps rules in memory
+type ruleRepStub struct {
+	rules []*entity.RiskRule
+}
+
+func (r *ruleRepStub) Save(rule *entity.RiskRule) error {
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+func (r *ruleRepStub) FindByAccount(accountId string) ([]*entity.RiskRule, error) {
+	return r.rules, nil
+}
+
+func (r *ruleRepStub) FindByType(env entity.Environment, ruleType entity.RiskRuleType) ([]*entity.RiskRule, error) {
+	res := make([]*entity.RiskRule, 0)
+	for _, rule := range r.rules {
+		if rule.Env == env && rule.Type == ruleType {
+			res = append(res, rule)
+		}
+	}
+	return res, nil
+}
+
+func (r *ruleRepStub) UpdateDayState(rule *entity.RiskRule) error {
+	return nil
+}
+
+func (r *ruleRepStub) Delete(id uint) error {
+	return nil
+}
+
+//mockPortfolio sets account with money in rub and 10 instruments of figi "a" with the price of 100 rub
+func mockPortfolio(ctrl *gomock.Controller, money int64) *mock_service.MockInfoSrv {
+	infoSrv := mock_service.NewMockInfoSrv(ctrl)
+	infoSrv.EXPECT().GetPositions(gomock.Any(), gomock.Any()).Return(&dtotapi.PositionsResponse{
+		Money:      []*dtotapi.MoneyValue{{Currency: "rub", Value: decimal.NewFromInt(money)}},
+		Securities: []*dtotapi.PositionsSecurity{{Figi: "a", Balance: 10}},
+	}, nil).AnyTimes()
+	infoSrv.EXPECT().GetLastPrices(gomock.Any(), gomock.Any()).Return(&dtotapi.LastPricesResponse{
+		LastPrices: []*dtotapi.LastPrice{{Figi: "a", Price: decimal.NewFromInt(100)}},
+	}, nil).AnyTimes()
+	infoSrv.EXPECT().GetInstrumentInfoByFigi("a", gomock.Any()).Return(&dtotapi.InstrumentResponse{Figi: "a", Currency: "rub"}, nil).Times(1)
+	return infoSrv
+}
+
+func TestRiskManager_exposureAndShare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rules := &ruleRepStub{rules: []*entity.RiskRule{
+		{Type: entity.MaxExposure, Currency: "rub", Value: decimal.NewFromInt(1500)},
+		{Type: entity.MaxFigiShare, Value: decimal.NewFromInt(60)},
+	}}
+	rm := NewRiskManager(entity.ProdEnv, mockPortfolio(ctrl, 1000), rules, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	//Portfolio value 2000, exposure 1000
+	assert.Nil(t, rm.Check(&trmodel.RiskOrder{Figi: "b", Currency: "rub", Opening: true, Amount: decimal.NewFromInt(400)}, ctx))
+	assert.NotNil(t, rm.Check(&trmodel.RiskOrder{Figi: "b", Currency: "rub", Opening: true, Amount: decimal.NewFromInt(600)}, ctx),
+		"Exposure 1600 exceeds limit")
+	assert.NotNil(t, rm.Check(&trmodel.RiskOrder{Figi: "a", Currency: "rub", Opening: true, Amount: decimal.NewFromInt(300)}, ctx),
+		"Share of figi a 65% exceeds limit")
+	assert.Nil(t, rm.Check(&trmodel.RiskOrder{Figi: "a", Currency: "rub", Amount: decimal.NewFromInt(1000)}, ctx),
+		"Closing order is not restricted")
+}
+
+func TestRiskManager_ordersPerMinute(t *testing.T) {
+	rules := &ruleRepStub{rules: []*entity.RiskRule{{Type: entity.MaxOrdersPerMin, Value: decimal.NewFromInt(2)}}}
+	rm := NewRiskManager(entity.ProdEnv, nil, rules, zap.NewNop().Sugar())
+	order := &trmodel.RiskOrder{AccountId: "acc", Figi: "a", Currency: "rub"}
+	assert.Nil(t, rm.Check(order, context.Background()))
+	assert.Nil(t, rm.Check(order, context.Background()))
+	assert.NotNil(t, rm.Check(order, context.Background()))
+}
+
+func TestRiskManager_dailyLossHalt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rules := &ruleRepStub{rules: []*entity.RiskRule{{AccountID: "acc", Type: entity.MaxDailyLoss, Currency: "rub", Value: decimal.NewFromInt(100)}}}
+	infoSrv := mock_service.NewMockInfoSrv(ctrl)
+	money := []int64{1000, 950, 850, 850}
+	for _, m := range money {
+		infoSrv.EXPECT().GetPositions(gomock.Any(), gomock.Any()).Return(&dtotapi.PositionsResponse{
+			Money: []*dtotapi.MoneyValue{{Currency: "rub", Value: decimal.NewFromInt(m)}},
+		}, nil).Times(1)
+	}
+	rm := NewRiskManager(entity.ProdEnv, infoSrv, rules, zap.NewNop().Sugar())
+	halted := make(chan string, 1)
+	rm.SetHaltHandler(func(accountId string, reason string) {
+		halted <- accountId
+	})
+	order := &trmodel.RiskOrder{AccountId: "acc", Figi: "a", Currency: "rub", Opening: true, Amount: decimal.NewFromInt(10)}
+	assert.Nil(t, rm.Check(order, context.Background()))
+	assert.Nil(t, rm.Check(order, context.Background()))
+	assert.NotNil(t, rm.Check(order, context.Background()), "Loss 150 exceeds limit")
+	assert.Equal(t, "acc", <-halted)
+	assert.NotNil(t, rm.Check(order, context.Background()), "Trading halted till the end of the day")
+	closing := &trmodel.RiskOrder{AccountId: "acc", Figi: "a", Currency: "rub", Amount: decimal.NewFromInt(10)}
+	assert.Nil(t, rm.Check(closing, context.Background()), "Closing sell reduces position on halted day")
+	assert.Equal(t, 0, len(halted), "Account is not halted twice")
+
+	//Halt is kept in rule, so it survives restart
+	restarted := NewRiskManager(entity.ProdEnv, nil, rules, zap.NewNop().Sugar())
+	assert.NotNil(t, restarted.Check(order, context.Background()), "Trading halted after restart")
+}
+
+func TestRiskManager_dayStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rule := &entity.RiskRule{AccountID: "acc", Env: entity.ProdEnv, Type: entity.MaxDailyLoss, Currency: "rub",
+		Value: decimal.NewFromInt(100), DayStart: "2000-01-01", DayStartValue: decimal.NewFromInt(5000)}
+	//Sandbox account is valuated by sandbox risk manager only
+	sdxRule := &entity.RiskRule{AccountID: "sdx", Env: entity.SandboxEnv, Type: entity.MaxDailyLoss, Currency: "rub",
+		Value: decimal.NewFromInt(100)}
+	rules := &ruleRepStub{rules: []*entity.RiskRule{rule, sdxRule}}
+	infoSrv := mock_service.NewMockInfoSrv(ctrl)
+	for _, m := range []int64{1000, 900} {
+		infoSrv.EXPECT().GetPositions(gomock.Any(), gomock.Any()).Return(&dtotapi.PositionsResponse{
+			Money: []*dtotapi.MoneyValue{{Currency: "rub", Value: decimal.NewFromInt(m)}},
+		}, nil).Times(1)
+	}
+	rm := NewRiskManager(entity.ProdEnv, infoSrv, rules, zap.NewNop().Sugar()).(*DefaultRiskManager)
+
+	//Value of previous day is replaced at day start
+	rm.takeDayStart(context.Background())
+	assert.Equal(t, time.Now().Format("2006-01-02"), rule.DayStart)
+	assert.True(t, rule.DayStartValue.Equal(decimal.NewFromInt(1000)), "got %s", rule.DayStartValue)
+	rm.takeDayStart(context.Background()) //Value of the current day is already taken - no valuation
+	assert.Equal(t, "", sdxRule.DayStart)
+
+	//Loss is counted from day start value, not from the first check
+	order := &trmodel.RiskOrder{AccountId: "acc", Figi: "a", Currency: "rub", Opening: true, Amount: decimal.NewFromInt(10)}
+	assert.NotNil(t, rm.Check(order, context.Background()), "Loss 100 reached limit")
+	assert.Equal(t, rule.DayStart, rule.HaltedDay)
+}
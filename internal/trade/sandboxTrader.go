@@ -16,20 +16,30 @@ type SandboxTrader struct {
 
 func (t *SandboxTrader) Go(ctx context.Context) {
 	t.ctx = ctx
+	if t.riskMgr != nil {
+		t.riskMgr.Go(ctx)
+	}
 	go t.checkOrdersBg()
 	go t.actionProcBg()
 }
 
-func NewSandboxTrader(infoSrv service.InfoSrv, tradeSrv service.TradeService, actionRep repository.ActionRepository, logger *zap.SugaredLogger) Trader {
+func NewSandboxTrader(infoSrv service.InfoSrv, tradeSrv service.TradeService, actionRep repository.ActionRepository,
+	stopRep repository.StopOrderRepository, riskMgr RiskManager, budget BudgetManager,
+	logger *zap.SugaredLogger) Trader {
 	return &SandboxTrader{
 		&BaseTrader{
-			infoSrv:   infoSrv,
-			tradeSrv:  tradeSrv,
-			actionRep: actionRep,
-			subs:      collections.NewSyncMap[uint, *stmodel.Subscription](),
-			orders:    collections.NewSyncMap[string, *entity.Action](),
-			algoCh:    make(chan *stmodel.ActionReq, 1),
-			logger:    logger,
+			infoSrv:    infoSrv,
+			tradeSrv:   tradeSrv,
+			actionRep:  actionRep,
+			stopRep:    stopRep,
+			riskMgr:    riskMgr,
+			budget:     budget,
+			subs:       collections.NewSyncMap[uint, *stmodel.Subscription](),
+			orders:     collections.NewSyncMap[string, *entity.Action](),
+			stopOrders: collections.NewSyncMap[string, *entity.StopOrder](),
+			streams:    make(map[string]bool),
+			algoCh:     make(chan *stmodel.ActionReq, 1),
+			logger:     logger,
 		},
 	}
 }
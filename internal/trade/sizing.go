@@ -0,0 +1,66 @@
+package trade
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/sizing"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/trmodel"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+//atrHistory is a period of minute candles requested to calculate average true range
+const atrHistory = 24 * time.Hour
+
+//capByBudget restricts amount of lots sized by configured limit to money left of algorithm budget, budget is not tracked when nil
+func capByBudget(lots int64, lotPrice decimal.Decimal, budget *decimal.Decimal) int64 {
+	if budget == nil || !lotPrice.IsPositive() {
+		return lots
+	}
+	if maxLots := budget.Div(lotPrice).Floor().IntPart(); maxLots < lots {
+		if maxLots < 0 {
+			return 0
+		}
+		return maxLots
+	}
+	return lots
+}
+
+//sizeLots returns amount of lots to buy by algorithm sizing policy, zero if policy amount is lower than one lot.
+//Average true range is calculated by last minute candles when policy requires it
+func (t *BaseTrader) sizeLots(opInfo *trmodel.OpInfo, figi string, policy *sizing.Policy) int64 {
+	order := sizing.Order{Lim: opInfo.Lim, PosPrice: opInfo.PosPrice, PosInLot: opInfo.PosInLot}
+	if policy.NeedsAtr() {
+		now := time.Now()
+		hist, err := t.infoSrv.GetHistorySorted([]string{figi}, investapi.CandleInterval_CANDLE_INTERVAL_1_MIN,
+			now.Add(-atrHistory), now, t.ctx)
+		if err != nil {
+			t.logger.Errorf("Error while requesting candles of %s to calculate average true range: %s", figi, err)
+			return 0
+		}
+		bars := make([]sizing.Bar, 0, len(hist))
+		for _, rec := range hist {
+			bars = append(bars, sizing.Bar{High: rec.High, Low: rec.Low, Close: rec.Close})
+		}
+		order.Atr = sizing.Atr(bars, policy.AtrPeriod)
+		t.logger.Infof("Average true range of %s by %d candles: %s", figi, policy.AtrPeriod, order.Atr)
+	}
+	return policy.Lots(&order)
+}
+
+//sizeLots returns amount of lots to buy by algorithm sizing policy in history simulation,
+//average true range is calculated by history candles before the time
+func (t *MockTrader) sizeLots(opInfo trmodel.OpInfo, figi string, tm time.Time) int64 {
+	order := sizing.Order{Lim: opInfo.Lim, PosPrice: opInfo.PosPrice, PosInLot: opInfo.PosInLot}
+	policy := t.sub.Sizing
+	if policy.NeedsAtr() {
+		bars := make([]sizing.Bar, 0)
+		for _, rec := range t.figiHist[figi] {
+			if rec.Time.After(tm) {
+				break
+			}
+			bars = append(bars, sizing.Bar{High: rec.High, Low: rec.Low, Close: rec.Price})
+		}
+		order.Atr = sizing.Atr(bars, policy.AtrPeriod)
+	}
+	return policy.Lots(&order)
+}
@@ -0,0 +1,358 @@
+package trade
+
+import (
+	"fmt"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/strategy/stmodel"
+	"github.com/shopspring/decimal"
+	"sort"
+	"time"
+)
+
+//placeStopOrders posts broker stop loss and take profit orders for position opened by filled action if algorithm requested them:
+//sell stop orders for bought position and buy stop orders for short one.
+//Errors are only logged - position stays under control of algorithm
+func (t *BaseTrader) placeStopOrders(action *entity.Action) {
+	short := action.Direction == entity.Sell
+	if (short && !action.Short) || action.LotsExecuted == 0 ||
+		(!action.StopLoss.IsPositive() && !action.TakeProfit.IsPositive()) {
+		return
+	}
+	instrInfo, err := t.infoSrv.GetInstrumentInfoByFigi(action.InstrFigi, t.ctx)
+	if err != nil {
+		t.logger.Errorf("Error getting instrument info, stop orders for action %d not placed: %s", action.ID, err)
+		return
+	}
+	hundred := decimal.NewFromInt(100)
+	if short {
+		//Short position loses on price growth and profits on price drop
+		if action.StopLoss.IsPositive() {
+			price := action.PositionPrice.Mul(hundred.Add(action.StopLoss)).Div(hundred)
+			t.postStopOrder(action, entity.StopLoss, t.normalizePriceUp(price, instrInfo.MinPriceIncrement), true)
+		}
+		if action.TakeProfit.IsPositive() && action.TakeProfit.LessThan(hundred) {
+			price := action.PositionPrice.Mul(hundred.Sub(action.TakeProfit)).Div(hundred)
+			t.postStopOrder(action, entity.TakeProfit, t.normalizePriceDown(price, instrInfo.MinPriceIncrement), true)
+		}
+		return
+	}
+	if action.StopLoss.IsPositive() {
+		price := action.PositionPrice.Mul(hundred.Sub(action.StopLoss)).Div(hundred)
+		t.postStopOrder(action, entity.StopLoss, t.normalizePriceDown(price, instrInfo.MinPriceIncrement), false)
+	}
+	if action.TakeProfit.IsPositive() {
+		price := action.PositionPrice.Mul(hundred.Add(action.TakeProfit)).Div(hundred)
+		t.postStopOrder(action, entity.TakeProfit, t.normalizePriceUp(price, instrInfo.MinPriceIncrement), false)
+	}
+}
+
+//postStopOrder posts stop order of requested type for all executed lots of action and starts its monitoring,
+//stop order of short position buys instrument, otherwise sells it
+func (t *BaseTrader) postStopOrder(action *entity.Action, stopType entity.StopOrderType, price decimal.Decimal, short bool) {
+	stopOrder := entity.StopOrder{
+		AlgorithmID: action.AlgorithmID,
+		ActionID:    action.ID,
+		AccountID:   action.AccountID,
+		InstrFigi:   action.InstrFigi,
+		Type:        stopType,
+		StopPrice:   price,
+		LotAmount:   action.LotsExecuted,
+		Currency:    action.Currency,
+		Status:      entity.StopActive,
+		Short:       short,
+	}
+	t.sendStopOrder(&stopOrder)
+}
+
+//sendStopOrder posts stop order to broker, persists it with received id and starts its monitoring
+func (t *BaseTrader) sendStopOrder(stopOrder *entity.StopOrder) {
+	req := dtotapi.PostStopOrderRequest{
+		Figi:          stopOrder.InstrFigi,
+		PosNum:        stopOrder.LotAmount,
+		StopPrice:     stopOrder.StopPrice,
+		Direction:     dtotapi.OrderDirectionSell,
+		AccountId:     stopOrder.AccountID,
+		StopOrderType: dtotapi.StopOrderTypeStopLoss,
+	}
+	if stopOrder.Type == entity.TakeProfit {
+		req.StopOrderType = dtotapi.StopOrderTypeTakeProfit
+	}
+	if stopOrder.Short {
+		req.Direction = dtotapi.OrderDirectionBuy
+	}
+	resp, err := t.tradeSrv.PostStopOrder(&req, t.ctx)
+	if err != nil {
+		t.logger.Errorf("Error posting %s stop order %+v: %s", stopOrder.Type, req, err)
+		return
+	}
+	stopOrder.StopOrderId = resp.StopOrderId
+	if err = t.stopRep.Save(stopOrder); err != nil {
+		t.logger.Errorf("Error while saving stop order %+v: %s", stopOrder, err)
+	}
+	t.logger.Infof("Posted %s stop order %s by price %s for action %d", stopOrder.Type, resp.StopOrderId,
+		stopOrder.StopPrice, stopOrder.ActionID)
+	t.stopOrders.Put(resp.StopOrderId, stopOrder)
+}
+
+//updateStopOrders adjusts broker stop orders by lots executed by action: stop orders of position closed by algorithm
+//(bought one for a sell, short one for a buy) are reduced, stop orders for opened position are placed
+func (t *BaseTrader) updateStopOrders(action *entity.Action) {
+	if action.LotsExecuted == 0 {
+		return
+	}
+	if action.Direction == entity.Buy || !action.Short {
+		t.reduceStopOrders(action.AlgorithmID, action.InstrFigi, action.Direction == entity.Buy, action.LotsExecuted)
+	}
+	t.placeStopOrders(action)
+}
+
+//reduceStopOrders decreases stop orders of algorithm position by instrument by closed lots: stop orders of opening actions
+//are canceled while closed lots cover them, stop orders of partially closed one are replaced by ones for remaining lots
+func (t *BaseTrader) reduceStopOrders(algoID uint, figi string, short bool, lots int64) {
+	pairs := make(map[uint][]*stopEntry)
+	var actionIds []uint
+	for _, entry := range t.stopOrders.GetSlice() {
+		stopOrder := entry.Value
+		if stopOrder.AlgorithmID != algoID || stopOrder.InstrFigi != figi || stopOrder.Short != short {
+			continue
+		}
+		if _, ok := pairs[stopOrder.ActionID]; !ok {
+			actionIds = append(actionIds, stopOrder.ActionID)
+		}
+		pairs[stopOrder.ActionID] = append(pairs[stopOrder.ActionID], &stopEntry{id: entry.Key, stopOrder: stopOrder})
+	}
+	//Earlier opened lots are closed first
+	sort.Slice(actionIds, func(i, j int) bool { return actionIds[i] < actionIds[j] })
+	for _, actionId := range actionIds {
+		if lots <= 0 {
+			return
+		}
+		protected := pairs[actionId][0].stopOrder.LotAmount
+		for _, entry := range pairs[actionId] {
+			if protected <= lots {
+				t.cancelStopOrder(entry.id, entry.stopOrder)
+			} else {
+				t.resizeStopOrder(entry.id, entry.stopOrder, protected-lots)
+			}
+		}
+		lots -= protected
+	}
+}
+
+//stopEntry is monitored stop order with its broker id
+type stopEntry struct {
+	id        string
+	stopOrder *entity.StopOrder
+}
+
+//cancelPairedStopOrder cancels stop order of opposite type placed for the same action as executed one,
+//if executed stop order closed only part of position, paired one is replaced by stop order for remaining lots
+func (t *BaseTrader) cancelPairedStopOrder(executed *entity.StopOrder, lots int64) {
+	for _, entry := range t.stopOrders.GetSlice() {
+		stopOrder := entry.Value
+		if stopOrder.AlgorithmID != executed.AlgorithmID || stopOrder.ActionID != executed.ActionID ||
+			stopOrder.Type == executed.Type {
+			continue
+		}
+		if remaining := stopOrder.LotAmount - lots; remaining > 0 {
+			t.resizeStopOrder(entry.Key, stopOrder, remaining)
+		} else {
+			t.cancelStopOrder(entry.Key, stopOrder)
+		}
+	}
+}
+
+//resizeStopOrder replaces stop order by the one with the same price for requested lots
+func (t *BaseTrader) resizeStopOrder(stopOrderId string, stopOrder *entity.StopOrder, lots int64) {
+	if !t.cancelStopOrder(stopOrderId, stopOrder) {
+		return
+	}
+	resized := entity.StopOrder{
+		AlgorithmID: stopOrder.AlgorithmID,
+		ActionID:    stopOrder.ActionID,
+		AccountID:   stopOrder.AccountID,
+		InstrFigi:   stopOrder.InstrFigi,
+		Type:        stopOrder.Type,
+		StopPrice:   stopOrder.StopPrice,
+		LotAmount:   lots,
+		Currency:    stopOrder.Currency,
+		Status:      entity.StopActive,
+		Short:       stopOrder.Short,
+	}
+	t.sendStopOrder(&resized)
+}
+
+//cancelStopOrders cancels active stop orders of algorithm by instrument - whole position is closed by algorithm itself
+func (t *BaseTrader) cancelStopOrders(algoID uint, figi string) {
+	for _, entry := range t.stopOrders.GetSlice() {
+		stopOrder := entry.Value
+		if stopOrder.AlgorithmID != algoID || stopOrder.InstrFigi != figi {
+			continue
+		}
+		t.cancelStopOrder(entry.Key, stopOrder)
+	}
+}
+
+//cancelStopOrder cancels stop order at broker and stops its monitoring, returns false if cancel failed
+func (t *BaseTrader) cancelStopOrder(stopOrderId string, stopOrder *entity.StopOrder) bool {
+	req := dtotapi.CancelStopOrderRequest{AccountId: stopOrder.AccountID, StopOrderId: stopOrderId}
+	if _, err := t.tradeSrv.CancelStopOrder(&req, t.ctx); err != nil {
+		//Stop order stays monitored - if it was executed, algorithm will be notified by check
+		t.logger.Errorf("Error while canceling stop order %s: %s", stopOrderId, err)
+		return false
+	}
+	t.stopOrders.Delete(stopOrderId)
+	t.saveStopOrderWithStatus(stopOrder, entity.StopCanceled)
+	t.logger.Infof("Stop order %s canceled", stopOrderId)
+	return true
+}
+
+//checkStopOrders compares monitored stop orders with active ones of accounts.
+//Stop order missing in active ones is considered executed only when broker reports it executed
+func (t *BaseTrader) checkStopOrders() {
+	sl := t.stopOrders.GetSlice()
+	active := make(map[string]*dtotapi.StopOrdersResponse)
+	for _, entry := range sl {
+		stopOrder := entry.Value
+		resp, ok := active[stopOrder.AccountID]
+		if !ok {
+			var err error
+			resp, err = t.tradeSrv.GetStopOrders(&dtotapi.StopOrdersRequest{AccountId: stopOrder.AccountID}, t.ctx)
+			if err != nil {
+				t.logger.Errorf("Error checking stop orders of account %s: %s", stopOrder.AccountID, err)
+				continue
+			}
+			active[stopOrder.AccountID] = resp
+		}
+		if !resp.Contains(entry.Key) {
+			t.processStopMissing(entry.Key, stopOrder)
+		}
+	}
+}
+
+//stopExecution is a sell (buy for short position) of stop order confirmed by its exchange order
+type stopExecution struct {
+	lots  int64           //Executed lots
+	price decimal.Decimal //Average price of single instrument
+	total decimal.Decimal //Money amount of executed lots
+}
+
+//processStopMissing processes stop order missing in active ones: stop order executed by broker if its exchange order is found,
+//otherwise it's canceled or expired - it's marked canceled and algorithm is not notified as position is not changed
+func (t *BaseTrader) processStopMissing(stopOrderId string, stopOrder *entity.StopOrder) {
+	execution, err := t.findStopExecution(stopOrder)
+	if err != nil {
+		//Stop order stays monitored - execution will be checked again
+		t.logger.Errorf("Error while confirming execution of stop order %s: %s", stopOrderId, err)
+		return
+	}
+	if execution == nil {
+		t.logger.Warnf("Stop order %s of algorithm %d is not active and not executed, considered canceled",
+			stopOrderId, stopOrder.AlgorithmID)
+		t.stopOrders.Delete(stopOrderId)
+		t.saveStopOrderWithStatus(stopOrder, entity.StopCanceled)
+		return
+	}
+	t.processStopExecuted(stopOrderId, stopOrder, execution)
+}
+
+//findStopExecution looks for stop order in executed ones of account and takes execution from exchange order
+//it was converted to, returns nil if stop order is not executed. Not more than stop order lots are attributed to it
+func (t *BaseTrader) findStopExecution(stopOrder *entity.StopOrder) (*stopExecution, error) {
+	req := dtotapi.StopOrdersRequest{
+		AccountId: stopOrder.AccountID,
+		Status:    dtotapi.StopOrderStatusExecuted,
+		From:      stopOrder.CreatedAt,
+		To:        time.Now(),
+	}
+	resp, err := t.tradeSrv.GetStopOrders(&req, t.ctx)
+	if err != nil {
+		return nil, err
+	}
+	executed := resp.Find(stopOrder.StopOrderId)
+	if executed == nil {
+		return nil, nil
+	}
+	if executed.ExchangeOrderId == "" {
+		return nil, fmt.Errorf("executed stop order %s has no exchange order", stopOrder.StopOrderId)
+	}
+	stateReq := dtotapi.OrderStateRequest{AccountId: stopOrder.AccountID, OrderId: executed.ExchangeOrderId}
+	state, err := t.infoSrv.GetOrderState(&stateReq, t.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state.LotsExec == 0 || state.AvrPrice == nil || state.ExecPrice == nil {
+		return nil, nil
+	}
+	lots := state.LotsExec
+	total := state.ExecPrice.Value
+	if lots > stopOrder.LotAmount {
+		lots = stopOrder.LotAmount
+		total = total.Mul(decimal.NewFromInt(lots)).Div(decimal.NewFromInt(state.LotsExec))
+	}
+	return &stopExecution{lots: lots, price: state.AvrPrice.Value, total: total}, nil
+}
+
+//processStopExecuted updates executed stop order, cancels paired one placed for the same action
+//and notifies algorithm with action made by broker - sell of bought position or buy covering short one
+func (t *BaseTrader) processStopExecuted(stopOrderId string, stopOrder *entity.StopOrder, execution *stopExecution) {
+	t.logger.Infof("Stop order %s of algorithm %d executed: %d lots by %s", stopOrderId, stopOrder.AlgorithmID,
+		execution.lots, execution.price)
+	t.stopOrders.Delete(stopOrderId)
+	t.saveStopOrderWithStatus(stopOrder, entity.StopExecuted)
+	t.cancelPairedStopOrder(stopOrder, execution.lots)
+
+	action := entity.Action{
+		AlgorithmID:   stopOrder.AlgorithmID,
+		AccountID:     stopOrder.AccountID,
+		Direction:     entity.Sell,
+		InstrFigi:     stopOrder.InstrFigi,
+		LotAmount:     stopOrder.LotAmount,
+		LotsExecuted:  execution.lots,
+		OrderType:     entity.Market,
+		Status:        entity.Success,
+		Info:          fmt.Sprintf("Executed by broker %s stop order", stopOrder.Type),
+		Currency:      stopOrder.Currency,
+		PositionPrice: execution.price,
+		TotalPrice:    execution.total,
+		OrderId:       stopOrderId,
+		RetrievedAt:   time.Now(),
+	}
+	if stopOrder.Short {
+		action.Direction = entity.Buy
+	}
+	if execution.lots < stopOrder.LotAmount {
+		action.Status = entity.Canceled
+		action.Info = fmt.Sprintf("Partially executed by broker %s stop order", stopOrder.Type)
+	}
+	if err := t.actionRep.Save(&action); err != nil {
+		t.logger.Errorf("Error while saving stop order action %+v: %s", action, err)
+	}
+	t.settleBudget(&action, decimal.Zero)
+	sub, ok := t.subs.Get(stopOrder.AlgorithmID)
+	if !ok {
+		t.logger.Warnf("Subscription by id %d not found, algorithm not notified about stop order", stopOrder.AlgorithmID)
+		return
+	}
+	sub.RChan <- &stmodel.ActionResp{Action: &action}
+}
+
+//Set status to stop order and persists it in db
+func (t *BaseTrader) saveStopOrderWithStatus(stopOrder *entity.StopOrder, status entity.StopOrderStatus) {
+	stopOrder.Status = status
+	if err := t.stopRep.Save(stopOrder); err != nil {
+		t.logger.Error("Error while saving stop order, skipping update...", err)
+	}
+}
+
+//RestoreStopOrders puts active stop orders to the stop orders map, so they will be checked by background task
+func (t *BaseTrader) RestoreStopOrders(stopOrders []*entity.StopOrder) {
+	for _, stopOrder := range stopOrders {
+		if stopOrder.Status != entity.StopActive || stopOrder.StopOrderId == "" {
+			continue
+		}
+		t.logger.Infof("Restoring stop order %s of algorithm %d", stopOrder.StopOrderId, stopOrder.AlgorithmID)
+		t.stopOrders.Put(stopOrder.StopOrderId, stopOrder)
+	}
+}
@@ -0,0 +1,123 @@
+package trade
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/collections"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	mock_service "github.com/ldmi3i/tinkoff-invest-bot/internal/mocks/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestFindStopExecution(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	infoSrv := mock_service.NewMockInfoSrv(ctrl)
+	tradeSrv := mock_service.NewMockTradeService(ctrl)
+	trader := &BaseTrader{infoSrv: infoSrv, tradeSrv: tradeSrv, ctx: context.Background(), logger: zap.NewNop().Sugar()}
+	stopOrder := &entity.StopOrder{AccountID: "acc", InstrFigi: "a", LotAmount: 2, StopPrice: decimal.NewFromInt(90), StopOrderId: "stop"}
+	rub := func(v int64) *dtotapi.MoneyValue {
+		return &dtotapi.MoneyValue{Currency: "rub", Value: decimal.NewFromInt(v)}
+	}
+
+	//Stop order is not in executed ones - unrelated executed stop order is not attributed to it
+	tradeSrv.EXPECT().GetStopOrders(gomock.Any(), gomock.Any()).Return(&dtotapi.StopOrdersResponse{StopOrders: []*dtotapi.StopOrder{
+		{StopOrderId: "other", Figi: "a", ExchangeOrderId: "otherOrder", Status: dtotapi.StopOrderStatusExecuted},
+	}}, nil)
+	execution, err := trader.findStopExecution(stopOrder)
+	assert.Nil(t, err)
+	assert.Nil(t, execution)
+
+	//Execution is taken from exchange order of stop order, unrelated sell of instrument after it was posted is not counted
+	tradeSrv.EXPECT().GetStopOrders(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(req *dtotapi.StopOrdersRequest, ctx context.Context) (*dtotapi.StopOrdersResponse, error) {
+			assert.Equal(t, dtotapi.StopOrderStatusExecuted, req.Status)
+			return &dtotapi.StopOrdersResponse{StopOrders: []*dtotapi.StopOrder{
+				{StopOrderId: "other", Figi: "a", ExchangeOrderId: "unrelated", Status: dtotapi.StopOrderStatusExecuted},
+				{StopOrderId: "stop", Figi: "a", ExchangeOrderId: "order", Status: dtotapi.StopOrderStatusExecuted},
+			}}, nil
+		})
+	infoSrv.EXPECT().GetOrderState(&dtotapi.OrderStateRequest{AccountId: "acc", OrderId: "order"}, gomock.Any()).
+		Return(&dtotapi.OrderStateResponse{OrderId: "order", LotsExec: 2, AvrPrice: rub(88), ExecPrice: rub(1760)}, nil)
+	infoSrv.EXPECT().GetOrderState(&dtotapi.OrderStateRequest{AccountId: "acc", OrderId: "unrelated"}, gomock.Any()).
+		Return(&dtotapi.OrderStateResponse{OrderId: "unrelated", LotsExec: 5, AvrPrice: rub(95), ExecPrice: rub(4750)}, nil).Times(0)
+	execution, err = trader.findStopExecution(stopOrder)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), execution.lots)
+	assert.True(t, execution.price.Equal(decimal.NewFromInt(88)), "got %s", execution.price)
+	assert.True(t, execution.total.Equal(decimal.NewFromInt(1760)), "got %s", execution.total)
+
+	//Not more than stop order lots are attributed to it
+	shortStop := &entity.StopOrder{AccountID: "acc", InstrFigi: "a", LotAmount: 1, StopPrice: decimal.NewFromInt(110), Short: true, StopOrderId: "short"}
+	tradeSrv.EXPECT().GetStopOrders(gomock.Any(), gomock.Any()).Return(&dtotapi.StopOrdersResponse{StopOrders: []*dtotapi.StopOrder{
+		{StopOrderId: "short", Figi: "a", ExchangeOrderId: "buy", Status: dtotapi.StopOrderStatusExecuted},
+	}}, nil)
+	infoSrv.EXPECT().GetOrderState(&dtotapi.OrderStateRequest{AccountId: "acc", OrderId: "buy"}, gomock.Any()).
+		Return(&dtotapi.OrderStateResponse{OrderId: "buy", LotsExec: 2, AvrPrice: rub(111), ExecPrice: rub(2220)}, nil)
+	execution, err = trader.findStopExecution(shortStop)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), execution.lots)
+	assert.True(t, execution.price.Equal(decimal.NewFromInt(111)), "got %s", execution.price)
+	assert.True(t, execution.total.Equal(decimal.NewFromInt(1110)), "got %s", execution.total)
+}
+
+//stopRepStub keeps saved stop orders statuses in memory
+type stopRepStub struct {
+	statuses map[string]entity.StopOrderStatus
+}
+
+func (r *stopRepStub) Save(stopOrder *entity.StopOrder) error {
+	r.statuses[stopOrder.StopOrderId] = stopOrder.Status
+	return nil
+}
+
+func TestUpdateStopOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tradeSrv := mock_service.NewMockTradeService(ctrl)
+	rep := &stopRepStub{statuses: make(map[string]entity.StopOrderStatus)}
+	trader := &BaseTrader{tradeSrv: tradeSrv, stopRep: rep, stopOrders: collections.NewSyncMap[string, *entity.StopOrder](),
+		ctx: context.Background(), logger: zap.NewNop().Sugar()}
+	stop := func(id string, actionId uint, stopType entity.StopOrderType, lots int64) {
+		trader.stopOrders.Put(id, &entity.StopOrder{AlgorithmID: 1, ActionID: actionId, AccountID: "acc", InstrFigi: "a",
+			Type: stopType, StopPrice: decimal.NewFromInt(90), LotAmount: lots, StopOrderId: id, Status: entity.StopActive})
+	}
+	stop("sl1", 1, entity.StopLoss, 2)
+	stop("tp1", 1, entity.TakeProfit, 2)
+	stop("sl2", 2, entity.StopLoss, 3)
+	stop("tp2", 2, entity.TakeProfit, 3)
+
+	//Sell of 3 lots closes position of first buy and 1 lot of second one - second buy stop orders are resized to 2 lots
+	tradeSrv.EXPECT().CancelStopOrder(gomock.Any(), gomock.Any()).Return(&dtotapi.CancelStopOrderResponse{}, nil).Times(4)
+	posted := 0
+	tradeSrv.EXPECT().PostStopOrder(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(req *dtotapi.PostStopOrderRequest, ctx context.Context) (*dtotapi.PostStopOrderResponse, error) {
+			assert.Equal(t, int64(2), req.PosNum)
+			assert.Equal(t, dtotapi.OrderDirectionSell, req.Direction)
+			posted++
+			return &dtotapi.PostStopOrderResponse{StopOrderId: fmt.Sprintf("resized%d", posted)}, nil
+		}).Times(2)
+	trader.updateStopOrders(&entity.Action{AlgorithmID: 1, InstrFigi: "a", Direction: entity.Sell, LotAmount: 3, LotsExecuted: 3})
+	for _, id := range []string{"sl1", "tp1", "sl2", "tp2"} {
+		assert.Equal(t, entity.StopCanceled, rep.statuses[id], id)
+	}
+	assert.Equal(t, 2, len(trader.stopOrders.GetSlice()))
+	for _, entry := range trader.stopOrders.GetSlice() {
+		assert.Equal(t, uint(2), entry.Value.ActionID)
+		assert.Equal(t, int64(2), entry.Value.LotAmount)
+	}
+
+	//Executed stop loss cancels only take profit of the same action
+	stop("sl3", 3, entity.StopLoss, 1)
+	stop("tp3", 3, entity.TakeProfit, 1)
+	tradeSrv.EXPECT().CancelStopOrder(&dtotapi.CancelStopOrderRequest{AccountId: "acc", StopOrderId: "tp3"}, gomock.Any()).
+		Return(&dtotapi.CancelStopOrderResponse{}, nil)
+	executed, _ := trader.stopOrders.Get("sl3")
+	trader.stopOrders.Delete("sl3")
+	trader.cancelPairedStopOrder(executed, 1)
+	assert.Equal(t, entity.StopCanceled, rep.statuses["tp3"])
+	assert.Equal(t, 2, len(trader.stopOrders.GetSlice()))
+}
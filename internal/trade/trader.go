@@ -14,22 +14,41 @@ import (
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/trade/trmodel"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
+	"sync"
 	"time"
 )
 
 type Trader interface {
 	AddSubscription(sub *stmodel.Subscription) error
 	RemoveSubscription(id uint) error
+	//RestoreOrders re-attaches previously posted orders to monitor their state and notify subscribed algorithms
+	RestoreOrders(actions []*entity.Action)
+	//RestoreStopOrders re-attaches previously posted broker stop orders to monitor their execution
+	RestoreStopOrders(stopOrders []*entity.StopOrder)
+	//CancelOrders cancels posted orders of algorithm without notifying it, returns ids of canceled orders
+	CancelOrders(algoId uint) ([]string, error)
+	//ClosePositions closes positions opened by algorithm with market orders, returns posted closing actions
+	ClosePositions(algoId uint, accountId string) ([]*entity.Action, error)
+	//GetBudgets returns money of algorithm tracked across its orders
+	GetBudgets(algoId uint) []*entity.AlgoBudget
 	Go(ctx context.Context)
 }
 
 type BaseTrader struct {
-	infoSrv   service.InfoSrv
-	tradeSrv  service.TradeService
-	actionRep repository.ActionRepository
-	subs      collections.SyncMap[uint, *stmodel.Subscription]
-	orders    collections.SyncMap[string, *entity.Action]
-	ctx       context.Context
+	infoSrv    service.InfoSrv
+	tradeSrv   service.TradeService
+	actionRep  repository.ActionRepository
+	stopRep    repository.StopOrderRepository
+	riskMgr    RiskManager   //Account-wide risk rules stage, nil - no rules checked
+	budget     BudgetManager //Money of algorithms tracked across orders, nil - whole limit is available for each order
+	subs       collections.SyncMap[uint, *stmodel.Subscription]
+	orders     collections.SyncMap[string, *entity.Action]
+	stopOrders collections.SyncMap[string, *entity.StopOrder]
+	ordMu      sync.Mutex //Prevents simultaneous processing of the same order by trades stream and polling
+	useStream  bool       //If true - orders are updated by trades stream of accounts, else only by polling
+	streams    map[string]bool
+	streamMu   sync.Mutex
+	ctx        context.Context
 
 	algoCh chan *stmodel.ActionReq
 	logger *zap.SugaredLogger
@@ -54,91 +73,151 @@ func (t *BaseTrader) subBg(sub *stmodel.Subscription) {
 }
 
 //checkOrdersBg provide periodical checks of active orders and notify algorithms about results
-//Also checks order estimation and cancel out of date orders
+//Also checks order estimation and cancel out of date orders, and execution of broker stop orders.
+//When trades stream is connected orders are updated by it and polling is used for reconciliation only
 func (t *BaseTrader) checkOrdersBg() {
 	t.logger.Info("Starting background checking orders...")
 	for {
+		t.checkStopOrders()
 		sl := t.orders.GetSlice()
 		t.logger.Debug("Check orders, len ", len(sl))
 		for _, entry := range sl {
-			action := entry.Value
-			req := dtotapi.OrderStateRequest{
+			t.checkOrder(entry.Key)
+		}
+		time.Sleep(t.pollInterval())
+	}
+}
+
+//checkOrder requests current order state, updates action and notifies algorithm when order is completed
+func (t *BaseTrader) checkOrder(orderId string) {
+	t.ordMu.Lock()
+	defer t.ordMu.Unlock()
+	action, ok := t.orders.Get(orderId)
+	if !ok {
+		return //Already processed by stream or polling
+	}
+	req := dtotapi.OrderStateRequest{
+		AccountId: action.AccountID,
+		OrderId:   orderId,
+	}
+	state, err := t.infoSrv.GetOrderState(&req, t.ctx)
+	if err != nil {
+		t.logger.Errorf("Error checking order state %+v: %s", req, err)
+		return
+	}
+	t.logger.Info("Check order with id ", orderId, " status ", state.ExecStatus)
+	sub, ok := t.subs.Get(action.AlgorithmID)
+	if !ok {
+		t.logger.Warn("Subscription by id ", action.ID, " not found")
+		t.orders.Delete(orderId)
+		return
+	}
+	reserved := t.reservedBy(action) //Captured before total price is updated by execution
+	switch state.ExecStatus {
+	case dtotapi.ExecutionReportStatusFill:
+		action.Status = entity.Success
+		action.Info = "Order successfully completed"
+		action.TotalPrice = state.TotalPrice.Value //Update total price to take into account commissions (from proto OrderState.total_order_amount)
+		action.Currency = state.TotalPrice.Currency
+		action.PositionPrice = state.AvrPrice.Value
+		action.LotsExecuted = state.LotsExec
+		err = t.actionRep.Save(action)
+		if err != nil {
+			t.logger.Errorf("Error while updating action %+v: %s", action, err)
+		}
+		t.orders.Delete(orderId)
+		t.settleBudget(action, reserved)
+		t.logger.Info("Order with id ", orderId, " completed")
+		t.updateStopOrders(action)
+		sub.RChan <- &stmodel.ActionResp{Action: action}
+	case dtotapi.ExecutionReportStatusRejected:
+		action.Status = entity.Failed
+		action.Info = "Order was rejected"
+		t.applyExecution(action, state)
+		err = t.actionRep.Save(action)
+		if err != nil {
+			t.logger.Errorf("Error while updating action %+v : %s", action, err)
+		}
+		t.orders.Delete(orderId)
+		t.settleBudget(action, reserved)
+		t.logger.Infof("Order with id %s rejected", orderId)
+		t.updateStopOrders(action)
+		sub.RChan <- &stmodel.ActionResp{Action: action}
+	case dtotapi.ExecutionReportStatusCancelled:
+		action.Status = entity.Canceled
+		action.Info = "Order was canceled"
+		t.applyExecution(action, state)
+		err = t.actionRep.Save(action)
+		if err != nil {
+			t.logger.Errorf("Error while updating action %+v: %s", action, err)
+		}
+		t.orders.Delete(orderId)
+		t.settleBudget(action, reserved)
+		t.logger.Infof("Order with id %s canceled", orderId)
+		t.updateStopOrders(action)
+		sub.RChan <- &stmodel.ActionResp{Action: action}
+	case dtotapi.ExecutionReportStatusPartiallyfill, dtotapi.ExecutionReportStatusNew:
+		if state.LotsExec != action.LotsExecuted {
+			action.LotsExecuted = state.LotsExec
+			if err = t.actionRep.Save(action); err != nil {
+				t.logger.Errorf("Error while updating action %+v: %s", action, err)
+			}
+		}
+		t.logger.Debugf("Check expiration time  %s, %s", action.ExpirationTime, time.Now())
+		if action.ExpirationTime.Before(time.Now()) {
+			t.logger.Infof("Canceling order %s by expiration time...", orderId)
+			cReq := dtotapi.CancelOrderRequest{
 				AccountId: action.AccountID,
-				OrderId:   entry.Key,
+				OrderId:   orderId,
 			}
-			state, err := t.infoSrv.GetOrderState(&req, t.ctx)
+			cResp, err := t.tradeSrv.CancelOrder(&cReq, t.ctx)
 			if err != nil {
-				t.logger.Errorf("Error checking order state %+v: %s", req, err)
-				continue
+				t.logger.Error("Error while canceling order: ")
+				return
 			}
-			t.logger.Info("Check order with id ", entry.Key, " status ", state.ExecStatus)
-			sub, ok := t.subs.Get(action.AlgorithmID)
-			if !ok {
-				t.logger.Warn("Subscription by id ", action.ID, " not found")
-				t.orders.Delete(entry.Key)
-				continue
+			t.orders.Delete(orderId)
+			t.logger.Info("Order was canceled successfully: ", cResp)
+			//Lots may be executed between state check and cancel - final state required to report them to algorithm
+			if fState, err := t.infoSrv.GetOrderState(&req, t.ctx); err == nil {
+				state = fState
+			} else {
+				t.logger.Errorf("Error checking state of canceled order %s, last known state used: %s", orderId, err)
 			}
-			switch state.ExecStatus {
-			case dtotapi.ExecutionReportStatusFill:
-				action.Status = entity.Success
-				action.Info = "Order successfully completed"
-				action.TotalPrice = state.TotalPrice.Value //Update total price to take into account commissions (from proto OrderState.total_order_amount)
-				action.Currency = state.TotalPrice.Currency
-				action.PositionPrice = state.AvrPrice.Value
-				action.LotsExecuted = state.LotsExec
-				err = t.actionRep.Save(action)
-				if err != nil {
-					t.logger.Errorf("Error while updating action %+v: %s", action, err)
-				}
-				t.orders.Delete(entry.Key)
-				t.logger.Info("Order with id ", entry.Key, " completed")
-				sub.RChan <- &stmodel.ActionResp{Action: action}
-			case dtotapi.ExecutionReportStatusRejected:
-				action.Status = entity.Failed
-				action.Info = "Order was rejected"
-				err = t.actionRep.Save(action)
-				if err != nil {
-					t.logger.Errorf("Error while updating action %+v : %s", action, err)
-				}
-				t.orders.Delete(entry.Key)
-				t.logger.Infof("Order with id %s rejected", entry.Key)
-				sub.RChan <- &stmodel.ActionResp{Action: action}
-			case dtotapi.ExecutionReportStatusCancelled:
-				action.Status = entity.Canceled
-				action.Info = "Order was canceled"
-				err = t.actionRep.Save(action)
-				if err != nil {
-					t.logger.Errorf("Error while updating action %+v: %s", action, err)
-				}
-				t.orders.Delete(entry.Key)
-				t.logger.Infof("Order with id %s rejected", entry.Key)
-				sub.RChan <- &stmodel.ActionResp{Action: action}
-			case dtotapi.ExecutionReportStatusPartiallyfill, dtotapi.ExecutionReportStatusNew:
-				t.logger.Debugf("Check expiration time  %s, %s", action.ExpirationTime, time.Now())
-				if action.ExpirationTime.Before(time.Now()) {
-					t.logger.Infof("Canceling order %s by expiration time...", entry.Key)
-					cReq := dtotapi.CancelOrderRequest{
-						AccountId: action.AccountID,
-						OrderId:   entry.Key,
-					}
-					cResp, err := t.tradeSrv.CancelOrder(&cReq, t.ctx)
-					if err != nil {
-						t.logger.Error("Error while canceling order: ")
-						continue
-					}
-					t.orders.Delete(entry.Key)
-					t.logger.Info("Order was canceled successfully: ", cResp)
-					action.Status = entity.Canceled
-					action.Info = "Order was canceled"
-					err = t.actionRep.Save(action) //Full save required to persist previously made changes
-					if err != nil {
-						t.logger.Errorf("Error while updating action %+v: %s", action, err)
-					}
-					sub.RChan <- &stmodel.ActionResp{Action: action}
-				}
+			action.Status = entity.Canceled
+			action.Info = "Order was canceled"
+			t.applyExecution(action, state)
+			err = t.actionRep.Save(action) //Full save required to persist previously made changes
+			if err != nil {
+				t.logger.Errorf("Error while updating action %+v: %s", action, err)
+			}
+			t.settleBudget(action, reserved)
+			t.updateStopOrders(action)
+			sub.RChan <- &stmodel.ActionResp{Action: action}
+		}
+	}
+}
+
+//applyExecution updates not filled action by executed part of the order: lots, average price and executed amount with commission -
+//commission increases amount paid by buy and decreases amount received by sell
+func (t *BaseTrader) applyExecution(action *entity.Action, state *dtotapi.OrderStateResponse) {
+	action.LotsExecuted = state.LotsExec
+	if state.LotsExec == 0 {
+		return
+	}
+	action.Info = fmt.Sprintf("%s, executed %d of %d lots", action.Info, state.LotsExec, state.LotsReq)
+	if state.AvrPrice != nil {
+		action.PositionPrice = state.AvrPrice.Value
+	}
+	if state.ExecPrice != nil {
+		action.TotalPrice = state.ExecPrice.Value
+		action.Currency = state.ExecPrice.Currency
+		if state.ExecCommission != nil {
+			if action.Direction == entity.Sell {
+				action.TotalPrice = action.TotalPrice.Sub(state.ExecCommission.Value)
+			} else {
+				action.TotalPrice = action.TotalPrice.Add(state.ExecCommission.Value)
 			}
 		}
-		time.Sleep(30 * time.Second)
 	}
 }
 
@@ -206,6 +285,13 @@ func (t *BaseTrader) preprocessAction(req *stmodel.ActionReq, subscription *stmo
 		subscription.RChan <- &stmodel.ActionResp{Action: action}
 		return nil, false
 	}
+	//Check is short selling available for instrument
+	if action.Short && !instrInfo.ShortEnabledFlag {
+		t.logger.Errorf("Short selling of instrument with figi %s not available", action.InstrFigi)
+		t.setActionStatus(action, entity.Failed, "Short selling of instrument not available")
+		subscription.RChan <- &stmodel.ActionResp{Action: action}
+		return nil, false
+	}
 	//Check is trade session has ok status
 	if !instrInfo.IsTradingAvailable() {
 		t.logger.Warn("Exchange trading status has incorrect status.", instrInfo.TradingStatus)
@@ -214,13 +300,26 @@ func (t *BaseTrader) preprocessAction(req *stmodel.ActionReq, subscription *stmo
 		return nil, false
 	}
 	opInfo := trmodel.OpInfo{
-		Currency: action.Currency, Lim: req.GetCurrLimit(action.Currency), PosInLot: instrInfo.Lot, PriceStep: instrInfo.MinPriceIncrement}
+		Currency: action.Currency, Lim: req.GetCurrLimit(action.Currency), PosInLot: instrInfo.Lot, PriceStep: instrInfo.MinPriceIncrement,
+		ShortRate: instrInfo.DShort}
 	if opInfo.Lim.IsZero() {
 		t.logger.Warnf("Limit for currency %s not set, discarding order", action.Currency)
 		t.setActionStatus(action, entity.Failed, "Limit by requested currency not set")
 		subscription.RChan <- &stmodel.ActionResp{Action: action}
 		return nil, false
 	}
+	//Opening buy is restricted by money of algorithm left after previous orders, order is sized by configured limit
+	budgetLim, tracked := subscription.GetBudgetLimit(action.Currency)
+	if t.budget != nil && tracked && action.Direction == entity.Buy && action.LotAmount == 0 {
+		avail := t.budget.Available(action.AlgorithmID, action.Currency, budgetLim)
+		if !avail.IsPositive() {
+			t.logger.Warnf("Budget of algorithm %d in %s exhausted, discarding order", action.AlgorithmID, action.Currency)
+			t.setActionStatus(action, entity.Failed, "Algorithm budget exhausted")
+			subscription.RChan <- &stmodel.ActionResp{Action: action}
+			return nil, false
+		}
+		opInfo.Budget = &avail
+	}
 	//Retrieve last single position price
 	prices, err := t.infoSrv.GetLastPrices([]string{action.InstrFigi}, t.ctx)
 	if err != nil || prices.GetByFigi(action.InstrFigi) == nil {
@@ -239,20 +338,33 @@ func (t *BaseTrader) procBuy(opInfo *trmodel.OpInfo, action *entity.Action, sub
 	t.logger.Debug("Starting buy for action ", action.ID)
 	//Calculating price for single buy operation multiple to instrument weight
 	lotPrice := decimal.NewFromInt(opInfo.PosInLot).Mul(opInfo.PosPrice)
-	//Check is minimum instrument price exceed the limit
-	if lotPrice.GreaterThan(opInfo.Lim) {
-		t.logger.Warnf("Limit lower than minimal buy price, figi %s; limit: %s; lot price: %s; one price: %s",
-			action.InstrFigi, opInfo.Lim, opInfo.PosPrice, lotPrice)
-		t.setActionStatus(action, entity.Failed, "Price of one buy exceeds limit")
-		sub.RChan <- &stmodel.ActionResp{Action: action}
-		return
+	var moneyAmount decimal.Decimal
+	var lotAmount int64
+	if action.LotAmount > 0 {
+		//Requested amount (buy to cover short position) is not restricted by the limit - position must be closed
+		lotAmount = action.LotAmount
+		moneyAmount = lotPrice.Mul(decimal.NewFromInt(lotAmount))
+	} else {
+		//Check is minimum instrument price exceed the limit
+		if lotPrice.GreaterThan(opInfo.Lim) {
+			t.logger.Warnf("Limit lower than minimal buy price, figi %s; limit: %s; lot price: %s; one price: %s",
+				action.InstrFigi, opInfo.Lim, opInfo.PosPrice, lotPrice)
+			t.setActionStatus(action, entity.Failed, "Price of one buy exceeds limit")
+			sub.RChan <- &stmodel.ActionResp{Action: action}
+			return
+		}
+		//Calculate number of lots by algorithm sizing policy, amount is restricted by the limit and money left of budget
+		lotAmount = capByBudget(t.sizeLots(opInfo, action.InstrFigi, sub.Sizing), lotPrice, opInfo.Budget)
+		if lotAmount == 0 {
+			t.logger.Warnf("Sizing policy %+v gives no lots for figi %s; limit: %s; lot price: %s",
+				sub.Sizing, action.InstrFigi, opInfo.Lim, lotPrice)
+			t.setActionStatus(action, entity.Failed, "Sizing policy amount is lower than one lot")
+			sub.RChan <- &stmodel.ActionResp{Action: action}
+			return
+		}
+		//Calculate required amount of money for this order
+		moneyAmount = lotPrice.Mul(decimal.NewFromInt(lotAmount))
 	}
-	//Calculate number of weighted instruments available to buy for existing limit
-	operNum := opInfo.Lim.Div(lotPrice).Floor()
-	//Calculate required amount of money for this order
-	moneyAmount := operNum.Mul(lotPrice)
-	//Set instrument amount to buy
-	lotAmount := operNum.IntPart()
 	//Get real available money amount using GetPositions request
 	posReq := dtotapi.PositionsRequest{AccountId: action.AccountID}
 	positions, err := t.infoSrv.GetPositions(&posReq, t.ctx)
@@ -271,6 +383,12 @@ func (t *BaseTrader) procBuy(opInfo *trmodel.OpInfo, action *entity.Action, sub
 		sub.RChan <- &stmodel.ActionResp{Action: action}
 		return
 	}
+	//Buy by requested amount covers short position and doesn't increase exposure
+	riskOrder := trmodel.RiskOrder{AccountId: action.AccountID, Figi: action.InstrFigi, Currency: opInfo.Currency,
+		Opening: action.LotAmount == 0, Amount: moneyAmount}
+	if !t.checkRisk(&riskOrder, action, sub) {
+		return
+	}
 	//Prepare request and post order
 	orderId := uuid.New().String()
 	req := dtotapi.PostOrderRequest{
@@ -299,12 +417,19 @@ func (t *BaseTrader) procBuy(opInfo *trmodel.OpInfo, action *entity.Action, sub
 	//Set amounts of money to action and update action status in db
 	action.TotalPrice = moneyAmount //will be updated to take into account commissions if succeed
 	action.LotAmount = lotAmount
-	t.orders.Put(order.OrderId, action)
-	t.setActionStatus(action, entity.Posted, "Action posted successfully")
+	action.OrderId = order.OrderId
+	if t.budget != nil {
+		t.budget.Reserve(action.AlgorithmID, action.Currency, moneyAmount)
+	}
+	t.putOrder(order.OrderId, action)
+	t.saveActionWithStatus(action, entity.Posted, "Action posted successfully")
 }
 
-//Process sell order (currently there's no limits for a sell operation)
+//Process sell order (there's no limits for a sell operation of held instrument, short sell is limited by limit and margin)
 func (t *BaseTrader) procSell(opInfo *trmodel.OpInfo, action *entity.Action, sub *stmodel.Subscription) {
+	if action.Short && !t.prepareShort(opInfo, action, sub) {
+		return
+	}
 	//Check is requested instrument amount for a sell not zero
 	if action.LotAmount == 0 {
 		t.logger.Warn("LotAmount is 0 - nothing to sell")
@@ -312,6 +437,12 @@ func (t *BaseTrader) procSell(opInfo *trmodel.OpInfo, action *entity.Action, sub
 		sub.RChan <- &stmodel.ActionResp{Action: action}
 		return
 	}
+	lotPrice := decimal.NewFromInt(opInfo.PosInLot).Mul(opInfo.PosPrice)
+	riskOrder := trmodel.RiskOrder{AccountId: action.AccountID, Figi: action.InstrFigi, Currency: opInfo.Currency,
+		Opening: action.Short, Amount: lotPrice.Mul(decimal.NewFromInt(action.LotAmount))}
+	if !t.checkRisk(&riskOrder, action, sub) {
+		return
+	}
 	//Posting market sell request
 	orderId := uuid.New().String()
 	req := dtotapi.PostOrderRequest{
@@ -336,9 +467,83 @@ func (t *BaseTrader) procSell(opInfo *trmodel.OpInfo, action *entity.Action, sub
 		return
 	}
 	//Populating orders map to further state monitoring and responding
-	t.orders.Put(order.OrderId, action)
+	action.OrderId = order.OrderId
+	t.putOrder(order.OrderId, action)
 	t.logger.Info("Posted sell order ", order)
-	t.setActionStatus(action, entity.Posted, "Sell order successfully posted")
+	t.saveActionWithStatus(action, entity.Posted, "Sell order successfully posted")
+}
+
+//checkRisk passes order through account risk rules, rejects action with the violated rule when order is not allowed
+func (t *BaseTrader) checkRisk(order *trmodel.RiskOrder, action *entity.Action, sub *stmodel.Subscription) bool {
+	if t.riskMgr == nil {
+		return true
+	}
+	if err := t.riskMgr.Check(order, t.ctx); err != nil {
+		t.logger.Warnf("Action %d of algorithm %d rejected by risk manager: %s", action.ID, action.AlgorithmID, err)
+		t.setActionStatus(action, entity.Failed, "Rejected by risk manager: "+err.Error())
+		sub.RChan <- &stmodel.ActionResp{Action: action}
+		return false
+	}
+	return true
+}
+
+//prepareShort calculates amount of short sell by limit if it's not requested and checks that account margin is enough.
+//Returns false if short position may not be opened
+func (t *BaseTrader) prepareShort(opInfo *trmodel.OpInfo, action *entity.Action, sub *stmodel.Subscription) bool {
+	lotPrice := decimal.NewFromInt(opInfo.PosInLot).Mul(opInfo.PosPrice)
+	if action.LotAmount == 0 {
+		if lotPrice.GreaterThan(opInfo.Lim) {
+			t.logger.Warnf("Limit lower than minimal short sell price, figi %s; limit: %s; lot price: %s",
+				action.InstrFigi, opInfo.Lim, lotPrice)
+			t.setActionStatus(action, entity.Failed, "Price of one sell exceeds limit")
+			sub.RChan <- &stmodel.ActionResp{Action: action}
+			return false
+		}
+		action.LotAmount = opInfo.Lim.Div(lotPrice).Floor().IntPart()
+	}
+	attrs, err := t.infoSrv.GetMarginAttributes(&dtotapi.MarginAttributesRequest{AccountId: action.AccountID}, t.ctx)
+	if err != nil {
+		t.logger.Error("Error getting margin attributes ", err)
+		t.setActionStatus(action, entity.Failed, "Error while getting margin attributes")
+		sub.RChan <- &stmodel.ActionResp{Action: action}
+		return false
+	}
+	//Collateral required for position by instrument risk rate, full position price if rate is unknown
+	required := lotPrice.Mul(decimal.NewFromInt(action.LotAmount))
+	if opInfo.ShortRate.IsPositive() {
+		required = required.Mul(opInfo.ShortRate)
+	}
+	if attrs.GetFreeMargin().LessThan(required) {
+		t.logger.Warnf("Not enough margin for short sell of figi %s; lot num: %d; required margin: %s; free margin: %s",
+			action.InstrFigi, action.LotAmount, required, attrs.GetFreeMargin())
+		t.setActionStatus(action, entity.Failed, "Not enough margin for short sell")
+		sub.RChan <- &stmodel.ActionResp{Action: action}
+		return false
+	}
+	return true
+}
+
+//reservedBy returns money reserved in algorithm budget by posted order - estimated price of buy order
+func (t *BaseTrader) reservedBy(action *entity.Action) decimal.Decimal {
+	if action.Direction == entity.Buy {
+		return action.TotalPrice
+	}
+	return decimal.Zero
+}
+
+//settleBudget releases money reserved by completed order and applies executed amount to algorithm budget
+func (t *BaseTrader) settleBudget(action *entity.Action, reserved decimal.Decimal) {
+	if t.budget != nil {
+		t.budget.Settle(action, reserved)
+	}
+}
+
+//GetBudgets returns tracked budgets of algorithm, empty when budgets are not tracked by trader
+func (t *BaseTrader) GetBudgets(algoId uint) []*entity.AlgoBudget {
+	if t.budget == nil {
+		return []*entity.AlgoBudget{}
+	}
+	return t.budget.GetBudgets(algoId)
 }
 
 //normalization required to take into account minimum price step of instrument
@@ -364,6 +569,27 @@ func (t *BaseTrader) setActionStatus(action *entity.Action, status entity.Action
 	}
 }
 
+//Set status and info message to action and persists full action state (order id, amounts etc.) in db
+func (t *BaseTrader) saveActionWithStatus(action *entity.Action, status entity.ActionStatus, msg string) {
+	action.Status = status
+	action.Info = msg
+	if err := t.actionRep.Save(action); err != nil {
+		t.logger.Error("Error while saving action, skipping update...", err)
+	}
+}
+
+//RestoreOrders puts posted orders to the orders map, so they will be checked by background task
+func (t *BaseTrader) RestoreOrders(actions []*entity.Action) {
+	for _, action := range actions {
+		if action.Status != entity.Posted || action.OrderId == "" {
+			t.logger.Warnf("Action %d is not posted order, skipping restore...", action.ID)
+			continue
+		}
+		t.logger.Infof("Restoring order %s of algorithm %d", action.OrderId, action.AlgorithmID)
+		t.putOrder(action.OrderId, action)
+	}
+}
+
 // RemoveSubscription removes algorithm subscription and stop monitoring
 func (t *BaseTrader) RemoveSubscription(id uint) error {
 	t.logger.Infof("Remove subscription for algo with id: %d", id)
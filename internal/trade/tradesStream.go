@@ -0,0 +1,105 @@
+package trade
+
+import (
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto/dtotapi"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/entity"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/env"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/tapigen"
+	"time"
+)
+
+const (
+	pollInterval      = 30 * time.Second //Order state polling interval when trades stream is not connected
+	reconcileInterval = time.Minute      //Order state polling interval with connected trades stream - to reconcile missed trades and expired orders
+)
+
+//putOrder puts posted order to the orders map and starts trades stream of order account if it's not started yet
+func (t *BaseTrader) putOrder(orderId string, action *entity.Action) {
+	t.orders.Put(orderId, action)
+	t.ensureTradesStream(action.AccountID)
+}
+
+//ensureTradesStream starts background consuming of account trades stream, if trader uses streams and it's not started yet
+func (t *BaseTrader) ensureTradesStream(accountId string) {
+	if !t.useStream || t.ctx == nil || accountId == "" {
+		return
+	}
+	t.streamMu.Lock()
+	defer t.streamMu.Unlock()
+	if _, ok := t.streams[accountId]; ok {
+		return
+	}
+	t.streams[accountId] = false
+	go t.tradesStreamBg(accountId)
+}
+
+//startTradesStreams starts trades streams of accounts of already monitored orders
+func (t *BaseTrader) startTradesStreams() {
+	for _, entry := range t.orders.GetSlice() {
+		t.ensureTradesStream(entry.Value.AccountID)
+	}
+}
+
+//tradesStreamBg consumes trades stream of account and checks order state as soon as order trades received.
+//Broken stream is reconnected after retry interval, meanwhile orders are checked by polling
+func (t *BaseTrader) tradesStreamBg(accountId string) {
+	t.logger.Infof("Starting trades stream of account %s...", accountId)
+	for {
+		stream, err := t.tradeSrv.GetTradesStream([]string{accountId}, t.ctx)
+		if err == nil {
+			t.setStreamState(accountId, true)
+			err = t.recvTrades(stream)
+		}
+		t.setStreamState(accountId, false)
+		if t.ctx.Err() != nil {
+			t.logger.Infof("Trades stream of account %s stopped", accountId)
+			return
+		}
+		retryIvl := time.Duration(env.GetRetryMin()) * time.Minute
+		t.logger.Errorf("Trades stream of account %s broken, orders are polled; reconnect in %s: %s", accountId, retryIvl, err)
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-time.After(retryIvl):
+		}
+	}
+}
+
+//recvTrades processes stream messages until stream is broken, returns receive error
+func (t *BaseTrader) recvTrades(stream investapi.OrdersStreamService_TradesStreamClient) error {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		trades := resp.GetOrderTrades()
+		if trades == nil {
+			continue //Ping message
+		}
+		orderTrades := dtotapi.OrderTradesToDto(trades)
+		t.logger.Infof("Received %d trades of order %s", len(orderTrades.Trades), orderTrades.OrderId)
+		//Final order values (average price, commission) are taken from order state
+		t.checkOrder(orderTrades.OrderId)
+	}
+}
+
+func (t *BaseTrader) setStreamState(accountId string, connected bool) {
+	t.streamMu.Lock()
+	defer t.streamMu.Unlock()
+	t.streams[accountId] = connected
+}
+
+//pollInterval returns reconciliation interval when streams of all accounts are connected, else regular polling interval
+func (t *BaseTrader) pollInterval() time.Duration {
+	if !t.useStream {
+		return pollInterval
+	}
+	t.streamMu.Lock()
+	defer t.streamMu.Unlock()
+	for _, connected := range t.streams {
+		if !connected {
+			return pollInterval
+		}
+	}
+	return reconcileInterval
+}
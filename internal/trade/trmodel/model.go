@@ -11,6 +11,17 @@ type OpInfo struct {
 	Lim       decimal.Decimal
 	PriceStep decimal.Decimal
 	Currency  string
+	ShortRate decimal.Decimal  //Margin risk rate of short position (part of position price required as collateral)
+	Budget    *decimal.Decimal //Money left of algorithm budget to open position, nil when budget is not tracked
+}
+
+//RiskOrder describes order passed through account risk rules before posting
+type RiskOrder struct {
+	AccountId string
+	Figi      string
+	Currency  string
+	Opening   bool            //Order increases exposure: buy by limit or short sell; closing orders are not restricted by exposure rules
+	Amount    decimal.Decimal //Money amount of order by last price
 }
 
 type Timed[T any] struct {
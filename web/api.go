@@ -14,6 +14,9 @@ func StartHttp() {
 	historyHandlers(router, dc)
 	tradeHandlers(router, dc)
 	statHandlers(router, dc)
+	strategyHandlers(router, dc)
+	riskHandlers(router, dc)
+	haltHandlers(router, dc)
 
 	log.Fatal(router.Run(fmt.Sprintf(":%s", env.GetSrvPort())))
 }
@@ -24,6 +27,8 @@ func historyHandlers(router *gin.Engine, ctx bot.DependencyContainer) {
 	router.POST("/history/load", hh.LoadHistory)
 	router.POST("/history/analyze", hh.AnalyzeHistory)
 	router.POST("/history/analyze/range", hh.AnalyzeHistoryInRange)
+	router.POST("/history/analyze/range/stream", hh.AnalyzeHistoryInRangeStream)
+	router.POST("/history/analyze/walkforward", hh.AnalyzeWalkForward)
 }
 
 func tradeHandlers(router *gin.Engine, dc bot.DependencyContainer) {
@@ -45,3 +50,24 @@ func statHandlers(router *gin.Engine, dc bot.DependencyContainer) {
 
 	router.GET("/stat/algorithm", st.AlgorithmStat)
 }
+
+func strategyHandlers(router *gin.Engine, dc bot.DependencyContainer) {
+	sh := NewStrategyHandler(dc.GetStrategyAPI(), dc.GetLogger())
+
+	router.GET("/strategies", sh.GetStrategies)
+}
+
+func riskHandlers(router *gin.Engine, dc bot.DependencyContainer) {
+	rh := NewRiskHandler(dc.GetRiskAPI(), dc.GetLogger())
+
+	router.GET("/trade/risk/rules", rh.GetRules)
+	router.POST("/trade/risk/rules", rh.SaveRule)
+	router.DELETE("/trade/risk/rules", rh.DeleteRule)
+}
+
+func haltHandlers(router *gin.Engine, dc bot.DependencyContainer) {
+	hh := NewHaltHandler(dc.GetHaltAPI(), dc.GetLogger())
+
+	router.POST("/trade/halt", hh.Halt)
+	router.POST("/trade/resume", hh.Resume)
+}
@@ -0,0 +1,54 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/bot"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type HaltHandler interface {
+	Halt(c *gin.Context)
+	Resume(c *gin.Context)
+}
+
+type DefaultHaltHandler struct {
+	api    bot.HaltAPI
+	logger *zap.SugaredLogger
+}
+
+func NewHaltHandler(api bot.HaltAPI, logger *zap.SugaredLogger) HaltHandler {
+	return &DefaultHaltHandler{api, logger}
+}
+
+func (h *DefaultHaltHandler) Halt(c *gin.Context) {
+	var req dto.HaltRequest
+	//Empty body halts trading without closing positions
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.logger.Errorf("Error while validating Halt request:\n%s", err)
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	h.logger.Warnf("Trading halt requested: %+v", req)
+	res, err := h.api.Halt(&req)
+	if err != nil {
+		h.logger.Errorf("Error while halting trading:\n%s", err)
+		c.JSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *DefaultHaltHandler) Resume(c *gin.Context) {
+	h.logger.Warn("Trading resume requested")
+	res, err := h.api.Resume()
+	if err != nil {
+		h.logger.Errorf("Error while resuming trading:\n%s", err)
+		c.JSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -1,11 +1,16 @@
 package web
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/bot"
 	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
 	"go.uber.org/zap"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 )
 
@@ -13,6 +18,8 @@ type HistoryHandler interface {
 	LoadHistory(c *gin.Context)
 	AnalyzeHistory(c *gin.Context)
 	AnalyzeHistoryInRange(c *gin.Context)
+	AnalyzeHistoryInRangeStream(c *gin.Context)
+	AnalyzeWalkForward(c *gin.Context)
 }
 
 type DefaultHistoryHandler struct {
@@ -74,8 +81,103 @@ func (h *DefaultHistoryHandler) AnalyzeHistoryInRange(c *gin.Context) {
 	stat, err := h.api.AnalyzeAlgoInRange(&req, c.Request.Context())
 	if err != nil {
 		h.logger.Errorf("Error while analyzing history:\n%s", err)
-		c.JSON(http.StatusInternalServerError, err.Error())
+		c.JSON(errStatus(err), err.Error())
+		return
+	}
+	if req.GridFormat == bot.GridCsv && stat != nil {
+		data, err := gridToCsv(stat.Grid)
+		if err != nil {
+			h.logger.Errorf("Error while writing grid csv:\n%s", err)
+			c.JSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Data(http.StatusOK, "text/csv", data)
+		return
+	}
+	c.JSON(http.StatusOK, stat)
+}
+
+//AnalyzeHistoryInRangeStream writes result of each analyzed parameter set as JSON line when it finishes,
+//the last line contains final result or error
+func (h *DefaultHistoryHandler) AnalyzeHistoryInRangeStream(c *gin.Context) {
+	var req dto.CreateAlgorithmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Errorf("Error while validating AnalyzeAlgo request:\n%s", err)
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	h.logger.Infof("Analyze history with streaming: %+v", req)
+	enc := json.NewEncoder(c.Writer)
+	write := func(progress *dto.RangeProgress) {
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "application/x-ndjson")
+		}
+		if err := enc.Encode(progress); err != nil {
+			h.logger.Errorf("Error while writing range analysis progress:\n%s", err)
+			return
+		}
+		c.Writer.Flush()
+	}
+	stat, err := h.api.AnalyzeAlgoInRangeStream(&req, write, c.Request.Context())
+	if err != nil {
+		h.logger.Errorf("Error while analyzing history:\n%s", err)
+		if !c.Writer.Written() {
+			c.JSON(errStatus(err), err.Error())
+			return
+		}
+		write(&dto.RangeProgress{Error: err.Error()})
+		return
+	}
+	write(&dto.RangeProgress{Result: stat})
+}
+
+func (h *DefaultHistoryHandler) AnalyzeWalkForward(c *gin.Context) {
+	var req dto.WalkForwardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Errorf("Error while validating AnalyzeWalkForward request:\n%s", err)
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	h.logger.Infof("Walk-forward analysis: %+v", req)
+	stat, err := h.api.AnalyzeWalkForward(&req, c.Request.Context())
+	if err != nil {
+		h.logger.Errorf("Error while walk-forward analysis:\n%s", err)
+		c.JSON(errStatus(err), err.Error())
 		return
 	}
 	c.JSON(http.StatusOK, stat)
 }
+
+//gridToCsv writes results of range analysis as csv table: parameter columns sorted by name followed by result columns
+func gridToCsv(grid []*dto.GridRow) ([]byte, error) {
+	paramSet := make(map[string]bool)
+	for _, row := range grid {
+		for key := range row.Params {
+			paramSet[key] = true
+		}
+	}
+	params := make([]string, 0, len(paramSet))
+	for key := range paramSet {
+		params = append(params, key)
+	}
+	sort.Strings(params)
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := append(append([]string{}, params...), "score", "valid", "balance", "tradeNum", "sharpe", "maxDrawdownPct")
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, row := range grid {
+		record := make([]string, 0, len(header))
+		for _, key := range params {
+			record = append(record, row.Params[key])
+		}
+		record = append(record, row.Score.String(), strconv.FormatBool(row.Valid), row.Balance.String(),
+			strconv.Itoa(row.TradeNum), row.Sharpe.String(), row.MaxDrawdownPct.String())
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
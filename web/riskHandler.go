@@ -0,0 +1,85 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/bot"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/dto"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/errors"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type RiskHandler interface {
+	GetRules(c *gin.Context)
+	SaveRule(c *gin.Context)
+	DeleteRule(c *gin.Context)
+}
+
+type DefaultRiskHandler struct {
+	api    bot.RiskAPI
+	logger *zap.SugaredLogger
+}
+
+func NewRiskHandler(api bot.RiskAPI, logger *zap.SugaredLogger) RiskHandler {
+	return &DefaultRiskHandler{api, logger}
+}
+
+func (h *DefaultRiskHandler) GetRules(c *gin.Context) {
+	var req dto.RiskRulesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Errorf("Error while validating RiskRules request:\n%s", err)
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	rules, err := h.api.GetRules(&req)
+	if err != nil {
+		h.logger.Errorf("Error retrieving risk rules:\n%s", err)
+		c.JSON(errStatus(err), err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *DefaultRiskHandler) SaveRule(c *gin.Context) {
+	var req dto.RiskRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Errorf("Error while validating RiskRule request:\n%s", err)
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	h.logger.Infof("Save risk rule: %+v", req)
+	rule, err := h.api.SaveRule(&req)
+	if err != nil {
+		h.logger.Errorf("Error saving risk rule:\n%s", err)
+		c.JSON(errStatus(err), err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *DefaultRiskHandler) DeleteRule(c *gin.Context) {
+	var req dto.DeleteRiskRuleRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Errorf("Error while validating DeleteRiskRule request:\n%s", err)
+		c.JSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	h.logger.Infof("Delete risk rule: %+v", req)
+	if err := h.api.DeleteRule(&req); err != nil {
+		h.logger.Errorf("Error deleting risk rule:\n%s", err)
+		c.JSON(errStatus(err), err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, "Deleted successfully")
+}
+
+//errStatus returns http status by error type
+func errStatus(err error) int {
+	switch err.(type) {
+	case errors.ValidationErr:
+		return http.StatusBadRequest
+	case errors.NotFoundErr:
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,32 @@
+package web
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ldmi3i/tinkoff-invest-bot/internal/bot"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type StrategyHandler interface {
+	GetStrategies(c *gin.Context)
+}
+
+type DefaultStrategyHandler struct {
+	api    bot.StrategyAPI
+	logger *zap.SugaredLogger
+}
+
+func NewStrategyHandler(api bot.StrategyAPI, logger *zap.SugaredLogger) StrategyHandler {
+	return &DefaultStrategyHandler{api, logger}
+}
+
+func (h *DefaultStrategyHandler) GetStrategies(c *gin.Context) {
+	h.logger.Info("Strategies list requested")
+	strategies, err := h.api.GetStrategies()
+	if err != nil {
+		h.logger.Errorf("Error retrieving strategies:\n%s", err)
+		c.JSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, strategies)
+}
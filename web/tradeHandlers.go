@@ -64,7 +64,7 @@ func (h *DefaultTradeHandler) trade(c *gin.Context, api bot.TradeAPI) {
 	stat, err := api.Trade(&req, context.Background())
 	if err != nil {
 		h.logger.Errorf("Error while analyzing history:\n%s", err)
-		c.JSON(http.StatusInternalServerError, err.Error())
+		c.JSON(errStatus(err), err.Error())
 		return
 	}
 	c.JSON(http.StatusOK, stat)